@@ -0,0 +1,202 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/signer"
+	"github.com/elliottech/lighter-go/types"
+)
+
+const (
+	// DefaultAuthTokenLifetime is how long tokens created by the AuthTokenProvider are valid for.
+	// Lighter accepts auth tokens with a deadline of at most 7 hours.
+	DefaultAuthTokenLifetime = time.Hour * 6
+	// DefaultAuthTokenRefreshBefore is how long before the deadline a cached token is replaced.
+	// Tokens returned by the provider are always valid for at least this long.
+	DefaultAuthTokenRefreshBefore = time.Hour
+
+	authTokenRefreshInterval = time.Minute
+)
+
+type authTokenKey struct {
+	accountIndex int64
+	apiKeyIndex  uint8
+}
+
+// cachedAuthToken keeps the key it was created with, as the TxClient can switch to another API key afterwards
+type cachedAuthToken struct {
+	key        authTokenKey
+	keyManager signer.KeyManager
+	clock      Clock
+	token      string
+	deadline   time.Time
+}
+
+// AuthTokenProvider caches auth tokens per (account, apiKey), so REST and WebSocket clients
+// don't sign a new token for every request. Tokens are refreshed when they get close to their deadline,
+// either lazily by Token, or ahead of time by a background goroutine started with Start.
+// The provider is safe for concurrent use, and can be shared by multiple TxClients.
+type AuthTokenProvider struct {
+	lifetime      time.Duration
+	refreshBefore time.Duration
+
+	mu     sync.Mutex
+	tokens map[authTokenKey]*cachedAuthToken
+	stop   chan struct{}
+	done   chan struct{}
+}
+
+// NewAuthTokenProvider creates a provider for tokens valid for lifetime, refreshed refreshBefore their deadline
+func NewAuthTokenProvider(lifetime, refreshBefore time.Duration) (*AuthTokenProvider, error) {
+	if lifetime > 7*time.Hour {
+		return nil, fmt.Errorf("lifetime should be within 7 hours")
+	}
+	if refreshBefore <= 0 || refreshBefore >= lifetime {
+		return nil, fmt.Errorf("refreshBefore should be positive and lower than lifetime")
+	}
+
+	return &AuthTokenProvider{
+		lifetime:      lifetime,
+		refreshBefore: refreshBefore,
+		tokens:        make(map[authTokenKey]*cachedAuthToken),
+	}, nil
+}
+
+func newDefaultAuthTokenProvider() *AuthTokenProvider {
+	return &AuthTokenProvider{
+		lifetime:      DefaultAuthTokenLifetime,
+		refreshBefore: DefaultAuthTokenRefreshBefore,
+		tokens:        make(map[authTokenKey]*cachedAuthToken),
+	}
+}
+
+// Token returns a token for the (account, apiKey) of txClient, valid for at least refreshBefore.
+// Tokens are signed without holding the lock, so a slow KeyProvider doesn't block the tokens of other accounts.
+func (p *AuthTokenProvider) Token(txClient *TxClient) (string, error) {
+	key := authTokenKey{accountIndex: txClient.GetAccountIndex(), apiKeyIndex: txClient.GetApiKeyIndex()}
+
+	p.mu.Lock()
+	cached, ok := p.tokens[key]
+	if ok && cached.deadline.Sub(txClient.Now()) > p.refreshBefore {
+		p.mu.Unlock()
+		return cached.token, nil
+	}
+	p.mu.Unlock()
+
+	cached = &cachedAuthToken{key: key, keyManager: txClient.GetKeyManager(), clock: txClient}
+	token, deadline, err := p.sign(cached)
+	if err != nil {
+		return "", err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	cached.token, cached.deadline = token, deadline
+	p.tokens[key] = cached
+	return token, nil
+}
+
+// Invalidate drops the cached token of (accountIndex, apiKeyIndex), e.g. after the API key was changed
+func (p *AuthTokenProvider) Invalidate(accountIndex int64, apiKeyIndex uint8) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	delete(p.tokens, authTokenKey{accountIndex: accountIndex, apiKeyIndex: apiKeyIndex})
+}
+
+// Start refreshes all cached tokens in the background, before they need to be refreshed by Token.
+// onError is called from the background goroutine for every failed refresh, and can be nil.
+func (p *AuthTokenProvider) Start(onError func(error)) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return fmt.Errorf("auth token provider is already running")
+	}
+	p.stop = make(chan struct{})
+	p.done = make(chan struct{})
+	go p.run(p.stop, p.done, onError)
+	return nil
+}
+
+// Stop stops the background refresh. Cached tokens are kept.
+func (p *AuthTokenProvider) Stop() {
+	p.mu.Lock()
+	stop, done := p.stop, p.done
+	p.stop, p.done = nil, nil
+	p.mu.Unlock()
+
+	if stop == nil {
+		return
+	}
+	close(stop)
+	<-done
+}
+
+func (p *AuthTokenProvider) run(stop <-chan struct{}, done chan<- struct{}, onError func(error)) {
+	defer close(done)
+
+	ticker := time.NewTicker(authTokenRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			errs := p.refreshDue()
+			if onError != nil {
+				for _, err := range errs {
+					onError(err)
+				}
+			}
+		}
+	}
+}
+
+// refreshDue refreshes the tokens which would need to be refreshed by Token before the next tick
+func (p *AuthTokenProvider) refreshDue() []error {
+	p.mu.Lock()
+	var due []*cachedAuthToken
+	for _, cached := range p.tokens {
+		// refresh one interval early, so Token never has to
+		if cached.deadline.Sub(cached.clock.Now()) <= p.refreshBefore+authTokenRefreshInterval {
+			due = append(due, cached)
+		}
+	}
+	p.mu.Unlock()
+
+	var errs []error
+	for _, cached := range due {
+		token, deadline, err := p.sign(cached)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		p.mu.Lock()
+		// the token was invalidated, or replaced by Token, while it was signed
+		if p.tokens[cached.key] == cached {
+			cached.token, cached.deadline = token, deadline
+		}
+		p.mu.Unlock()
+	}
+	return errs
+}
+
+// sign signs a new token with the key the cached token was created for. It's called without holding the lock,
+// and only reads the fields of cached which never change.
+func (p *AuthTokenProvider) sign(cached *cachedAuthToken) (string, time.Time, error) {
+	deadline := cached.clock.Now().Add(p.lifetime)
+	token, err := types.ConstructAuthToken(cached.keyManager, deadline, &types.TransactOpts{
+		FromAccountIndex: &cached.key.accountIndex,
+		ApiKeyIndex:      &cached.key.apiKeyIndex,
+	})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to create auth token for account %d api key %d. err: %w", cached.key.accountIndex, cached.key.apiKeyIndex, err)
+	}
+	return token, deadline, nil
+}
@@ -0,0 +1,220 @@
+package client
+
+import (
+	"errors"
+	"hash"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elliottech/lighter-go/signer"
+)
+
+// parseAuthToken returns the deadline, account & api key signed in an auth token
+func parseAuthToken(t *testing.T, token string) (time.Time, string, string) {
+	t.Helper()
+
+	parts := strings.Split(token, ":")
+	if len(parts) != 4 {
+		t.Fatalf("invalid auth token %s", token)
+	}
+	deadline, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return time.Unix(deadline, 0), parts[1], parts[2]
+}
+
+func newAuthTokenTxClient(t *testing.T) (*TxClient, *FakeClock) {
+	t.Helper()
+
+	c := newBatchTxClient(t)
+	clock := NewFakeClock(time.Unix(1767225600, 0))
+	c.SetClock(clock)
+	return c, clock
+}
+
+func TestAuthTokenProviderToken(t *testing.T) {
+	c, clock := newAuthTokenTxClient(t)
+
+	token, err := c.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadline, account, apiKey := parseAuthToken(t, token)
+	if !deadline.Equal(clock.Now().Add(DefaultAuthTokenLifetime)) || account != "100" || apiKey != "3" {
+		t.Fatalf("unexpected auth token %s", token)
+	}
+
+	// the token is reused while it's valid for more than DefaultAuthTokenRefreshBefore
+	clock.Advance(DefaultAuthTokenLifetime - DefaultAuthTokenRefreshBefore - time.Second)
+	if cached, err := c.GetCachedAuthToken(); err != nil || cached != token {
+		t.Fatalf("expected the cached token, got %s %v", cached, err)
+	}
+
+	clock.Advance(2 * time.Second)
+	refreshed, err := c.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deadline, _, _ := parseAuthToken(t, refreshed); !deadline.Equal(clock.Now().Add(DefaultAuthTokenLifetime)) {
+		t.Fatalf("expected a refreshed token, got %s", refreshed)
+	}
+
+	c.AuthTokenProvider().Invalidate(100, 3)
+	clock.Advance(time.Second)
+	if token, _ := c.GetCachedAuthToken(); token == refreshed {
+		t.Fatal("expected a new token after Invalidate")
+	}
+}
+
+func TestAuthTokenProviderRefreshDue(t *testing.T) {
+	c, clock := newAuthTokenTxClient(t)
+	p := c.AuthTokenProvider()
+
+	token, err := c.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// tokens are refreshed one interval before Token would refresh them
+	clock.Advance(DefaultAuthTokenLifetime - DefaultAuthTokenRefreshBefore - authTokenRefreshInterval - time.Second)
+	if errs := p.refreshDue(); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	if cached, _ := c.GetCachedAuthToken(); cached != token {
+		t.Fatal("expected the token not to be refreshed yet")
+	}
+
+	// the client switches to another api key before the refresh
+	c.SwitchAPIKey(4)
+	clock.Advance(2 * time.Second)
+	if errs := p.refreshDue(); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+	p.mu.Lock()
+	refreshed := p.tokens[authTokenKey{accountIndex: 100, apiKeyIndex: 3}].token
+	p.mu.Unlock()
+	deadline, account, apiKey := parseAuthToken(t, refreshed)
+	if !deadline.Equal(clock.Now().Add(DefaultAuthTokenLifetime)) || account != "100" || apiKey != "3" {
+		t.Fatalf("expected a refreshed token for api key 3, got %s", refreshed)
+	}
+
+	token, err = c.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, apiKey := parseAuthToken(t, token); apiKey != "4" {
+		t.Fatalf("expected a token for api key 4, got %s", token)
+	}
+}
+
+func TestAuthTokenProviderShared(t *testing.T) {
+	p, err := NewAuthTokenProvider(2*time.Hour, 30*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := newAuthTokenTxClient(t)
+	second, err := NewTxClient(nil, batchPrivateKey, 101, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second.SetClock(first.clock)
+	first.SetAuthTokenProvider(p)
+	second.SetAuthTokenProvider(p)
+
+	firstToken, err := first.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	secondToken, err := second.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deadline, account, _ := parseAuthToken(t, secondToken); account != "101" || !deadline.Equal(first.Now().Add(2*time.Hour)) {
+		t.Fatalf("unexpected token %s", secondToken)
+	}
+	if _, account, _ := parseAuthToken(t, firstToken); account != "100" {
+		t.Fatalf("unexpected token %s", firstToken)
+	}
+
+	if _, err := NewAuthTokenProvider(8*time.Hour, time.Hour); err == nil {
+		t.Error("expected an error for a lifetime over 7 hours")
+	}
+	if _, err := NewAuthTokenProvider(time.Hour, time.Hour); err == nil {
+		t.Error("expected an error for refreshBefore not lower than lifetime")
+	}
+}
+
+// blockingKeyManager waits for release before signing, like a KeyProvider showing a biometric prompt
+type blockingKeyManager struct {
+	signer.KeyManager
+	signing chan struct{}
+	release chan struct{}
+}
+
+func (k *blockingKeyManager) Sign(hashedMessage []byte, hFunc hash.Hash) ([]byte, error) {
+	k.signing <- struct{}{}
+	<-k.release
+	return k.KeyManager.Sign(hashedMessage, hFunc)
+}
+
+func TestAuthTokenProviderSlowKey(t *testing.T) {
+	fast, clock := newAuthTokenTxClient(t)
+	p := fast.AuthTokenProvider()
+	slowKey := &blockingKeyManager{KeyManager: fast.GetKeyManager(), signing: make(chan struct{}), release: make(chan struct{})}
+	slow := NewTxClientWithKeyManager(nil, slowKey, 101, 3, 304)
+	slow.SetClock(clock)
+	slow.SetAuthTokenProvider(p)
+
+	// waitFast checks the token of another account doesn't wait for the slow key
+	waitFast := func() {
+		t.Helper()
+
+		done := make(chan error, 1)
+		go func() {
+			_, err := fast.GetCachedAuthToken()
+			done <- err
+		}()
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("the token of another account waited for the slow key")
+		}
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := slow.GetCachedAuthToken()
+		done <- err
+	}()
+	<-slowKey.signing
+	waitFast()
+	slowKey.release <- struct{}{}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(DefaultAuthTokenLifetime - DefaultAuthTokenRefreshBefore)
+	go func() {
+		errs := p.refreshDue()
+		done <- errors.Join(errs...)
+	}()
+	<-slowKey.signing
+	waitFast()
+	slowKey.release <- struct{}{}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	token, err := slow.GetCachedAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deadline, _, _ := parseAuthToken(t, token); !deadline.Equal(clock.Now().Add(DefaultAuthTokenLifetime)) {
+		t.Fatalf("expected the token refreshed by refreshDue, got %s", token)
+	}
+}
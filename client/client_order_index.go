@@ -0,0 +1,126 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+const (
+	// AutoClientOrderIndex can be passed as CreateOrderTxReq.ClientOrderIndex to let the TxClient allocate one.
+	AutoClientOrderIndex int64 = -1
+
+	// MaxClientOrderIndexTagBits is the maximum number of high bits of a ClientOrderIndex which can be used for a strategy tag.
+	// The remaining (at least 40) bits hold a millisecond based sequence, which lasts for ~34 years after clientOrderIndexEpoch.
+	MaxClientOrderIndexTagBits uint8 = 8
+
+	clientOrderIndexBits        = 48
+	clientOrderIndexEpoch int64 = 1735689600000 // 2025-01-01 00:00:00 UTC, in milliseconds
+)
+
+// ClientOrderIDAllocator generates ClientOrderIndex values which are unique for an account.
+// Each index is made of an optional strategy tag in the high bits, followed by a sequence.
+// The sequence is the number of milliseconds since 2025-01-01, but it's always incremented
+// by at least one, so multiple orders created in the same millisecond don't collide.
+//
+// As the sequence is time based, indexes don't collide after a restart as long as the previous process
+// didn't allocate ahead of the clock. To be fully safe, persist Last() and pass it to Restore on startup.
+// The allocator is safe for concurrent use.
+type ClientOrderIDAllocator struct {
+	mu      sync.Mutex
+	tagBits uint8
+	tag     int64
+	last    int64 // last allocated sequence, without the tag
+}
+
+// NewClientOrderIDAllocator creates an allocator which encodes tag in the tagBits high bits of each index.
+// Use tagBits = 0 if no tag is needed.
+func NewClientOrderIDAllocator(tagBits uint8, tag int64) (*ClientOrderIDAllocator, error) {
+	if tagBits > MaxClientOrderIndexTagBits {
+		return nil, fmt.Errorf("tagBits should not be larger than %d", MaxClientOrderIndexTagBits)
+	}
+	if tag < 0 || tag >= int64(1)<<tagBits {
+		return nil, fmt.Errorf("tag should be between 0 and %d", int64(1)<<tagBits-1)
+	}
+
+	return &ClientOrderIDAllocator{
+		tagBits: tagBits,
+		tag:     tag,
+	}, nil
+}
+
+// newAPIKeyClientOrderIDAllocator is the default allocator of a TxClient, which uses the API key index as tag,
+// so clients using different API keys of the same account never collide. Clients sharing an API key have to
+// coordinate their nonces already, and should share one allocator too.
+func newAPIKeyClientOrderIDAllocator(apiKeyIndex uint8) *ClientOrderIDAllocator {
+	return &ClientOrderIDAllocator{
+		tagBits: MaxClientOrderIndexTagBits,
+		tag:     int64(apiKeyIndex),
+	}
+}
+
+func (a *ClientOrderIDAllocator) sequenceBits() uint8 {
+	return clientOrderIndexBits - a.tagBits
+}
+
+// Next returns a new ClientOrderIndex, in the [MinClientOrderIndex, MaxClientOrderIndex] range.
+func (a *ClientOrderIDAllocator) Next() (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	seq := time.Now().UnixMilli() - clientOrderIndexEpoch
+	if seq <= a.last {
+		seq = a.last + 1
+	}
+	if seq >= int64(1)<<a.sequenceBits() {
+		return 0, fmt.Errorf("client order index sequence exhausted for %d tag bits", a.tagBits)
+	}
+	a.last = seq
+
+	return a.encode(seq), nil
+}
+
+// Last returns the last allocated ClientOrderIndex, or NilClientOrderIndex if none was allocated yet.
+func (a *ClientOrderIDAllocator) Last() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.last == 0 {
+		return txtypes.NilClientOrderIndex
+	}
+	return a.encode(a.last)
+}
+
+// Restore makes sure the allocator never returns indexes lower or equal to lastClientOrderIndex,
+// which should be a value previously returned by Last(), potentially from another process.
+func (a *ClientOrderIDAllocator) Restore(lastClientOrderIndex int64) error {
+	if lastClientOrderIndex == txtypes.NilClientOrderIndex {
+		return nil
+	}
+	if lastClientOrderIndex < txtypes.MinClientOrderIndex || lastClientOrderIndex > txtypes.MaxClientOrderIndex {
+		return fmt.Errorf("invalid client order index %d", lastClientOrderIndex)
+	}
+	if tag := a.Tag(lastClientOrderIndex); tag != a.tag {
+		return fmt.Errorf("client order index %d has tag %d, expected %d", lastClientOrderIndex, tag, a.tag)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	seq := lastClientOrderIndex & (int64(1)<<a.sequenceBits() - 1)
+	if seq > a.last {
+		a.last = seq
+	}
+	return nil
+}
+
+// Tag returns the strategy tag encoded in clientOrderIndex, using the tag layout of this allocator.
+func (a *ClientOrderIDAllocator) Tag(clientOrderIndex int64) int64 {
+	return clientOrderIndex >> a.sequenceBits()
+}
+
+func (a *ClientOrderIDAllocator) encode(seq int64) int64 {
+	return a.tag<<a.sequenceBits() | seq
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+func TestClientOrderIDAllocatorMonotonic(t *testing.T) {
+	a, err := NewClientOrderIDAllocator(0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if last := a.Last(); last != txtypes.NilClientOrderIndex {
+		t.Fatalf("expected no last index, got %d", last)
+	}
+
+	// many indexes are allocated in the same millisecond
+	prev := int64(0)
+	for i := 0; i < 10000; i++ {
+		index, err := a.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if index <= prev || index < txtypes.MinClientOrderIndex || index > txtypes.MaxClientOrderIndex {
+			t.Fatalf("index %d allocated after %d", index, prev)
+		}
+		prev = index
+	}
+	if last := a.Last(); last != prev {
+		t.Fatalf("expected last index %d, got %d", prev, last)
+	}
+}
+
+func TestClientOrderIDAllocatorTag(t *testing.T) {
+	if _, err := NewClientOrderIDAllocator(MaxClientOrderIndexTagBits+1, 0); err == nil {
+		t.Error("expected an error for too many tag bits")
+	}
+	if _, err := NewClientOrderIDAllocator(4, 16); err == nil {
+		t.Error("expected an error for a tag which doesn't fit the tag bits")
+	}
+
+	a, err := NewClientOrderIDAllocator(4, 11)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index, err := a.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := a.Tag(index); tag != 11 {
+		t.Fatalf("expected tag 11, got %d", tag)
+	}
+	if index > txtypes.MaxClientOrderIndex {
+		t.Fatalf("index %d doesn't fit in 48 bits", index)
+	}
+
+	// the default allocator of a TxClient is tagged with its API key index
+	c := newBatchTxClient(t)
+	index, err = c.ClientOrderIDAllocator().Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tag := c.ClientOrderIDAllocator().Tag(index); tag != int64(c.GetApiKeyIndex()) {
+		t.Fatalf("expected tag %d, got %d", c.GetApiKeyIndex(), tag)
+	}
+
+	// and follows the API key the client switches to
+	c.SwitchAPIKey(5)
+	if index, err = c.ClientOrderIDAllocator().Next(); err != nil {
+		t.Fatal(err)
+	}
+	if tag := c.ClientOrderIDAllocator().Tag(index); tag != 5 {
+		t.Fatalf("expected tag 5 after switching the API key, got %d", tag)
+	}
+
+	// an allocator set by the app is kept
+	custom, err := NewClientOrderIDAllocator(4, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetClientOrderIDAllocator(custom)
+	c.SwitchAPIKey(6)
+	if c.ClientOrderIDAllocator() != custom {
+		t.Fatal("SwitchAPIKey replaced the allocator set with SetClientOrderIDAllocator")
+	}
+}
+
+func TestClientOrderIDAllocatorRestore(t *testing.T) {
+	a, err := NewClientOrderIDAllocator(8, 255)
+	if err != nil {
+		t.Fatal(err)
+	}
+	maxSequence := int64(1)<<(clientOrderIndexBits-8) - 1
+
+	// a value allocated far ahead of the clock, e.g. by a previous process
+	ahead := int64(255)<<(clientOrderIndexBits-8) | (maxSequence - 1)
+	if err := a.Restore(ahead); err != nil {
+		t.Fatal(err)
+	}
+	if last := a.Last(); last != ahead {
+		t.Fatalf("expected last index %d, got %d", ahead, last)
+	}
+	index, err := a.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if index != ahead+1 || index != txtypes.MaxClientOrderIndex {
+		t.Fatalf("expected index %d, got %d", ahead+1, index)
+	}
+	if _, err := a.Next(); err == nil {
+		t.Fatal("expected the sequence to be exhausted")
+	}
+
+	// restoring a lower value doesn't move the sequence back
+	if err := a.Restore(ahead - 10); err != nil {
+		t.Fatal(err)
+	}
+	if last := a.Last(); last != txtypes.MaxClientOrderIndex {
+		t.Fatalf("expected last index %d, got %d", txtypes.MaxClientOrderIndex, last)
+	}
+
+	if err := a.Restore(1); err == nil {
+		t.Error("expected an error for an index with another tag")
+	}
+	if err := a.Restore(txtypes.MaxClientOrderIndex + 1); err == nil {
+		t.Error("expected an error for an index larger than 48 bits")
+	}
+	if err := a.Restore(txtypes.NilClientOrderIndex); err != nil {
+		t.Errorf("expected nil index to be ignored, got %v", err)
+	}
+}
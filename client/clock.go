@@ -0,0 +1,108 @@
+package client
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is the source of time for all timestamps sent to Lighter: tx ExpiredAt, order expiries & auth token deadlines.
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock uses the local clock, as is
+var SystemClock Clock = systemClock{}
+
+// ServerClock follows the Lighter server clock, by applying an estimated offset to the local clock.
+// The offset is estimated from the Date header of HTTP responses. As the header has a 1 second resolution,
+// every response bounds the offset to an interval, and the intersection of these intervals gets narrower
+// with each response. If the local clock jumps, the intervals stop intersecting and the estimation restarts.
+// The clock is safe for concurrent use.
+type ServerClock struct {
+	mu     sync.RWMutex
+	synced bool
+	lo, hi time.Duration // bounds of the offset between the server & local clocks
+}
+
+func NewServerClock() *ServerClock {
+	return &ServerClock{}
+}
+
+// Now returns the estimated server time, or the local time if no response was observed yet
+func (c *ServerClock) Now() time.Time {
+	return time.Now().Add(c.Offset())
+}
+
+// Offset returns the estimated difference between the server & local clocks
+func (c *ServerClock) Offset() time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if !c.synced {
+		return 0
+	}
+	return (c.lo + c.hi) / 2
+}
+
+// Synced returns true once at least one server time was observed
+func (c *ServerClock) Synced() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.synced
+}
+
+// Observe records a server time with a resolution of 1 second, like the HTTP Date header,
+// from a response to a request sent at localSent and received at localReceived.
+func (c *ServerClock) Observe(serverTime, localSent, localReceived time.Time) {
+	// the server time is in [serverTime, serverTime + 1s), at some point in [localSent, localReceived]
+	lo := serverTime.Sub(localReceived)
+	hi := serverTime.Add(time.Second).Sub(localSent)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.synced && lo <= c.hi && hi >= c.lo {
+		c.lo, c.hi = max(c.lo, lo), min(c.hi, hi)
+		return
+	}
+	c.synced = true
+	c.lo, c.hi = lo, hi
+}
+
+// FakeClock is a manually driven Clock, meant for tests
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = now
+}
+
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+}
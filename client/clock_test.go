@@ -0,0 +1,98 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+var clockTestLocal = time.Unix(1767225600, 0)
+
+// observe records a response to a request sent at local + sent, handled by the server at local + handled,
+// and received at local + received, by a server which is offset ahead of the local clock
+func observe(c *ServerClock, offset, sent, handled, received time.Duration) {
+	serverTime := clockTestLocal.Add(handled + offset).Truncate(time.Second)
+	c.Observe(serverTime, clockTestLocal.Add(sent), clockTestLocal.Add(received))
+}
+
+func checkOffset(t *testing.T, c *ServerClock, offset time.Duration) {
+	t.Helper()
+
+	if !c.Synced() {
+		t.Fatal("expected the clock to be synced")
+	}
+	if c.lo > offset || c.hi < offset {
+		t.Fatalf("offset %v not within the estimated bounds [%v, %v]", offset, c.lo, c.hi)
+	}
+	if diff := c.Offset() - offset; diff < -time.Second || diff > time.Second {
+		t.Fatalf("estimated offset %v too far from %v", c.Offset(), offset)
+	}
+}
+
+func TestServerClockObserve(t *testing.T) {
+	const offset = 10*time.Second + 300*time.Millisecond
+
+	c := NewServerClock()
+	if c.Synced() || c.Offset() != 0 {
+		t.Fatal("expected no offset before the first response")
+	}
+
+	observe(c, offset, 0, 100*time.Millisecond, 200*time.Millisecond)
+	checkOffset(t, c, offset)
+	width := c.hi - c.lo
+	if width > time.Second+200*time.Millisecond {
+		t.Fatalf("bounds [%v, %v] wider than the resolution and the round trip", c.lo, c.hi)
+	}
+
+	// responses at other fractions of a second narrow the bounds
+	for i := 1; i < 10; i++ {
+		at := time.Duration(i) * 1100 * time.Millisecond
+		observe(c, offset, at, at+50*time.Millisecond, at+100*time.Millisecond)
+		checkOffset(t, c, offset)
+	}
+	if c.hi-c.lo >= width || c.hi-c.lo > 200*time.Millisecond {
+		t.Fatalf("expected narrower bounds than %v, got [%v, %v]", width, c.lo, c.hi)
+	}
+
+	// a late response, with a long round trip, doesn't widen the bounds
+	lo, hi := c.lo, c.hi
+	observe(c, offset, 20*time.Second, 22*time.Second, 25*time.Second)
+	if c.lo != lo || c.hi != hi {
+		t.Fatalf("bounds changed from [%v, %v] to [%v, %v] by a late response", lo, hi, c.lo, c.hi)
+	}
+}
+
+func TestServerClockOutOfOrder(t *testing.T) {
+	const offset = -3*time.Second - 700*time.Millisecond
+
+	type response struct{ sent, handled, received time.Duration }
+	responses := []response{
+		{0, 300 * time.Millisecond, 900 * time.Millisecond},
+		{1200 * time.Millisecond, 1250 * time.Millisecond, 1300 * time.Millisecond},
+		{2500 * time.Millisecond, 2900 * time.Millisecond, 3100 * time.Millisecond},
+		{4000 * time.Millisecond, 4650 * time.Millisecond, 4700 * time.Millisecond},
+	}
+
+	inOrder, reversed := NewServerClock(), NewServerClock()
+	for i := range responses {
+		r, late := responses[i], responses[len(responses)-1-i]
+		observe(inOrder, offset, r.sent, r.handled, r.received)
+		observe(reversed, offset, late.sent, late.handled, late.received)
+	}
+	checkOffset(t, inOrder, offset)
+	if inOrder.lo != reversed.lo || inOrder.hi != reversed.hi {
+		t.Fatalf("bounds depend on the order of the responses: [%v, %v] and [%v, %v]", inOrder.lo, inOrder.hi, reversed.lo, reversed.hi)
+	}
+}
+
+func TestServerClockJump(t *testing.T) {
+	c := NewServerClock()
+	for i := 0; i < 5; i++ {
+		at := time.Duration(i) * 1300 * time.Millisecond
+		observe(c, 2*time.Second, at, at+10*time.Millisecond, at+20*time.Millisecond)
+	}
+	checkOffset(t, c, 2*time.Second)
+
+	// the local clock jumps back by 1 minute, so the offset grows by as much
+	observe(c, 62*time.Second, 10*time.Second, 10*time.Second+10*time.Millisecond, 10*time.Second+20*time.Millisecond)
+	checkOffset(t, c, 62*time.Second)
+}
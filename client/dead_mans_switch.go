@@ -0,0 +1,168 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// DeadMansSwitch keeps a scheduled cancel all armed while the process is alive.
+// Every interval, it moves the scheduled cancel all to now + window. If the process dies,
+// the exchange cancels all resting orders of the account once the window passes.
+// On a clean shutdown, Stop aborts the scheduled cancel all, so the orders are kept.
+//
+// Each re-arm is a tx. The nonce is fetched from the API once, then incremented locally after every accepted tx,
+// and fetched again after a failed send. As the nonce is per API key, the TxClient should use a dedicated API key,
+// so the switch doesn't race the trading nonces.
+type DeadMansSwitch struct {
+	txClient *TxClient
+	window   time.Duration
+	interval time.Duration
+	onError  func(error)
+
+	mu         sync.Mutex
+	stop       chan struct{}
+	done       chan struct{}
+	armedUntil time.Time
+	nonce      int64 // next nonce of the API key, or -1 if it has to be fetched
+}
+
+// NewDeadMansSwitch creates a switch which cancels all orders window after the last successful re-arm.
+// window should be between MinOrderCancelAllPeriod and MaxOrderCancelAllPeriod, and interval should be lower than window,
+// e.g. 1 minute for a 5 minutes window. onError is called from the background goroutine for every failed re-arm, and can be nil.
+func (c *TxClient) NewDeadMansSwitch(window, interval time.Duration, onError func(error)) (*DeadMansSwitch, error) {
+	if c.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil. The dead man's switch needs it to send txs")
+	}
+	if window.Milliseconds() < txtypes.MinOrderCancelAllPeriod || window.Milliseconds() > txtypes.MaxOrderCancelAllPeriod {
+		return nil, fmt.Errorf("window should be between %v and %v", time.Duration(txtypes.MinOrderCancelAllPeriod)*time.Millisecond, time.Duration(txtypes.MaxOrderCancelAllPeriod)*time.Millisecond)
+	}
+	if interval <= 0 || interval >= window {
+		return nil, fmt.Errorf("interval should be positive and lower than the window")
+	}
+
+	return &DeadMansSwitch{
+		txClient: c,
+		window:   window,
+		interval: interval,
+		onError:  onError,
+		nonce:    -1,
+	}, nil
+}
+
+// Start arms the scheduled cancel all and starts re-arming it in the background.
+// An error is returned if the first arm fails, in which case the background goroutine is not started.
+func (d *DeadMansSwitch) Start() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return fmt.Errorf("dead man's switch is already running")
+	}
+	if err := d.armLocked(); err != nil {
+		return err
+	}
+
+	d.stop = make(chan struct{})
+	d.done = make(chan struct{})
+	go d.run(d.stop, d.done)
+	return nil
+}
+
+// Stop stops re-arming and aborts the scheduled cancel all, so resting orders are kept.
+// If aborting fails, the scheduled cancel all stays armed and triggers at ArmedUntil,
+// and Stop can be called again to retry.
+func (d *DeadMansSwitch) Stop() error {
+	d.mu.Lock()
+	stop, done := d.stop, d.done
+	d.stop, d.done = nil, nil
+	armed := !d.armedUntil.IsZero()
+	d.mu.Unlock()
+
+	if stop == nil && !armed {
+		return fmt.Errorf("dead man's switch is not running")
+	}
+	if stop != nil {
+		close(stop)
+		<-done
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.stop != nil {
+		return fmt.Errorf("dead man's switch was restarted while stopping")
+	}
+	if err := d.sendLocked(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.AbortScheduledCancelAll,
+		Time:        0,
+	}); err != nil {
+		return fmt.Errorf("failed to abort scheduled cancel all. err: %w", err)
+	}
+	d.armedUntil = time.Time{}
+	return nil
+}
+
+// ArmedUntil returns the time at which the orders will be cancelled if no further re-arm succeeds,
+// or the zero time if the switch is not armed.
+func (d *DeadMansSwitch) ArmedUntil() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.armedUntil
+}
+
+func (d *DeadMansSwitch) run(stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			d.mu.Lock()
+			err := d.armLocked()
+			d.mu.Unlock()
+			if err != nil && d.onError != nil {
+				d.onError(err)
+			}
+		}
+	}
+}
+
+func (d *DeadMansSwitch) armLocked() error {
+	armedUntil := d.txClient.Now().Add(d.window)
+	if err := d.sendLocked(&types.CancelAllOrdersTxReq{
+		TimeInForce: txtypes.ScheduledCancelAll,
+		Time:        armedUntil.UnixMilli(),
+	}); err != nil {
+		return fmt.Errorf("failed to schedule cancel all. err: %w", err)
+	}
+	d.armedUntil = armedUntil
+	return nil
+}
+
+// sendLocked signs & sends a cancel all tx with the locally tracked nonce
+func (d *DeadMansSwitch) sendLocked(req *types.CancelAllOrdersTxReq) error {
+	ops := &types.TransactOpts{}
+	if d.nonce >= 0 {
+		nonce := d.nonce
+		ops.Nonce = &nonce
+	}
+	tx, err := d.txClient.GetCancelAllOrdersTransaction(req, ops)
+	if err != nil {
+		return err
+	}
+	if _, err := d.txClient.apiClient.SendRawTx(tx); err != nil {
+		d.nonce = -1
+		return err
+	}
+	d.nonce = tx.Nonce + 1
+	return nil
+}
@@ -0,0 +1,138 @@
+//go:build !signonly
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// cancelAllServer accepts cancel all txs, and fails them while failing is set
+type cancelAllServer struct {
+	nonceRequests atomic.Int64
+	failing       atomic.Bool
+	sentTxs       chan txtypes.L2CancelAllOrdersTxInfo
+}
+
+func newCancelAllServer(t *testing.T) (*cancelAllServer, *httptest.Server) {
+	s := &cancelAllServer{sentTxs: make(chan txtypes.L2CancelAllOrdersTxInfo, 100)}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/nextNonce", func(w http.ResponseWriter, r *http.Request) {
+		s.nonceRequests.Add(1)
+		fmt.Fprint(w, `{"code":200,"nonce":7}`)
+	})
+	mux.HandleFunc("/api/v1/sendTx", func(w http.ResponseWriter, r *http.Request) {
+		if s.failing.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var tx txtypes.L2CancelAllOrdersTxInfo
+		if err := json.Unmarshal([]byte(r.FormValue("tx_info")), &tx); err != nil {
+			t.Error(err)
+		}
+		s.sentTxs <- tx
+		fmt.Fprint(w, `{"code":200,"tx_hash":"0xabc"}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *cancelAllServer) next(t *testing.T) txtypes.L2CancelAllOrdersTxInfo {
+	t.Helper()
+
+	select {
+	case tx := <-s.sentTxs:
+		return tx
+	case <-time.After(5 * time.Second):
+		t.Fatal("no tx sent")
+		return txtypes.L2CancelAllOrdersTxInfo{}
+	}
+}
+
+func TestDeadMansSwitch(t *testing.T) {
+	s, server := newCancelAllServer(t)
+	c, err := NewTxClient(NewHTTPClient(server.URL), batchPrivateKey, 100, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d, err := c.NewDeadMansSwitch(5*time.Minute, 10*time.Millisecond, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Stop(); err == nil {
+		t.Fatal("expected an error when stopping a switch which isn't running")
+	}
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Start(); err == nil {
+		t.Fatal("expected an error when starting a running switch")
+	}
+
+	// the first arm & the re-arms use consecutive nonces, fetched once
+	for nonce := int64(7); nonce < 10; nonce++ {
+		tx := s.next(t)
+		if tx.Nonce != nonce || tx.TimeInForce != txtypes.ScheduledCancelAll {
+			t.Fatalf("expected a scheduled cancel all with nonce %d, got %+v", nonce, tx)
+		}
+	}
+	if n := s.nonceRequests.Load(); n != 1 {
+		t.Fatalf("expected the nonce to be fetched once, got %d requests", n)
+	}
+	if d.ArmedUntil().IsZero() {
+		t.Fatal("expected the switch to be armed")
+	}
+
+	// a failed abort keeps the switch armed, and can be retried
+	s.failing.Store(true)
+	if err := d.Stop(); err == nil {
+		t.Fatal("expected the abort to fail")
+	}
+	if d.ArmedUntil().IsZero() {
+		t.Fatal("expected the switch to stay armed after a failed abort")
+	}
+
+	s.failing.Store(false)
+	for len(s.sentTxs) > 0 {
+		<-s.sentTxs
+	}
+	if err := d.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	tx := s.next(t)
+	if tx.TimeInForce != txtypes.AbortScheduledCancelAll || tx.Nonce != 7 {
+		t.Fatalf("expected an abort with the refetched nonce, got %+v", tx)
+	}
+	if !d.ArmedUntil().IsZero() {
+		t.Fatal("expected the switch to be disarmed")
+	}
+	if err := d.Stop(); err == nil {
+		t.Fatal("expected an error when stopping a stopped switch")
+	}
+}
+
+func TestDeadMansSwitchParams(t *testing.T) {
+	c := newBatchTxClient(t)
+	if _, err := c.NewDeadMansSwitch(5*time.Minute, time.Minute, nil); err == nil {
+		t.Error("expected an error without HTTPClient")
+	}
+
+	c.apiClient = NewHTTPClient("http://127.0.0.1:1")
+	if _, err := c.NewDeadMansSwitch(time.Minute, time.Second, nil); err == nil {
+		t.Error("expected an error for a window shorter than MinOrderCancelAllPeriod")
+	}
+	if _, err := c.NewDeadMansSwitch(5*time.Minute, 5*time.Minute, nil); err == nil {
+		t.Error("expected an error for an interval not lower than the window")
+	}
+}
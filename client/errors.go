@@ -0,0 +1,54 @@
+package client
+
+import "errors"
+
+// ErrSignOnly is returned by every request of the HTTPClient in builds with the signonly tag,
+// which leave net/http out, e.g. to keep the WASM module small. Txs have to be signed with an explicit nonce,
+// and sent by the app.
+var ErrSignOnly = errors.New("HTTP requests are not available in signonly builds")
+
+// APIError is returned when Lighter responds with a result code other than CodeOK, e.g. when it rejects a tx
+type APIError struct {
+	Code    int32
+	Message string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// StatusError is returned when Lighter responds with an HTTP status other than 200
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return e.Body
+}
+
+// RequestError is returned when a request to Lighter fails without response, e.g. when the connection fails
+type RequestError struct {
+	Err error
+}
+
+func (e *RequestError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RequestError) Unwrap() error {
+	return e.Err
+}
+
+// NonceError is returned when the nonce of a tx isn't provided, and can't be fetched from Lighter
+type NonceError struct {
+	Err error
+}
+
+func (e *NonceError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NonceError) Unwrap() error {
+	return e.Err
+}
@@ -1,3 +1,5 @@
+//go:build !signonly
+
 package client
 
 import (
@@ -27,9 +29,11 @@ var (
 )
 
 type HTTPClient struct {
+	client              *http.Client
 	endpoint            string
 	channelName         string
 	fatFingerProtection bool
+	serverClock         *ServerClock
 }
 
 func NewHTTPClient(baseUrl string) *HTTPClient {
@@ -38,12 +42,36 @@ func NewHTTPClient(baseUrl string) *HTTPClient {
 	}
 
 	return &HTTPClient{
+		client:              httpClient,
 		endpoint:            baseUrl,
 		channelName:         "",
 		fatFingerProtection: true,
+		serverClock:         NewServerClock(),
 	}
 }
 
 func (c *HTTPClient) SetFatFingerProtection(enabled bool) {
 	c.fatFingerProtection = enabled
 }
+
+// SetTransport replaces the transport of the requests, e.g. by one using the fetch API in a browser,
+// where the default transport can't open connections
+func (c *HTTPClient) SetTransport(transport http.RoundTripper) {
+	c.client = &http.Client{
+		Timeout:   httpClient.Timeout,
+		Transport: transport,
+	}
+}
+
+// ServerClock returns the clock estimated from the Date header of every response received by this client
+func (c *HTTPClient) ServerClock() *ServerClock {
+	return c.serverClock
+}
+
+func (c *ServerClock) observeResponse(resp *http.Response, localSent, localReceived time.Time) {
+	serverTime, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		return
+	}
+	c.Observe(serverTime, localSent, localReceived)
+}
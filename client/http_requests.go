@@ -1,14 +1,16 @@
+//go:build !signonly
+
 package client
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/elliottech/lighter-go/types/txtypes"
 )
@@ -19,7 +21,7 @@ func (c *HTTPClient) parseResultStatus(respBody []byte) error {
 		return err
 	}
 	if resultStatus.Code != CodeOK {
-		return errors.New(resultStatus.Message)
+		return &APIError{Code: resultStatus.Code, Message: resultStatus.Message}
 	}
 	return nil
 }
@@ -36,17 +38,19 @@ func (c *HTTPClient) getAndParseL2HTTPResponse(path string, params map[string]an
 		q.Set(k, fmt.Sprintf("%v", v))
 	}
 	u.RawQuery = q.Encode()
-	resp, err := httpClient.Get(u.String())
+	sentAt := time.Now()
+	resp, err := c.client.Get(u.String())
 	if err != nil {
-		return err
+		return &RequestError{Err: err}
 	}
 	defer resp.Body.Close()
+	c.serverClock.observeResponse(resp, sentAt, time.Now())
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return errors.New(string(body))
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	if err = c.parseResultStatus(body); err != nil {
 		return err
@@ -57,6 +61,22 @@ func (c *HTTPClient) getAndParseL2HTTPResponse(path string, params map[string]an
 	return nil
 }
 
+// SyncClock requests the status endpoint, to update the ServerClock without waiting for other requests
+func (c *HTTPClient) SyncClock() error {
+	sentAt := time.Now()
+	resp, err := c.client.Get(c.endpoint)
+	if err != nil {
+		return &RequestError{Err: err}
+	}
+	defer resp.Body.Close()
+	receivedAt := time.Now()
+	if _, err := http.ParseTime(resp.Header.Get("Date")); err != nil {
+		return fmt.Errorf("response has no valid Date header. err: %w", err)
+	}
+	c.serverClock.observeResponse(resp, sentAt, receivedAt)
+	return nil
+}
+
 func (c *HTTPClient) GetNextNonce(accountIndex int64, apiKeyIndex uint8) (int64, error) {
 	result := &NextNonce{}
 	err := c.getAndParseL2HTTPResponse("api/v1/nextNonce", map[string]any{"account_index": accountIndex, "api_key_index": apiKeyIndex}, result)
@@ -76,12 +96,15 @@ func (c *HTTPClient) GetApiKey(accountIndex int64, apiKeyIndex uint8) (*AccountA
 }
 
 func (c *HTTPClient) SendRawTx(tx txtypes.TxInfo) (string, error) {
-	txType := tx.GetTxType()
 	txInfo, err := tx.GetTxInfo()
 	if err != nil {
 		return "", err
 	}
+	return c.SendRawTxInfo(tx.GetTxType(), txInfo)
+}
 
+// SendRawTxInfo sends a tx already serialized, like the tx info returned by the bindings
+func (c *HTTPClient) SendRawTxInfo(txType uint8, txInfo string) (string, error) {
 	data := url.Values{"tx_type": {strconv.Itoa(int(txType))}, "tx_info": {txInfo}}
 
 	if c.fatFingerProtection == false {
@@ -91,17 +114,19 @@ func (c *HTTPClient) SendRawTx(tx txtypes.TxInfo) (string, error) {
 	req, _ := http.NewRequest("POST", c.endpoint+"/api/v1/sendTx", strings.NewReader(data.Encode()))
 	req.Header.Set("Channel-Name", c.channelName)
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	resp, err := httpClient.Do(req)
+	sentAt := time.Now()
+	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", err
+		return "", &RequestError{Err: err}
 	}
 	defer resp.Body.Close()
+	c.serverClock.observeResponse(resp, sentAt, time.Now())
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return "", err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return "", errors.New(string(body))
+		return "", &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 	if err = c.parseResultStatus(body); err != nil {
 		return "", err
@@ -126,3 +151,38 @@ func (c *HTTPClient) GetTransferFeeInfo(accountIndex, toAccountIndex int64, auth
 	}
 	return result, nil
 }
+
+func (c *HTTPClient) GetAccountActiveOrders(accountIndex int64, marketIndex uint8, auth string) (*Orders, error) {
+	result := &Orders{}
+	err := c.getAndParseL2HTTPResponse("api/v1/accountActiveOrders", map[string]any{
+		"account_index": accountIndex,
+		"market_id":     marketIndex,
+		"auth":          auth,
+	}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (c *HTTPClient) GetAccount(accountIndex int64) (*Account, error) {
+	result := &Accounts{}
+	err := c.getAndParseL2HTTPResponse("api/v1/account", map[string]any{"by": "index", "value": accountIndex}, result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Accounts) == 0 {
+		return nil, fmt.Errorf("account %d not found", accountIndex)
+	}
+	return result.Accounts[0], nil
+}
+
+// GetAccountsByL1Address returns all accounts of an L1 address: the master account and its sub-accounts
+func (c *HTTPClient) GetAccountsByL1Address(l1Address string) (*SubAccounts, error) {
+	result := &SubAccounts{}
+	err := c.getAndParseL2HTTPResponse("api/v1/accountsByL1Address", map[string]any{"l1_address": l1Address}, result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
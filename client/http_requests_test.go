@@ -0,0 +1,74 @@
+//go:build !signonly
+
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newOffsetServer answers with a Date header offset ahead of the local clock, or without Date header if offset is nil
+func newOffsetServer(t *testing.T, offset *time.Duration) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if offset == nil {
+			w.Header()["Date"] = nil
+		} else {
+			w.Header().Set("Date", time.Now().Add(*offset).UTC().Format(http.TimeFormat))
+		}
+		fmt.Fprint(w, `{"code":200,"nonce":7}`)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSyncClock(t *testing.T) {
+	offset := time.Hour + 400*time.Millisecond
+	c := NewHTTPClient(newOffsetServer(t, &offset).URL)
+
+	if err := c.SyncClock(); err != nil {
+		t.Fatal(err)
+	}
+	checkSyncedOffset(t, c.ServerClock(), offset)
+
+	// every response updates the clock, not only SyncClock
+	offset = -time.Hour
+	if _, err := c.GetNextNonce(100, 3); err != nil {
+		t.Fatal(err)
+	}
+	checkSyncedOffset(t, c.ServerClock(), offset)
+
+	// the txs of a TxClient are signed with the server time
+	txClient, err := NewTxClient(c, batchPrivateKey, 100, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := txClient.Now().Sub(time.Now().Add(offset)); diff < -2*time.Second || diff > 2*time.Second {
+		t.Fatalf("TxClient time is %v away from the server time", diff)
+	}
+}
+
+func TestSyncClockWithoutDate(t *testing.T) {
+	c := NewHTTPClient(newOffsetServer(t, nil).URL)
+
+	if err := c.SyncClock(); err == nil {
+		t.Fatal("expected an error for a response without Date header")
+	}
+	if c.ServerClock().Synced() {
+		t.Fatal("expected the clock not to be synced")
+	}
+}
+
+func checkSyncedOffset(t *testing.T, c *ServerClock, offset time.Duration) {
+	t.Helper()
+
+	if !c.Synced() {
+		t.Fatal("expected the clock to be synced")
+	}
+	// the Date header has a 1 second resolution, and the local round trip adds a bit more
+	if diff := c.Offset() - offset; diff < -2*time.Second || diff > 2*time.Second {
+		t.Fatalf("estimated offset %v too far from %v", c.Offset(), offset)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build signonly
+
+package client
+
+import (
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// HTTPClient keeps the API of the HTTP client in signonly builds, where all its requests fail with ErrSignOnly
+type HTTPClient struct {
+	endpoint            string
+	fatFingerProtection bool
+	serverClock         *ServerClock
+}
+
+func NewHTTPClient(baseUrl string) *HTTPClient {
+	if baseUrl == "" {
+		return nil
+	}
+
+	return &HTTPClient{
+		endpoint:            baseUrl,
+		fatFingerProtection: true,
+		serverClock:         NewServerClock(),
+	}
+}
+
+func (c *HTTPClient) SetFatFingerProtection(enabled bool) {
+	c.fatFingerProtection = enabled
+}
+
+// ServerClock returns a clock which is never synced, as no response is received, so it follows the local clock
+func (c *HTTPClient) ServerClock() *ServerClock {
+	return c.serverClock
+}
+
+func (c *HTTPClient) SyncClock() error {
+	return ErrSignOnly
+}
+
+func (c *HTTPClient) GetNextNonce(accountIndex int64, apiKeyIndex uint8) (int64, error) {
+	return -1, ErrSignOnly
+}
+
+func (c *HTTPClient) GetApiKey(accountIndex int64, apiKeyIndex uint8) (*AccountApiKeys, error) {
+	return nil, ErrSignOnly
+}
+
+func (c *HTTPClient) SendRawTx(tx txtypes.TxInfo) (string, error) {
+	return "", ErrSignOnly
+}
+
+func (c *HTTPClient) SendRawTxInfo(txType uint8, txInfo string) (string, error) {
+	return "", ErrSignOnly
+}
+
+func (c *HTTPClient) GetTransferFeeInfo(accountIndex, toAccountIndex int64, auth string) (*TransferFeeInfo, error) {
+	return nil, ErrSignOnly
+}
+
+func (c *HTTPClient) GetAccountActiveOrders(accountIndex int64, marketIndex uint8, auth string) (*Orders, error) {
+	return nil, ErrSignOnly
+}
+
+func (c *HTTPClient) GetAccount(accountIndex int64) (*Account, error) {
+	return nil, ErrSignOnly
+}
+
+func (c *HTTPClient) GetAccountsByL1Address(l1Address string) (*SubAccounts, error) {
+	return nil, ErrSignOnly
+}
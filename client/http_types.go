@@ -1,5 +1,11 @@
 package client
 
+import (
+	"encoding/json"
+
+	"github.com/elliottech/lighter-go/types"
+)
+
 const (
 	CodeOK = 200
 )
@@ -33,5 +39,55 @@ type TxHash struct {
 
 type TransferFeeInfo struct {
 	ResultCode
-	TransferFee int64 `json:"transfer_fee_usdc"`
+	TransferFee types.USDC `json:"transfer_fee_usdc"`
+}
+
+// UnmarshalJSON reads transfer_fee_usdc, which the API returns as an integer with 6 decimals
+func (i *TransferFeeInfo) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		ResultCode
+		TransferFee int64 `json:"transfer_fee_usdc"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	i.ResultCode, i.TransferFee = raw.ResultCode, types.USDC(raw.TransferFee)
+	return nil
+}
+
+type Order struct {
+	OrderIndex          int64  `json:"order_index,example=281474976710657"`
+	ClientOrderIndex    int64  `json:"client_order_index,example=1"`
+	MarketIndex         uint8  `json:"market_index,example=0"`
+	InitialBaseAmount   string `json:"initial_base_amount,example=0.1"`
+	RemainingBaseAmount string `json:"remaining_base_amount,example=0.1"`
+	FilledBaseAmount    string `json:"filled_base_amount,example=0"`
+	Price               string `json:"price,example=3024.66"`
+	IsAsk               bool   `json:"is_ask"`
+	Status              string `json:"status,example=open"`
+}
+
+type Orders struct {
+	ResultCode
+	Orders []*Order `json:"orders"`
+}
+
+type Account struct {
+	AccountType      uint8      `json:"account_type,example=0"`
+	Index            int64      `json:"index,example=1"`
+	L1Address        string     `json:"l1_address,example=0x70997970C51812dc3A010C7d01b50e0d17dc79C8"`
+	Status           uint8      `json:"status,example=1"`
+	Collateral       types.USDC `json:"collateral,example=46342.125"`
+	AvailableBalance types.USDC `json:"available_balance,example=19995"`
+}
+
+type Accounts struct {
+	ResultCode
+	Accounts []*Account `json:"accounts"`
+}
+
+type SubAccounts struct {
+	ResultCode
+	L1Address   string     `json:"l1_address,example=0x70997970C51812dc3A010C7d01b50e0d17dc79C8"`
+	SubAccounts []*Account `json:"sub_accounts"`
 }
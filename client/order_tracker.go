@@ -0,0 +1,348 @@
+package client
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+type OrderState uint8
+
+// Order lifecycle: Pending -> Open -> PartiallyFilled -> Filled / Cancelled / Expired.
+// Pending orders can also be Rejected, if the create tx is not accepted.
+const (
+	OrderStatePending OrderState = iota
+	OrderStateOpen
+	OrderStatePartiallyFilled
+	OrderStateFilled
+	OrderStateCancelled
+	OrderStateExpired
+	OrderStateRejected
+)
+
+func (s OrderState) String() string {
+	switch s {
+	case OrderStatePending:
+		return "pending"
+	case OrderStateOpen:
+		return "open"
+	case OrderStatePartiallyFilled:
+		return "partially_filled"
+	case OrderStateFilled:
+		return "filled"
+	case OrderStateCancelled:
+		return "cancelled"
+	case OrderStateExpired:
+		return "expired"
+	case OrderStateRejected:
+		return "rejected"
+	default:
+		return fmt.Sprintf("unknown(%d)", uint8(s))
+	}
+}
+
+// IsFinal returns true if the order can't change state anymore
+func (s OrderState) IsFinal() bool {
+	return s == OrderStateFilled || s == OrderStateCancelled || s == OrderStateExpired || s == OrderStateRejected
+}
+
+// canTransitionTo rejects stale updates, e.g. an "open" update received after a "filled" one
+func (s OrderState) canTransitionTo(next OrderState) bool {
+	switch s {
+	case OrderStatePending:
+		return true
+	case OrderStateOpen:
+		return next != OrderStatePending && next != OrderStateRejected
+	case OrderStatePartiallyFilled:
+		return next != OrderStatePending && next != OrderStateOpen && next != OrderStateRejected
+	default:
+		return false
+	}
+}
+
+// TrackedOrder is a snapshot of an order known by the OrderTracker
+type TrackedOrder struct {
+	MarketIndex      uint8
+	ClientOrderIndex int64
+	OrderIndex       int64 // NilOrderIndex until reported by the exchange
+	State            OrderState
+
+	BaseAmount   int64
+	Price        uint32
+	TriggerPrice uint32
+	IsAsk        uint8
+
+	// TxHashes holds the hashes of all signed txs for this order: create, then any modify or cancel
+	TxHashes  []string
+	UpdatedAt time.Time
+}
+
+// OrderTracker keeps the state of orders created by this SDK, keyed by ClientOrderIndex.
+// Signed txs are recorded automatically when the tracker is set on a TxClient using SetOrderTracker.
+// Exchange updates, from the REST API or the WebSocket, should be fed through ApplyOrder,
+// and failed sends through MarkRejected. Orders without a ClientOrderIndex are not tracked.
+// UpdatedAt uses the clock of the TxClient the tracker was last set on.
+// The tracker is safe for concurrent use.
+type OrderTracker struct {
+	mu       sync.RWMutex
+	orders   map[int64]*TrackedOrder // by ClientOrderIndex
+	byTxHash map[string]int64        // tx hash -> ClientOrderIndex
+	clock    Clock
+}
+
+func NewOrderTracker() *OrderTracker {
+	return &OrderTracker{
+		orders:   make(map[int64]*TrackedOrder),
+		byTxHash: make(map[string]int64),
+		clock:    SystemClock,
+	}
+}
+
+// SetClock replaces the clock used for UpdatedAt
+func (t *OrderTracker) SetClock(clock Clock) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.clock = clock
+}
+
+// TrackCreateOrder records a signed create order tx, as pending
+func (t *OrderTracker) TrackCreateOrder(tx *txtypes.L2CreateOrderTxInfo) {
+	if tx == nil || tx.OrderInfo == nil || tx.ClientOrderIndex == txtypes.NilClientOrderIndex {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.orders[tx.ClientOrderIndex] = &TrackedOrder{
+		MarketIndex:      tx.MarketIndex,
+		ClientOrderIndex: tx.ClientOrderIndex,
+		OrderIndex:       txtypes.NilOrderIndex,
+		State:            OrderStatePending,
+		BaseAmount:       tx.BaseAmount,
+		Price:            tx.Price,
+		TriggerPrice:     tx.TriggerPrice,
+		IsAsk:            tx.IsAsk,
+		TxHashes:         []string{tx.GetTxHash()},
+		UpdatedAt:        t.clock.Now(),
+	}
+	t.byTxHash[tx.GetTxHash()] = tx.ClientOrderIndex
+}
+
+// TrackModifyOrder records a signed modify order tx. The new amounts are applied optimistically.
+func (t *OrderTracker) TrackModifyOrder(tx *txtypes.L2ModifyOrderTxInfo) {
+	if tx == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := t.findLocked(tx.Index)
+	if order == nil {
+		return
+	}
+	order.BaseAmount = tx.BaseAmount
+	order.Price = tx.Price
+	order.TriggerPrice = tx.TriggerPrice
+	order.TxHashes = append(order.TxHashes, tx.GetTxHash())
+	order.UpdatedAt = t.clock.Now()
+	t.byTxHash[tx.GetTxHash()] = order.ClientOrderIndex
+}
+
+// TrackCancelOrder records a signed cancel order tx. The state changes only once the exchange confirms the cancel.
+func (t *OrderTracker) TrackCancelOrder(tx *txtypes.L2CancelOrderTxInfo) {
+	if tx == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order := t.findLocked(tx.Index)
+	if order == nil {
+		return
+	}
+	order.TxHashes = append(order.TxHashes, tx.GetTxHash())
+	order.UpdatedAt = t.clock.Now()
+	t.byTxHash[tx.GetTxHash()] = order.ClientOrderIndex
+}
+
+// MarkRejected should be called when sending the create order tx with the given hash fails.
+// Failures of modify or cancel txs don't change the order state.
+func (t *OrderTracker) MarkRejected(txHash string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	clientOrderIndex, ok := t.byTxHash[txHash]
+	if !ok {
+		return
+	}
+	order := t.orders[clientOrderIndex]
+	if len(order.TxHashes) == 0 || order.TxHashes[0] != txHash {
+		return
+	}
+	if order.State.canTransitionTo(OrderStateRejected) {
+		order.State = OrderStateRejected
+		order.UpdatedAt = t.clock.Now()
+	}
+}
+
+// ApplyOrder updates a tracked order from an exchange order, received from the REST API or the WebSocket.
+// It maps the ClientOrderIndex to the exchange OrderIndex, and returns false if the order is not tracked
+// or if the update is older than the current state.
+func (t *OrderTracker) ApplyOrder(update *Order) (bool, error) {
+	if update == nil {
+		return false, nil
+	}
+	state, err := orderStateFromStatus(update)
+	if err != nil {
+		return false, err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	order, ok := t.orders[update.ClientOrderIndex]
+	if !ok {
+		return false, nil
+	}
+	if update.OrderIndex != txtypes.NilOrderIndex {
+		order.OrderIndex = update.OrderIndex
+	}
+	if order.State != state && !order.State.canTransitionTo(state) {
+		return false, nil
+	}
+	order.State = state
+	order.UpdatedAt = t.clock.Now()
+	return true, nil
+}
+
+// Get returns a copy of the order with the given ClientOrderIndex
+func (t *OrderTracker) Get(clientOrderIndex int64) (TrackedOrder, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	order, ok := t.orders[clientOrderIndex]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return order.copy(), true
+}
+
+// GetByTxHash returns a copy of the order which the tx with the given hash belongs to
+func (t *OrderTracker) GetByTxHash(txHash string) (TrackedOrder, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	clientOrderIndex, ok := t.byTxHash[txHash]
+	if !ok {
+		return TrackedOrder{}, false
+	}
+	return t.orders[clientOrderIndex].copy(), true
+}
+
+// OpenOrders returns copies of all orders which are not in a final state
+func (t *OrderTracker) OpenOrders() []TrackedOrder {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ret := make([]TrackedOrder, 0)
+	for _, order := range t.orders {
+		if !order.State.IsFinal() {
+			ret = append(ret, order.copy())
+		}
+	}
+	return ret
+}
+
+// Forget removes orders in a final state, last updated before the given time
+func (t *OrderTracker) Forget(before time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for clientOrderIndex, order := range t.orders {
+		if !order.State.IsFinal() || !order.UpdatedAt.Before(before) {
+			continue
+		}
+		for _, txHash := range order.TxHashes {
+			delete(t.byTxHash, txHash)
+		}
+		delete(t.orders, clientOrderIndex)
+	}
+}
+
+// resolve returns the market and the index to use in cancel & modify txs for the given ClientOrderIndex.
+// The exchange OrderIndex is used once known, otherwise the exchange accepts the ClientOrderIndex as well.
+func (t *OrderTracker) resolve(clientOrderIndex int64) (uint8, int64, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	order, ok := t.orders[clientOrderIndex]
+	if !ok {
+		return 0, 0, fmt.Errorf("order with client order index %d is not tracked", clientOrderIndex)
+	}
+	if order.State.IsFinal() {
+		return 0, 0, fmt.Errorf("order with client order index %d is already %s", clientOrderIndex, order.State)
+	}
+	if order.OrderIndex != txtypes.NilOrderIndex {
+		return order.MarketIndex, order.OrderIndex, nil
+	}
+	return order.MarketIndex, order.ClientOrderIndex, nil
+}
+
+// findLocked looks up an order by either its ClientOrderIndex or its exchange OrderIndex
+func (t *OrderTracker) findLocked(index int64) *TrackedOrder {
+	if order, ok := t.orders[index]; ok {
+		return order
+	}
+	if index < txtypes.MinOrderIndex {
+		return nil
+	}
+	for _, order := range t.orders {
+		if order.OrderIndex == index {
+			return order
+		}
+	}
+	return nil
+}
+
+func (o *TrackedOrder) copy() TrackedOrder {
+	ret := *o
+	ret.TxHashes = append([]string(nil), o.TxHashes...)
+	return ret
+}
+
+func orderStateFromStatus(order *Order) (OrderState, error) {
+	switch {
+	case order.Status == "pending" || order.Status == "in-progress":
+		return OrderStatePending, nil
+	case order.Status == "open":
+		if isZeroDecimal(order.FilledBaseAmount) {
+			return OrderStateOpen, nil
+		}
+		return OrderStatePartiallyFilled, nil
+	case order.Status == "filled":
+		return OrderStateFilled, nil
+	case order.Status == "canceled-expired":
+		return OrderStateExpired, nil
+	case strings.HasPrefix(order.Status, "canceled"):
+		return OrderStateCancelled, nil
+	default:
+		return 0, fmt.Errorf("unknown order status %q", order.Status)
+	}
+}
+
+// isZeroDecimal checks decimal strings returned by the API, like "0.0000"
+func isZeroDecimal(s string) bool {
+	for _, c := range s {
+		if c >= '1' && c <= '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+func newTrackedTxClient(t *testing.T) (*TxClient, *OrderTracker, *FakeClock) {
+	t.Helper()
+
+	c := newBatchTxClient(t)
+	clock := NewFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.SetClock(clock)
+	tracker := NewOrderTracker()
+	c.SetOrderTracker(tracker)
+	return c, tracker, clock
+}
+
+func createTrackedOrder(t *testing.T, c *TxClient) *txtypes.L2CreateOrderTxInfo {
+	t.Helper()
+
+	order := batchOrders(1)[0].(*types.CreateOrderTxReq)
+	order.MarketIndex = 2
+	tx, err := c.GetCreateOrderTransaction(order, batchOps(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestOrderTrackerStates(t *testing.T) {
+	c, tracker, clock := newTrackedTxClient(t)
+	tx := createTrackedOrder(t, c)
+
+	order, ok := tracker.GetByTxHash(tx.GetTxHash())
+	if !ok || order.State != OrderStatePending || order.OrderIndex != txtypes.NilOrderIndex {
+		t.Fatalf("expected a pending order, got %+v", order)
+	}
+	if !order.UpdatedAt.Equal(c.Now()) {
+		t.Fatalf("expected UpdatedAt from the client's clock, got %v", order.UpdatedAt)
+	}
+
+	updates := []struct {
+		status  string
+		filled  string
+		applied bool
+		state   OrderState
+	}{
+		{"open", "0.0000", true, OrderStateOpen},
+		{"open", "0.0100", true, OrderStatePartiallyFilled},
+		{"open", "0.0000", false, OrderStatePartiallyFilled}, // stale update
+		{"pending", "0.0000", false, OrderStatePartiallyFilled},
+		{"filled", "0.1000", true, OrderStateFilled},
+		{"canceled", "0.1000", false, OrderStateFilled},
+	}
+	orderIndex := txtypes.MinOrderIndex + 5
+	for i, u := range updates {
+		clock.Advance(time.Second)
+		applied, err := tracker.ApplyOrder(&Order{OrderIndex: orderIndex, ClientOrderIndex: tx.ClientOrderIndex, Status: u.status, FilledBaseAmount: u.filled})
+		if err != nil {
+			t.Fatal(err)
+		}
+		order, _ = tracker.Get(tx.ClientOrderIndex)
+		if applied != u.applied || order.State != u.state {
+			t.Fatalf("update %d: expected (%v, %s), got (%v, %s)", i, u.applied, u.state, applied, order.State)
+		}
+		if applied && !order.UpdatedAt.Equal(clock.Now()) {
+			t.Fatalf("update %d: UpdatedAt not set from the client's clock", i)
+		}
+	}
+	if order.OrderIndex != orderIndex {
+		t.Fatalf("expected order index %d, got %d", orderIndex, order.OrderIndex)
+	}
+	if len(tracker.OpenOrders()) != 0 {
+		t.Fatal("expected no open orders")
+	}
+
+	if _, err := tracker.ApplyOrder(&Order{ClientOrderIndex: tx.ClientOrderIndex, Status: "unknown"}); err == nil {
+		t.Fatal("expected an error for an unknown status")
+	}
+
+	tracker.Forget(order.UpdatedAt)
+	if _, ok := tracker.Get(tx.ClientOrderIndex); !ok {
+		t.Fatal("orders updated at the given time should be kept")
+	}
+	tracker.Forget(clock.Now())
+	if _, ok := tracker.GetByTxHash(tx.GetTxHash()); ok {
+		t.Fatal("expected the order to be forgotten")
+	}
+}
+
+func TestOrderTrackerRejected(t *testing.T) {
+	c, tracker, _ := newTrackedTxClient(t)
+	tx := createTrackedOrder(t, c)
+
+	cancelTx, err := c.GetCancelTrackedOrderTransaction(tx.ClientOrderIndex, batchOps(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a failed cancel doesn't change the order
+	tracker.MarkRejected(cancelTx.GetTxHash())
+	if order, _ := tracker.Get(tx.ClientOrderIndex); order.State != OrderStatePending {
+		t.Fatalf("expected a pending order, got %s", order.State)
+	}
+
+	tracker.MarkRejected(tx.GetTxHash())
+	if order, _ := tracker.Get(tx.ClientOrderIndex); order.State != OrderStateRejected {
+		t.Fatalf("expected a rejected order, got %s", order.State)
+	}
+	if _, err := c.GetCancelTrackedOrderTransaction(tx.ClientOrderIndex, batchOps(3)); err == nil {
+		t.Fatal("expected an error when cancelling a rejected order")
+	}
+}
+
+func TestTrackedOrderTransactions(t *testing.T) {
+	c, tracker, _ := newTrackedTxClient(t)
+	if _, err := c.GetCancelTrackedOrderTransaction(1, batchOps(1)); err == nil {
+		t.Fatal("expected an error for an untracked order")
+	}
+	tx := createTrackedOrder(t, c)
+
+	// before the exchange reports the order index, the client order index is used
+	modifyTx, err := c.GetModifyTrackedOrderTransaction(tx.ClientOrderIndex, 2000, 310000, 0, batchOps(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if modifyTx.MarketIndex != 2 || modifyTx.Index != tx.ClientOrderIndex {
+		t.Fatalf("expected market 2 and index %d, got %d and %d", tx.ClientOrderIndex, modifyTx.MarketIndex, modifyTx.Index)
+	}
+	order, _ := tracker.Get(tx.ClientOrderIndex)
+	if order.BaseAmount != 2000 || order.Price != 310000 || len(order.TxHashes) != 2 {
+		t.Fatalf("modify not tracked, got %+v", order)
+	}
+
+	orderIndex := txtypes.MinOrderIndex + 5
+	if _, err := tracker.ApplyOrder(&Order{OrderIndex: orderIndex, ClientOrderIndex: tx.ClientOrderIndex, Status: "open", FilledBaseAmount: "0"}); err != nil {
+		t.Fatal(err)
+	}
+	cancelTx, err := c.GetCancelTrackedOrderTransaction(tx.ClientOrderIndex, batchOps(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelTx.MarketIndex != 2 || cancelTx.Index != orderIndex {
+		t.Fatalf("expected market 2 and index %d, got %d and %d", orderIndex, cancelTx.MarketIndex, cancelTx.Index)
+	}
+	if order, _ := tracker.GetByTxHash(cancelTx.GetTxHash()); order.ClientOrderIndex != tx.ClientOrderIndex || order.State != OrderStateOpen {
+		t.Fatalf("cancel not tracked, got %+v", order)
+	}
+
+	c.SetOrderTracker(nil)
+	if _, err := c.GetModifyTrackedOrderTransaction(tx.ClientOrderIndex, 2000, 310000, 0, batchOps(4)); err == nil {
+		t.Fatal("expected an error without an order tracker")
+	}
+}
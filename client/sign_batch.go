@@ -0,0 +1,122 @@
+package client
+
+import (
+	"fmt"
+	"reflect"
+	"runtime"
+	"sync"
+
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// SignBatch signs several txs at once, spreading the signatures over GOMAXPROCS goroutines.
+// txs are request pointers, like *types.CreateOrderTxReq or *types.CancelOrderTxReq.
+//
+// The ops are filled once, so at most one nonce is requested from Lighter, and tx i is signed with nonce + i.
+// Auto client order indexes are allocated and auto transfer fees requested in the order of txs too, so the result
+// is the same as signing the txs one by one. The returned txs are in the order of txs. If any tx fails, an error naming the tx is returned.
+func (c *TxClient) SignBatch(txs []any, ops *types.TransactOpts) ([]txtypes.TxInfo, error) {
+	if len(txs) == 0 {
+		return nil, nil
+	}
+	ops, err := c.FullFillDefaultOps(ops)
+	if err != nil {
+		return nil, err
+	}
+
+	// anything stateful is resolved here, in order, so it doesn't depend on the scheduling of the workers
+	txs = append([]any(nil), txs...)
+	for i, tx := range txs {
+		if tx == nil || reflect.ValueOf(tx).Kind() == reflect.Pointer && reflect.ValueOf(tx).IsNil() {
+			return nil, fmt.Errorf("tx %d is nil", i)
+		}
+		if order, ok := tx.(*types.CreateOrderTxReq); ok && order.ClientOrderIndex == AutoClientOrderIndex {
+			clientOrderIndex, err := c.ClientOrderIDAllocator().Next()
+			if err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+			orderCopy := *order
+			orderCopy.ClientOrderIndex = clientOrderIndex
+			txs[i] = &orderCopy
+		}
+		if transfer, ok := tx.(*types.TransferTxReq); ok && transfer.Fee == AutoTransferFee {
+			fee, err := c.GetTransferFee(*ops.FromAccountIndex, transfer.ToAccountIndex)
+			if err != nil {
+				return nil, fmt.Errorf("tx %d: %w", i, err)
+			}
+			transferCopy := *transfer
+			transferCopy.Fee = fee
+			txs[i] = &transferCopy
+		}
+	}
+
+	var (
+		results = make([]txtypes.TxInfo, len(txs))
+		errs    = make([]error, len(txs))
+		indexes = make(chan int, len(txs))
+		wg      sync.WaitGroup
+	)
+	for i := range txs {
+		indexes <- i
+	}
+	close(indexes)
+
+	workers := min(runtime.GOMAXPROCS(0), len(txs))
+	wg.Add(workers)
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				nonce := *ops.Nonce + int64(i)
+				txOps := *ops
+				txOps.Nonce = &nonce
+				results[i], errs[i] = c.signBatchTx(txs[i], &txOps)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("tx %d (%T): %w", i, txs[i], err)
+		}
+	}
+	return results, nil
+}
+
+func (c *TxClient) signBatchTx(tx any, ops *types.TransactOpts) (txtypes.TxInfo, error) {
+	// the typed nil pointers returned with errors are never used, the error is checked first
+	switch tx := tx.(type) {
+	case *types.ChangePubKeyReq:
+		return c.GetChangePubKeyTransaction(tx, ops)
+	case *types.CreatePublicPoolTxReq:
+		return c.GetCreatePublicPoolTransaction(tx, ops)
+	case *types.UpdatePublicPoolTxReq:
+		return c.GetUpdatePublicPoolTransaction(tx, ops)
+	case *types.TransferTxReq:
+		return c.GetTransferTransaction(tx, ops)
+	case *types.WithdrawTxReq:
+		return c.GetWithdrawTransaction(tx, ops)
+	case *types.CreateOrderTxReq:
+		return c.GetCreateOrderTransaction(tx, ops)
+	case *types.CreateGroupedOrdersTxReq:
+		return c.GetCreateGroupedOrdersTransaction(tx, ops)
+	case *types.CancelOrderTxReq:
+		return c.GetCancelOrderTransaction(tx, ops)
+	case *types.ModifyOrderTxReq:
+		return c.GetModifyOrderTransaction(tx, ops)
+	case *types.CancelAllOrdersTxReq:
+		return c.GetCancelAllOrdersTransaction(tx, ops)
+	case *types.MintSharesTxReq:
+		return c.GetMintSharesTransaction(tx, ops)
+	case *types.BurnSharesTxReq:
+		return c.GetBurnSharesTransaction(tx, ops)
+	case *types.UpdateLeverageTxReq:
+		return c.GetUpdateLeverageTransaction(tx, ops)
+	case *types.UpdateMarginTxReq:
+		return c.GetUpdateMarginTransaction(tx, ops)
+	default:
+		return nil, fmt.Errorf("unsupported tx type %T", tx)
+	}
+}
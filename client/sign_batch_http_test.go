@@ -0,0 +1,47 @@
+//go:build !signonly
+
+package client
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+func TestSignBatchAutoTransferFee(t *testing.T) {
+	s, server := newSubAccountsServer(t, nil)
+	c, err := NewTxClient(NewHTTPClient(server.URL), batchPrivateKey, 100, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var memo [32]byte
+	txs := []any{
+		&types.TransferTxReq{ToAccountIndex: 101, USDCAmount: types.OneUSDC, Fee: AutoTransferFee, Memo: memo},
+		&types.TransferTxReq{ToAccountIndex: 102, USDCAmount: types.OneUSDC, Fee: types.OneUSDC / 10, Memo: memo},
+		&types.TransferTxReq{ToAccountIndex: 103, USDCAmount: types.OneUSDC, Fee: AutoTransferFee, Memo: memo},
+	}
+	signed, err := c.SignBatch(txs, batchOps(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, expected := range []types.USDC{types.OneUSDC / 2, types.OneUSDC / 10, types.OneUSDC / 2} {
+		if tx := signed[i].(*txtypes.L2TransferTxInfo); tx.Fee != int64(expected) || tx.Nonce != 42+int64(i) {
+			t.Errorf("tx %d: expected fee %d & nonce %d, got %d & %d", i, expected, 42+i, tx.Fee, tx.Nonce)
+		}
+	}
+	if !reflect.DeepEqual(s.feeRequests, []string{"100", "100"}) {
+		t.Errorf("expected a fee request per auto fee transfer, got %v", s.feeRequests)
+	}
+	if txs[0].(*types.TransferTxReq).Fee != AutoTransferFee {
+		t.Error("SignBatch changed the fee of the request")
+	}
+
+	// and checked against the max transfer fee
+	c.SetMaxTransferFee(types.OneUSDC / 4)
+	if _, err := c.SignBatch(txs, batchOps(42)); err == nil {
+		t.Fatal("expected an error for a fee higher than the max transfer fee")
+	}
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+const batchPrivateKey = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728"
+
+func newBatchTxClient(t testing.TB) *TxClient {
+	c, err := NewTxClient(nil, batchPrivateKey, 100, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func batchOrders(n int) []any {
+	txs := make([]any, n)
+	for i := range txs {
+		txs[i] = &types.CreateOrderTxReq{
+			MarketIndex:      0,
+			ClientOrderIndex: AutoClientOrderIndex,
+			BaseAmount:       1000 + int64(i),
+			Price:            300000,
+			IsAsk:            uint8(i % 2),
+			Type:             txtypes.LimitOrder,
+			TimeInForce:      txtypes.GoodTillTime,
+			OrderExpiry:      1769817600000,
+		}
+	}
+	return txs
+}
+
+func batchOps(nonce int64) *types.TransactOpts {
+	return &types.TransactOpts{ExpiredAt: 1767225600000, Nonce: &nonce}
+}
+
+func TestSignBatchKeepsOrder(t *testing.T) {
+	c := newBatchTxClient(t)
+	txs := append(batchOrders(32), &types.CancelOrderTxReq{MarketIndex: 0, Index: 7})
+
+	signed, err := c.SignBatch(txs, batchOps(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(signed) != len(txs) {
+		t.Fatalf("expected %d txs, got %d", len(txs), len(signed))
+	}
+
+	prevClientOrderIndex := int64(-1)
+	for i, tx := range signed {
+		if i < 32 {
+			order := tx.(*txtypes.L2CreateOrderTxInfo)
+			if order.Nonce != 42+int64(i) || order.BaseAmount != 1000+int64(i) {
+				t.Fatalf("tx %d signed out of order: nonce %d base amount %d", i, order.Nonce, order.BaseAmount)
+			}
+			if order.ClientOrderIndex <= prevClientOrderIndex {
+				t.Fatalf("tx %d client order index %d not allocated in order", i, order.ClientOrderIndex)
+			}
+			prevClientOrderIndex = order.ClientOrderIndex
+		} else if cancel := tx.(*txtypes.L2CancelOrderTxInfo); cancel.Nonce != 42+int64(i) {
+			t.Fatalf("tx %d signed with nonce %d", i, cancel.Nonce)
+		}
+	}
+
+	if _, err := c.SignBatch([]any{txs[0], "not a tx"}, batchOps(42)); err == nil {
+		t.Fatal("expected an error for an unsupported tx")
+	}
+}
+
+func BenchmarkSignBatch(b *testing.B) {
+	c := newBatchTxClient(b)
+	txs := batchOrders(64)
+
+	b.Run("sequential", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for j, tx := range txs {
+				if _, err := c.GetCreateOrderTransaction(tx.(*types.CreateOrderTxReq), batchOps(int64(j))); err != nil {
+					b.Fatal(err)
+				}
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.SignBatch(txs, batchOps(0)); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
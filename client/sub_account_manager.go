@@ -0,0 +1,214 @@
+package client
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/types"
+)
+
+const (
+	subAccountPollInterval = time.Second
+)
+
+// SubAccountManager creates the sub-accounts of a master account, and keeps a TxClient for each of them.
+// The master TxClient must have an HTTPClient, which is shared by all sub-account clients.
+// The manager is safe for concurrent use.
+type SubAccountManager struct {
+	master *TxClient
+
+	createMu sync.Mutex // serializes CreateSubAccount, which identifies the new account by diffing the sub-accounts
+	mu       sync.RWMutex
+	clients  map[int64]*TxClient
+}
+
+func NewSubAccountManager(master *TxClient) (*SubAccountManager, error) {
+	if master.apiClient == nil {
+		return nil, fmt.Errorf("HTTPClient is nil. The sub-account manager needs it to discover & fund sub-accounts")
+	}
+
+	return &SubAccountManager{
+		master:  master,
+		clients: map[int64]*TxClient{master.GetAccountIndex(): master},
+	}, nil
+}
+
+// SubAccounts returns the indexes of all sub-accounts of the master account, as reported by the account API
+func (m *SubAccountManager) SubAccounts() ([]int64, error) {
+	master, err := m.master.apiClient.GetAccount(m.master.GetAccountIndex())
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := m.master.apiClient.GetAccountsByL1Address(master.L1Address)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]int64, 0, len(accounts.SubAccounts))
+	for _, account := range accounts.SubAccounts {
+		if account.Index != m.master.GetAccountIndex() {
+			ret = append(ret, account.Index)
+		}
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i] < ret[j] })
+	return ret, nil
+}
+
+// CreateSubAccount signs & sends a create sub-account tx from the master account,
+// then polls the account API until the new sub-account shows up, or the timeout passes.
+// It returns the index of the new sub-account.
+// Calls are serialized, but sub-accounts of the same master account must not be created concurrently
+// by other processes: if more than one new sub-account shows up, an error is returned instead of guessing.
+func (m *SubAccountManager) CreateSubAccount(timeout time.Duration) (int64, error) {
+	m.createMu.Lock()
+	defer m.createMu.Unlock()
+
+	existing, err := m.SubAccounts()
+	if err != nil {
+		return 0, err
+	}
+	known := make(map[int64]bool, len(existing))
+	for _, accountIndex := range existing {
+		known[accountIndex] = true
+	}
+
+	tx, err := m.master.GetCreateSubAccountTransaction(nil)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := m.master.apiClient.SendRawTx(tx); err != nil {
+		return 0, fmt.Errorf("failed to send create sub-account tx. err: %w", err)
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		accounts, err := m.SubAccounts()
+		if err == nil {
+			created := make([]int64, 0, 1)
+			for _, accountIndex := range accounts {
+				if !known[accountIndex] {
+					created = append(created, accountIndex)
+				}
+			}
+			if len(created) == 1 {
+				return created[0], nil
+			}
+			if len(created) > 1 {
+				return 0, fmt.Errorf("sub-accounts %v were created concurrently, can't tell which one was created by tx %s", created, tx.GetTxHash())
+			}
+		}
+		if time.Now().After(deadline) {
+			return 0, fmt.Errorf("sub-account not found after %v. tx hash: %s", timeout, tx.GetTxHash())
+		}
+		time.Sleep(subAccountPollInterval)
+	}
+}
+
+// AddClient creates & stores a TxClient for a sub-account. The API key must already be registered for the sub-account.
+func (m *SubAccountManager) AddClient(accountIndex int64, apiKeyPrivateKey string, apiKeyIndex uint8) (*TxClient, error) {
+	txClient, err := NewTxClient(m.master.apiClient, apiKeyPrivateKey, accountIndex, apiKeyIndex, m.master.chainId)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.clients[accountIndex] = txClient
+	return txClient, nil
+}
+
+// Client returns the TxClient of the given account, which can be the master account
+func (m *SubAccountManager) Client(accountIndex int64) (*TxClient, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	txClient, ok := m.clients[accountIndex]
+	return txClient, ok
+}
+
+// Transfer moves usdcAmount between two managed accounts, paying the fee reported by the API.
+// The fee is bounded by the sender client's max transfer fee.
+// It returns the hash of the transfer tx.
+func (m *SubAccountManager) Transfer(fromAccountIndex, toAccountIndex int64, usdcAmount types.USDC) (string, error) {
+	return m.transfer(fromAccountIndex, toAccountIndex, usdcAmount, AutoTransferFee)
+}
+
+func (m *SubAccountManager) transfer(fromAccountIndex, toAccountIndex int64, usdcAmount, fee types.USDC) (string, error) {
+	from, ok := m.Client(fromAccountIndex)
+	if !ok {
+		return "", fmt.Errorf("no client for account %d, call AddClient() first", fromAccountIndex)
+	}
+
+	tx, err := from.GetTransferTransaction(&types.TransferTxReq{
+		ToAccountIndex: toAccountIndex,
+		USDCAmount:     usdcAmount,
+		Fee:            fee,
+	}, nil)
+	if err != nil {
+		return "", err
+	}
+	return from.apiClient.SendRawTx(tx)
+}
+
+// Rebalance transfers USDC between managed accounts so that each account in targets ends up
+// with the given available balance. Accounts with a higher available balance than their target fund
+// the ones with less, so every account with a surplus must have a client. Collateral used as margin is never moved.
+// Transfer fees are paid by the sender out of its surplus, so a receiving account can end up
+// slightly below its target. It returns the hashes of the sent transfer txs.
+func (m *SubAccountManager) Rebalance(targets map[int64]types.USDC) ([]string, error) {
+	type delta struct {
+		accountIndex int64
+		amount       types.USDC
+	}
+	var surplus, deficit []*delta
+
+	for accountIndex, target := range targets {
+		account, err := m.master.apiClient.GetAccount(accountIndex)
+		if err != nil {
+			return nil, err
+		}
+		available := account.AvailableBalance
+		if available > target {
+			surplus = append(surplus, &delta{accountIndex: accountIndex, amount: available - target})
+		} else if available < target {
+			deficit = append(deficit, &delta{accountIndex: accountIndex, amount: target - available})
+		}
+	}
+	sort.Slice(surplus, func(i, j int) bool { return surplus[i].accountIndex < surplus[j].accountIndex })
+	sort.Slice(deficit, func(i, j int) bool { return deficit[i].accountIndex < deficit[j].accountIndex })
+
+	txHashes := make([]string, 0)
+	for _, to := range deficit {
+		for _, from := range surplus {
+			if to.amount == 0 {
+				break
+			}
+			if from.amount == 0 {
+				continue
+			}
+			fromClient, ok := m.Client(from.accountIndex)
+			if !ok {
+				return txHashes, fmt.Errorf("no client for account %d, call AddClient() first", from.accountIndex)
+			}
+			fee, err := fromClient.GetTransferFee(from.accountIndex, to.accountIndex)
+			if err != nil {
+				return txHashes, err
+			}
+			if from.amount <= fee {
+				continue
+			}
+			amount := min(from.amount-fee, to.amount)
+			txHash, err := m.transfer(from.accountIndex, to.accountIndex, amount, fee)
+			if err != nil {
+				return txHashes, fmt.Errorf("failed to transfer %s from %d to %d. err: %w", amount, from.accountIndex, to.accountIndex, err)
+			}
+			txHashes = append(txHashes, txHash)
+			from.amount -= amount + fee
+			to.amount -= amount
+		}
+	}
+	return txHashes, nil
+}
@@ -0,0 +1,208 @@
+//go:build !signonly
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+const subAccountsL1Address = "0x70997970C51812dc3A010C7d01b50e0d17dc79C8"
+
+// subAccountsServer fakes the accounts of one L1 address
+type subAccountsServer struct {
+	mu           sync.Mutex
+	accounts     map[int64]*Account
+	nextIndex    int64
+	createdPerTx int // sub-accounts created by each create sub-account tx, > 1 to fake concurrent creations
+	transfers    []txtypes.L2TransferTxInfo
+	feeRequests  []string // account_index of the transfer fee requests
+}
+
+func newSubAccountsServer(t *testing.T, balances map[int64][2]string) (*subAccountsServer, *httptest.Server) {
+	s := &subAccountsServer{accounts: make(map[int64]*Account), nextIndex: 200, createdPerTx: 1}
+	for index, balance := range balances {
+		s.accounts[index] = &Account{Index: index, L1Address: subAccountsL1Address, Collateral: mustUSDC(t, balance[0]), AvailableBalance: mustUSDC(t, balance[1])}
+	}
+
+	writeJSON := func(w http.ResponseWriter, v any) {
+		if err := json.NewEncoder(w).Encode(v); err != nil {
+			t.Error(err)
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/account", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		index, _ := strconv.ParseInt(r.URL.Query().Get("value"), 10, 64)
+		result := Accounts{ResultCode: ResultCode{Code: CodeOK}, Accounts: []*Account{}}
+		if account, ok := s.accounts[index]; ok {
+			result.Accounts = append(result.Accounts, account)
+		}
+		writeJSON(w, result)
+	})
+	mux.HandleFunc("/api/v1/accountsByL1Address", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		result := SubAccounts{ResultCode: ResultCode{Code: CodeOK}, L1Address: subAccountsL1Address, SubAccounts: []*Account{}}
+		for _, account := range s.accounts {
+			result.SubAccounts = append(result.SubAccounts, account)
+		}
+		writeJSON(w, result)
+	})
+	mux.HandleFunc("/api/v1/nextNonce", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":200,"nonce":1}`)
+	})
+	mux.HandleFunc("/api/v1/transferFeeInfo", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.feeRequests = append(s.feeRequests, r.URL.Query().Get("account_index"))
+		s.mu.Unlock()
+		fmt.Fprint(w, `{"code":200,"transfer_fee_usdc":500000}`)
+	})
+	mux.HandleFunc("/api/v1/sendTx", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		switch r.FormValue("tx_type") {
+		case strconv.Itoa(txtypes.TxTypeL2CreateSubAccount):
+			for i := 0; i < s.createdPerTx; i++ {
+				s.accounts[s.nextIndex] = &Account{Index: s.nextIndex, L1Address: subAccountsL1Address}
+				s.nextIndex++
+			}
+		case strconv.Itoa(txtypes.TxTypeL2Transfer):
+			var tx txtypes.L2TransferTxInfo
+			if err := json.Unmarshal([]byte(r.FormValue("tx_info")), &tx); err != nil {
+				t.Error(err)
+			}
+			s.transfers = append(s.transfers, tx)
+		default:
+			t.Errorf("unexpected tx type %s", r.FormValue("tx_type"))
+		}
+		fmt.Fprint(w, `{"code":200,"tx_hash":"0xabc"}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func mustUSDC(t *testing.T, s string) types.USDC {
+	t.Helper()
+
+	amount, err := types.ParseUSDC(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amount
+}
+
+func newSubAccountManager(t *testing.T, server *httptest.Server) *SubAccountManager {
+	t.Helper()
+
+	master, err := NewTxClient(NewHTTPClient(server.URL), batchPrivateKey, 100, 3, 304)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewSubAccountManager(master)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestSubAccountManagerCreate(t *testing.T) {
+	s, server := newSubAccountsServer(t, map[int64][2]string{100: {"0", "0"}, 101: {"0", "0"}})
+	m := newSubAccountManager(t, server)
+
+	accounts, err := m.SubAccounts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0] != 101 {
+		t.Fatalf("expected sub-account 101, got %v", accounts)
+	}
+
+	var wg sync.WaitGroup
+	created := make([]int64, 2)
+	for i := range created {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			accountIndex, err := m.CreateSubAccount(0)
+			if err != nil {
+				t.Error(err)
+			}
+			created[i] = accountIndex
+		}()
+	}
+	wg.Wait()
+	sort.Slice(created, func(i, j int) bool { return created[i] < created[j] })
+	if created[0] != 200 || created[1] != 201 {
+		t.Fatalf("expected sub-accounts 200 & 201, got %v", created)
+	}
+
+	// another process created a sub-account at the same time
+	s.mu.Lock()
+	s.createdPerTx = 2
+	s.mu.Unlock()
+	if _, err := m.CreateSubAccount(0); err == nil {
+		t.Fatal("expected an error when more than one sub-account shows up")
+	}
+}
+
+func TestSubAccountManagerRebalance(t *testing.T) {
+	// collateral, available balance
+	s, server := newSubAccountsServer(t, map[int64][2]string{
+		100: {"5000", "1000"},
+		101: {"100", "100"},
+		102: {"900", "800"},
+	})
+	m := newSubAccountManager(t, server)
+
+	targets := map[int64]types.USDC{100: mustUSDC(t, "700"), 101: mustUSDC(t, "500"), 102: mustUSDC(t, "300")}
+	if _, err := m.Rebalance(targets); err == nil {
+		t.Fatal("expected an error without a client for account 102")
+	}
+	s.transfers, s.feeRequests = nil, nil
+
+	if _, err := m.AddClient(102, batchPrivateKey, 3); err != nil {
+		t.Fatal(err)
+	}
+	txHashes, err := m.Rebalance(targets)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(txHashes) != 2 {
+		t.Fatalf("expected 2 transfers, got %d", len(txHashes))
+	}
+
+	// the fee is paid out of the surplus of the sender, which is based on the available balance
+	expected := []struct {
+		from, to    int64
+		amount, fee string
+	}{
+		{100, 101, "299.5", "0.5"},
+		{102, 101, "100.5", "0.5"},
+	}
+	if len(s.feeRequests) != 2 || s.feeRequests[0] != "100" || s.feeRequests[1] != "102" {
+		t.Errorf("expected the fees of transfers from 100 & 102, got %v", s.feeRequests)
+	}
+	for i, e := range expected {
+		tx := s.transfers[i]
+		if tx.FromAccountIndex != e.from || tx.ToAccountIndex != e.to || tx.USDCAmount != int64(mustUSDC(t, e.amount)) || tx.Fee != int64(mustUSDC(t, e.fee)) {
+			t.Errorf("transfer %d: expected %+v, got %+v", i, e, tx)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/hex"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/elliottech/lighter-go/signer"
@@ -11,6 +12,11 @@ import (
 
 const (
 	defaultExpireTime = time.Minute*10 - time.Second // we need to give a second margin, to eliminate millisecond differences
+
+	// AutoTransferFee can be passed as TransferTxReq.Fee to let the TxClient request the fee from Lighter
+	AutoTransferFee types.USDC = -1
+	// DefaultMaxTransferFee is the highest fee accepted when the transfer fee is resolved automatically
+	DefaultMaxTransferFee = 10 * types.OneUSDC
 )
 
 type TxClient struct {
@@ -18,11 +24,23 @@ type TxClient struct {
 	chainId      uint32
 	keyManager   signer.KeyManager
 	accountIndex int64
-	apiKeyIndex  uint8
+
+	// mu guards the fields below, which can be replaced while other goroutines sign with the client
+	mu             sync.RWMutex
+	apiKeyIndex    uint8
+	clientOrderIDs *ClientOrderIDAllocator
+	defaultIDs     bool // clientOrderIDs is tagged with apiKeyIndex, and follows SwitchAPIKey
+	orderTracker   *OrderTracker
+	maxTransferFee types.USDC
+	authTokens     *AuthTokenProvider
+	clock          Clock
 }
 
 // NewTxClient is linked to a specific (account, apiKey) pair
-// apiKeyPrivateKey should be hex-encoded bytes generated using `hexutil.Encode(TxClient.GetKeyManager().PrvKeyBytes())`
+// Orders created with AutoClientOrderIndex get indexes from a millisecond based ClientOrderIDAllocator, tagged with apiKeyIndex,
+// so clients using different API keys of the same account never collide. Clients sharing an API key, e.g. in different processes,
+// can still collide and should share one allocator, or use their own tags via SetClientOrderIDAllocator.
+// apiKeyPrivateKey should be hex-encoded bytes generated using `hex.EncodeToString(TxClient.GetKeyManager().PrvKeyBytes())`, with or without 0x
 func NewTxClient(apiClient *HTTPClient, apiKeyPrivateKey string, accountIndex int64, apiKeyIndex uint8, chainId uint32) (*TxClient, error) {
 	// remove 0x from private key, if any, and parse to bytes
 	if len(apiKeyPrivateKey) < 2 {
@@ -40,14 +58,28 @@ func NewTxClient(apiClient *HTTPClient, apiKeyPrivateKey string, accountIndex in
 	if err != nil {
 		return nil, err
 	}
+	return NewTxClientWithKeyManager(apiClient, keyManager, accountIndex, apiKeyIndex, chainId), nil
+}
+
+// NewTxClientWithKeyManager is NewTxClient for a key which isn't held as bytes, like a signer.KeyProvider
+func NewTxClientWithKeyManager(apiClient *HTTPClient, keyManager signer.KeyManager, accountIndex int64, apiKeyIndex uint8, chainId uint32) *TxClient {
+	var clock Clock = SystemClock
+	if apiClient != nil {
+		clock = apiClient.ServerClock()
+	}
 
 	return &TxClient{
-		apiClient:    apiClient,
-		apiKeyIndex:  apiKeyIndex,
-		accountIndex: accountIndex,
-		chainId:      chainId,
-		keyManager:   keyManager,
-	}, nil
+		apiClient:      apiClient,
+		apiKeyIndex:    apiKeyIndex,
+		accountIndex:   accountIndex,
+		chainId:        chainId,
+		keyManager:     keyManager,
+		clientOrderIDs: newAPIKeyClientOrderIDAllocator(apiKeyIndex),
+		defaultIDs:     true,
+		maxTransferFee: DefaultMaxTransferFee,
+		authTokens:     newDefaultAuthTokenProvider(),
+		clock:          clock,
+	}
 }
 
 func (c *TxClient) FullFillDefaultOps(ops *types.TransactOpts) (*types.TransactOpts, error) {
@@ -55,21 +87,22 @@ func (c *TxClient) FullFillDefaultOps(ops *types.TransactOpts) (*types.TransactO
 		ops = new(types.TransactOpts)
 	}
 	if ops.ExpiredAt == 0 {
-		ops.ExpiredAt = time.Now().Add(defaultExpireTime).UnixMilli()
+		ops.ExpiredAt = c.Now().Add(defaultExpireTime).UnixMilli()
 	}
 	if ops.FromAccountIndex == nil {
 		ops.FromAccountIndex = &c.accountIndex
 	}
 	if ops.ApiKeyIndex == nil {
-		ops.ApiKeyIndex = &c.apiKeyIndex
+		apiKeyIndex := c.GetApiKeyIndex()
+		ops.ApiKeyIndex = &apiKeyIndex
 	}
 	if ops.Nonce == nil {
 		if c.apiClient == nil {
-			return nil, fmt.Errorf("nonce was not provided & HTTPClient is nil. Either provide the nonce or enable HTTPClient to get the nonce from Lighter")
+			return nil, &NonceError{Err: fmt.Errorf("nonce was not provided & HTTPClient is nil. Either provide the nonce or enable HTTPClient to get the nonce from Lighter")}
 		}
 		nonce, err := c.apiClient.GetNextNonce(*ops.FromAccountIndex, *ops.ApiKeyIndex)
 		if err != nil {
-			return nil, err
+			return nil, &NonceError{Err: err}
 		}
 		ops.Nonce = &nonce
 	}
@@ -82,28 +115,154 @@ func (c *TxClient) GetAccountIndex() int64 {
 }
 
 func (c *TxClient) GetApiKeyIndex() uint8 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
 	return c.apiKeyIndex
 }
 
+func (c *TxClient) GetChainId() uint32 {
+	return c.chainId
+}
+
 func (c *TxClient) GetKeyManager() signer.KeyManager {
 	return c.keyManager
 }
 
+// Now returns the current time of the client's clock, which is used for every timestamp signed by the client.
+// By default, it's the server clock of the HTTPClient, or the local clock if there is no HTTPClient.
+func (c *TxClient) Now() time.Time {
+	c.mu.RLock()
+	clock := c.clock
+	c.mu.RUnlock()
+
+	return clock.Now()
+}
+
+// SetClock replaces the clock used for tx expiries & auth token deadlines, e.g. with a FakeClock in tests
+func (c *TxClient) SetClock(clock Clock) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clock = clock
+}
+
 func (c *TxClient) GetAuthToken(deadline time.Time) (string, error) {
-	if time.Until(deadline) > (7 * time.Hour) {
+	if deadline.Sub(c.Now()) > (7 * time.Hour) {
 		return "", fmt.Errorf("deadline should be within 7 hours")
 	}
 
+	apiKeyIndex := c.GetApiKeyIndex()
 	return types.ConstructAuthToken(c.keyManager, deadline, &types.TransactOpts{
-		ApiKeyIndex:      &c.apiKeyIndex,
+		ApiKeyIndex:      &apiKeyIndex,
 		FromAccountIndex: &c.accountIndex,
 	})
 }
 
+// ClientOrderIDAllocator returns the allocator used for orders created with AutoClientOrderIndex.
+func (c *TxClient) ClientOrderIDAllocator() *ClientOrderIDAllocator {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.clientOrderIDs
+}
+
+// SetClientOrderIDAllocator replaces the allocator used for orders created with AutoClientOrderIndex.
+// Clients of the same account should share the same allocator.
+func (c *TxClient) SetClientOrderIDAllocator(allocator *ClientOrderIDAllocator) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clientOrderIDs = allocator
+	c.defaultIDs = false
+}
+
+// OrderTracker returns the tracker set using SetOrderTracker, if any
+func (c *TxClient) OrderTracker() *OrderTracker {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.orderTracker
+}
+
+// SetOrderTracker makes the client record every signed create, modify and cancel order tx in the given tracker.
+// The tracker then uses the client's clock, like the signed txs.
+func (c *TxClient) SetOrderTracker(tracker *OrderTracker) {
+	if tracker != nil {
+		tracker.SetClock(c)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.orderTracker = tracker
+}
+
+// GetCachedAuthToken returns an auth token from the client's AuthTokenProvider,
+// which is reused until it gets close to its deadline
+func (c *TxClient) GetCachedAuthToken() (string, error) {
+	return c.AuthTokenProvider().Token(c)
+}
+
+// AuthTokenProvider returns the provider used by GetCachedAuthToken
+func (c *TxClient) AuthTokenProvider() *AuthTokenProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.authTokens
+}
+
+// SetAuthTokenProvider replaces the provider used by GetCachedAuthToken, e.g. to share one between clients
+func (c *TxClient) SetAuthTokenProvider(provider *AuthTokenProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.authTokens = provider
+}
+
+// SetMaxTransferFee sets the highest fee accepted when the transfer fee is resolved automatically
+func (c *TxClient) SetMaxTransferFee(maxFee types.USDC) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.maxTransferFee = maxFee
+}
+
+// GetTransferFee returns the fee charged by Lighter for a transfer from fromAccountIndex to toAccountIndex.
+// It fails if the fee is higher than the client's max transfer fee.
+func (c *TxClient) GetTransferFee(fromAccountIndex, toAccountIndex int64) (types.USDC, error) {
+	if c.apiClient == nil {
+		return 0, fmt.Errorf("fee was not provided & HTTPClient is nil. Either provide the fee or enable HTTPClient to get the fee from Lighter")
+	}
+	authToken, err := c.GetCachedAuthToken()
+	if err != nil {
+		return 0, err
+	}
+	feeInfo, err := c.apiClient.GetTransferFeeInfo(fromAccountIndex, toAccountIndex, authToken)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get transfer fee. err: %w", err)
+	}
+	c.mu.RLock()
+	maxTransferFee := c.maxTransferFee
+	c.mu.RUnlock()
+	if feeInfo.TransferFee > maxTransferFee {
+		return 0, fmt.Errorf("transfer fee %s is higher than the max transfer fee %s", feeInfo.TransferFee, maxTransferFee)
+	}
+	return feeInfo.TransferFee, nil
+}
+
 func (c *TxClient) HTTP() *HTTPClient {
 	return c.apiClient
 }
 
+// SwitchAPIKey makes the client sign with another API key of the account. The default ClientOrderIDAllocator
+// is replaced by one tagged with the new key, while an allocator set with SetClientOrderIDAllocator is kept.
 func (c *TxClient) SwitchAPIKey(apiKey uint8) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.defaultIDs && apiKey != c.apiKeyIndex {
+		c.clientOrderIDs = newAPIKeyClientOrderIDAllocator(apiKey)
+	}
 	c.apiKeyIndex = apiKey
 }
@@ -64,11 +64,22 @@ func (c *TxClient) GetUpdatePublicPoolTransaction(tx *types.UpdatePublicPoolTxRe
 	return txInfo, nil
 }
 
+// GetTransferTransaction signs a transfer tx. If tx.Fee is AutoTransferFee, the fee is requested from Lighter,
+// and the tx is rejected if the fee is higher than the client's max transfer fee.
 func (c *TxClient) GetTransferTransaction(tx *types.TransferTxReq, ops *types.TransactOpts) (*txtypes.L2TransferTxInfo, error) {
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
 	}
+	if tx.Fee == AutoTransferFee {
+		fee, err := c.GetTransferFee(*ops.FromAccountIndex, tx.ToAccountIndex)
+		if err != nil {
+			return nil, err
+		}
+		txCopy := *tx
+		txCopy.Fee = fee
+		tx = &txCopy
+	}
 	txInfo, err := types.ConstructTransferTx(c.keyManager, c.chainId, tx, ops)
 	if err != nil {
 		return nil, err
@@ -89,15 +100,29 @@ func (c *TxClient) GetWithdrawTransaction(tx *types.WithdrawTxReq, ops *types.Tr
 	return txInfo, nil
 }
 
+// GetCreateOrderTransaction signs a create order tx. If tx.ClientOrderIndex is AutoClientOrderIndex,
+// a new one is taken from the client's ClientOrderIDAllocator, and can be read from the returned tx.
 func (c *TxClient) GetCreateOrderTransaction(tx *types.CreateOrderTxReq, ops *types.TransactOpts) (*txtypes.L2CreateOrderTxInfo, error) {
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
 		return nil, err
 	}
+	if tx.ClientOrderIndex == AutoClientOrderIndex {
+		clientOrderIndex, err := c.ClientOrderIDAllocator().Next()
+		if err != nil {
+			return nil, err
+		}
+		txCopy := *tx
+		txCopy.ClientOrderIndex = clientOrderIndex
+		tx = &txCopy
+	}
 	txInfo, err := types.ConstructCreateOrderTx(c.keyManager, c.chainId, tx, ops)
 	if err != nil {
 		return nil, err
 	}
+	if tracker := c.OrderTracker(); tracker != nil {
+		tracker.TrackCreateOrder(txInfo)
+	}
 	return txInfo, nil
 }
 
@@ -110,9 +135,29 @@ func (c *TxClient) GetCancelOrderTransaction(tx *types.CancelOrderTxReq, ops *ty
 	if err != nil {
 		return nil, err
 	}
+	if tracker := c.OrderTracker(); tracker != nil {
+		tracker.TrackCancelOrder(txInfo)
+	}
 	return txInfo, nil
 }
 
+// GetCancelTrackedOrderTransaction cancels an order using its ClientOrderIndex.
+// The market and the exchange OrderIndex are resolved using the client's OrderTracker.
+func (c *TxClient) GetCancelTrackedOrderTransaction(clientOrderIndex int64, ops *types.TransactOpts) (*txtypes.L2CancelOrderTxInfo, error) {
+	tracker := c.OrderTracker()
+	if tracker == nil {
+		return nil, fmt.Errorf("order tracker is not set, call SetOrderTracker() first")
+	}
+	marketIndex, index, err := tracker.resolve(clientOrderIndex)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCancelOrderTransaction(&types.CancelOrderTxReq{
+		MarketIndex: marketIndex,
+		Index:       index,
+	}, ops)
+}
+
 func (c *TxClient) GetCreateGroupedOrdersTransaction(tx *types.CreateGroupedOrdersTxReq, ops *types.TransactOpts) (*txtypes.L2CreateGroupedOrdersTxInfo, error) {
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
@@ -135,10 +180,33 @@ func (c *TxClient) GetModifyOrderTransaction(tx *types.ModifyOrderTxReq, ops *ty
 	if err != nil {
 		return nil, err
 	}
+	if tracker := c.OrderTracker(); tracker != nil {
+		tracker.TrackModifyOrder(txInfo)
+	}
 
 	return txInfo, nil
 }
 
+// GetModifyTrackedOrderTransaction modifies an order using its ClientOrderIndex.
+// The market and the exchange OrderIndex are resolved using the client's OrderTracker.
+func (c *TxClient) GetModifyTrackedOrderTransaction(clientOrderIndex, baseAmount int64, price, triggerPrice uint32, ops *types.TransactOpts) (*txtypes.L2ModifyOrderTxInfo, error) {
+	tracker := c.OrderTracker()
+	if tracker == nil {
+		return nil, fmt.Errorf("order tracker is not set, call SetOrderTracker() first")
+	}
+	marketIndex, index, err := tracker.resolve(clientOrderIndex)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetModifyOrderTransaction(&types.ModifyOrderTxReq{
+		MarketIndex:  marketIndex,
+		Index:        index,
+		BaseAmount:   baseAmount,
+		Price:        price,
+		TriggerPrice: triggerPrice,
+	}, ops)
+}
+
 func (c *TxClient) GetCancelAllOrdersTransaction(tx *types.CancelAllOrdersTxReq, ops *types.TransactOpts) (*txtypes.L2CancelAllOrdersTxInfo, error) {
 	ops, err := c.FullFillDefaultOps(ops)
 	if err != nil {
@@ -0,0 +1,295 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/types"
+)
+
+// The params of the methods which aren't signing a tx, as taken by Call.
+// The fields tagged omitempty are optional, like the ones with a non-zero default.
+
+type GenerateAPIKeyParams struct {
+	Seed string `json:"seed,omitempty"`
+}
+
+type CreateClientParams struct {
+	URL          string `json:"url,omitempty"`
+	PrivateKey   string `json:"privateKey"`
+	ChainId      uint32 `json:"chainId"`
+	ApiKeyIndex  uint8  `json:"apiKeyIndex"`
+	AccountIndex int64  `json:"accountIndex"`
+}
+
+type AddAPIKeyParams struct {
+	PrivateKey  string `json:"privateKey"`
+	ApiKeyIndex uint8  `json:"apiKeyIndex"`
+}
+
+type ConfigureClientOrderIndexParams struct {
+	TagBits              uint8 `json:"tagBits"`
+	Tag                  int64 `json:"tag"`
+	LastClientOrderIndex int64 `json:"lastClientOrderIndex,omitempty"`
+}
+
+type CheckClientParams struct {
+	ApiKeyIndex  uint8 `json:"apiKeyIndex"`
+	AccountIndex int64 `json:"accountIndex"`
+}
+
+type SwitchAPIKeyParams struct {
+	ApiKeyIndex uint8 `json:"apiKeyIndex"`
+}
+
+type SetMaxTransferFeeParams struct {
+	MaxFee types.USDC `json:"maxFee"`
+}
+
+type CreateAuthTokenParams struct {
+	Deadline int64 `json:"deadline,omitempty"`
+}
+
+// Result is the kind of string returned by a method of Call, telling the bindings how to convert it
+type Result string
+
+const (
+	ResultString  Result = "string"  // a string, like a tx info or an auth token
+	ResultNone    Result = "none"    // an empty string
+	ResultInteger Result = "integer" // an int64 as a decimal string
+	ResultJSON    Result = "json"    // a JSON object or array
+)
+
+// method decodes its params into the struct returned by newParams, which holds the defaults,
+// so a missing nonce is AutoNonce, and a missing order expiry is DefaultOrderExpiry.
+type method struct {
+	newParams func() any
+	run       func(s *Session, params any) (string, error)
+	result    Result
+}
+
+// returns sets the kind of result of the method, which is ResultString by default
+func (m method) returns(result Result) method {
+	m.result = result
+	return m
+}
+
+func newMethod[P any](defaults func() *P, run func(*Session, P) (string, error)) method {
+	return method{
+		newParams: func() any { return defaults() },
+		run: func(s *Session, params any) (string, error) {
+			return run(s, *params.(*P))
+		},
+		result: ResultString,
+	}
+}
+
+func zero[P any]() *P {
+	return new(P)
+}
+
+// errOnly adapts the methods without result
+func errOnly[P any](fn func(*Session, P) error) func(*Session, P) (string, error) {
+	return func(s *Session, p P) (string, error) {
+		return "", fn(s, p)
+	}
+}
+
+// methods maps the names accepted by Call to the Session methods
+var methods = map[string]method{
+	"GenerateAPIKey": newMethod(zero[GenerateAPIKeyParams], func(_ *Session, p GenerateAPIKeyParams) (string, error) {
+		privateKey, publicKey := GenerateAPIKey(p.Seed)
+		return marshalResult(map[string]string{"privateKey": privateKey, "publicKey": publicKey})
+	}).returns(ResultJSON),
+	"CreateClient": newMethod(zero[CreateClientParams], errOnly(func(s *Session, p CreateClientParams) error {
+		return s.CreateClient(p.URL, p.PrivateKey, p.ChainId, p.ApiKeyIndex, p.AccountIndex)
+	})).returns(ResultNone),
+	"AddAPIKey": newMethod(zero[AddAPIKeyParams], errOnly(func(s *Session, p AddAPIKeyParams) error {
+		return s.AddAPIKey(p.PrivateKey, p.ApiKeyIndex)
+	})).returns(ResultNone),
+	"ConfigureClientOrderIndex": newMethod(zero[ConfigureClientOrderIndexParams], errOnly(func(s *Session, p ConfigureClientOrderIndexParams) error {
+		return s.ConfigureClientOrderIndex(p.TagBits, p.Tag, p.LastClientOrderIndex)
+	})).returns(ResultNone),
+	"CheckClient": newMethod(zero[CheckClientParams], errOnly(func(s *Session, p CheckClientParams) error {
+		return s.CheckClient(p.ApiKeyIndex, p.AccountIndex)
+	})).returns(ResultNone),
+	"SwitchAPIKey": newMethod(zero[SwitchAPIKeyParams], errOnly(func(s *Session, p SwitchAPIKeyParams) error {
+		return s.SwitchAPIKey(p.ApiKeyIndex)
+	})).returns(ResultNone),
+	"SetMaxTransferFee": newMethod(zero[SetMaxTransferFeeParams], errOnly(func(s *Session, p SetMaxTransferFeeParams) error {
+		return s.SetMaxTransferFee(p.MaxFee)
+	})).returns(ResultNone),
+	"CreateAuthToken": newMethod(zero[CreateAuthTokenParams], func(s *Session, p CreateAuthTokenParams) (string, error) {
+		return s.CreateAuthToken(p.Deadline)
+	}),
+
+	"SendTx": newMethod(zero[SendTxParams], (*Session).SendTx),
+	"GetNextNonce": newMethod(zero[GetNextNonceParams], func(s *Session, _ GetNextNonceParams) (string, error) {
+		return s.GetNextNonce()
+	}).returns(ResultInteger),
+	"GetApiKeys":         newMethod(zero[GetApiKeysParams], (*Session).GetApiKeys).returns(ResultJSON),
+	"GetTransferFeeInfo": newMethod(zero[GetTransferFeeInfoParams], (*Session).GetTransferFeeInfo),
+	"SetFatFingerProtection": newMethod(zero[SetFatFingerProtectionParams], errOnly(func(s *Session, p SetFatFingerProtectionParams) error {
+		return s.SetFatFingerProtection(p.Enabled)
+	})).returns(ResultNone),
+
+	"SignChangePubKey": newMethod(func() *ChangePubKeyParams {
+		return &ChangePubKeyParams{Nonce: AutoNonce}
+	}, (*Session).SignChangePubKey),
+	"SignCreateOrder": newMethod(func() *CreateOrderParams {
+		return &CreateOrderParams{OrderParams: NewOrderParams(), Nonce: AutoNonce}
+	}, (*Session).SignCreateOrder),
+	"SignCreateGroupedOrders": newMethod(func() *CreateGroupedOrdersParams {
+		return &CreateGroupedOrdersParams{Nonce: AutoNonce}
+	}, (*Session).SignCreateGroupedOrders),
+	"SignCancelOrder": newMethod(func() *CancelOrderParams {
+		return &CancelOrderParams{Nonce: AutoNonce}
+	}, (*Session).SignCancelOrder),
+	"SignWithdraw": newMethod(func() *WithdrawParams {
+		return &WithdrawParams{Nonce: AutoNonce}
+	}, (*Session).SignWithdraw),
+	"SignCreateSubAccount": newMethod(func() *CreateSubAccountParams {
+		return &CreateSubAccountParams{Nonce: AutoNonce}
+	}, (*Session).SignCreateSubAccount),
+	"SignCancelAllOrders": newMethod(func() *CancelAllOrdersParams {
+		return &CancelAllOrdersParams{Nonce: AutoNonce}
+	}, (*Session).SignCancelAllOrders),
+	"SignModifyOrder": newMethod(func() *ModifyOrderParams {
+		return &ModifyOrderParams{Nonce: AutoNonce}
+	}, (*Session).SignModifyOrder),
+	"SignTransfer": newMethod(func() *TransferParams {
+		return &TransferParams{Fee: client.AutoTransferFee, Nonce: AutoNonce}
+	}, (*Session).SignTransfer),
+	"SignCreatePublicPool": newMethod(func() *CreatePublicPoolParams {
+		return &CreatePublicPoolParams{Nonce: AutoNonce}
+	}, (*Session).SignCreatePublicPool),
+	"SignUpdatePublicPool": newMethod(func() *UpdatePublicPoolParams {
+		return &UpdatePublicPoolParams{Nonce: AutoNonce}
+	}, (*Session).SignUpdatePublicPool),
+	"SignMintShares": newMethod(func() *SharesParams {
+		return &SharesParams{Nonce: AutoNonce}
+	}, (*Session).SignMintShares),
+	"SignBurnShares": newMethod(func() *SharesParams {
+		return &SharesParams{Nonce: AutoNonce}
+	}, (*Session).SignBurnShares),
+	"SignUpdateLeverage": newMethod(func() *UpdateLeverageParams {
+		return &UpdateLeverageParams{Nonce: AutoNonce}
+	}, (*Session).SignUpdateLeverage),
+	"SignUpdateMargin": newMethod(func() *UpdateMarginParams {
+		return &UpdateMarginParams{Nonce: AutoNonce}
+	}, (*Session).SignUpdateMargin),
+}
+
+// Methods returns the names accepted by Call, sorted
+func Methods() []string {
+	names := make([]string, 0, len(methods))
+	for name := range methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ClientMethods returns the methods of a created client, sorted: all but GenerateAPIKey, which doesn't use
+// the Session, and CreateClient, which creates it. It's meant for bindings with client objects.
+func ClientMethods() []string {
+	names := []string{}
+	for _, name := range Methods() {
+		if name != "GenerateAPIKey" && name != "CreateClient" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// LowerCamelCase returns the name of a method or field in the bindings using lower camel case, e.g. JS.
+// The leading word or initialism is lowered, so SignCreateOrder is signCreateOrder, and USDCAmount is usdcAmount.
+func LowerCamelCase(name string) string {
+	upper := 0
+	for upper < len(name) && unicode.IsUpper(rune(name[upper])) {
+		upper++
+	}
+	if upper > 1 && upper < len(name) {
+		upper--
+	}
+	return strings.ToLower(name[:upper]) + name[upper:]
+}
+
+// NewParams returns a pointer to the params of a method, holding the defaults of the missing fields.
+// It's meant for bindings converting their own objects to the params, and for generating their type definitions.
+func NewParams(name string) (any, error) {
+	m, ok := methods[name]
+	if !ok {
+		return nil, WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("unknown method %q", name))
+	}
+	return m.newParams(), nil
+}
+
+// MethodResult returns the kind of result of a method
+func MethodResult(name string) (Result, error) {
+	m, ok := methods[name]
+	if !ok {
+		return "", WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("unknown method %q", name))
+	}
+	return m.result, nil
+}
+
+// DefaultSession is the session used by the package level Call, for bindings holding a single session
+var DefaultSession = NewSession()
+
+// Call runs a method of DefaultSession, see Session.Call
+func Call(name string, paramsJSON string) (string, error) {
+	return DefaultSession.Call(name, paramsJSON)
+}
+
+// Call runs a method by name, with its params as a JSON object. It's meant for bindings which are easier
+// to write against a single JSON-in/JSON-out function, like scripting languages.
+// Sign methods return the tx info JSON, and the kind of result of the other methods is given by MethodResult.
+func (s *Session) Call(name string, paramsJSON string) (ret string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s panicked: %v", name, r)
+		}
+	}()
+
+	m, ok := methods[name]
+	if !ok {
+		return "", WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("unknown method %q", name))
+	}
+	params := m.newParams()
+	if err := decodeParams([]byte(paramsJSON), params); err != nil {
+		return "", err
+	}
+	return m.run(s, params)
+}
+
+// decodeParams decodes the params object of a method, which can be empty if all params are optional
+func decodeParams(params []byte, p any) error {
+	if len(bytes.TrimSpace(params)) == 0 {
+		return nil
+	}
+	if err := strictUnmarshal(params, p); err != nil {
+		return WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("invalid params. err: %w", err))
+	}
+	return nil
+}
+
+// strictUnmarshal rejects unknown fields, so a misspelled optional param isn't silently replaced by its default
+func strictUnmarshal(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(v)
+}
+
+func marshalResult(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,233 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/types"
+)
+
+const testPrivateKey = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728"
+
+// newTestSession creates a session without url, so txs have to be signed with an explicit nonce.
+// The clock is fixed, so the ExpiredAt of the txs is the same.
+func newTestSession(t *testing.T) *Session {
+	s := NewSession()
+	if err := s.CreateClient("", testPrivateKey, 304, 3, 100); err != nil {
+		t.Fatal(err)
+	}
+	txClient, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txClient.SetClock(client.NewFakeClock(time.UnixMilli(1767225600000)))
+	return s
+}
+
+func TestCallMatchesTypedMethods(t *testing.T) {
+	s := newTestSession(t)
+
+	fromCall, err := s.Call("SignCreateOrder", `{"marketIndex":1,"clientOrderIndex":7,"baseAmount":1000,"price":300000,"isAsk":1,"type":0,"timeInForce":1,"orderExpiry":1769817600000,"nonce":42}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fromMethod, err := s.SignCreateOrder(CreateOrderParams{
+		OrderParams: OrderParams{MarketIndex: 1, ClientOrderIndex: 7, BaseAmount: 1000, Price: 300000, IsAsk: 1, TimeInForce: 1, OrderExpiry: 1769817600000},
+		Nonce:       42,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// signatures are randomized, everything else is the same
+	if a, b := withoutSig(t, fromCall), withoutSig(t, fromMethod); a != b {
+		t.Fatalf("Call and SignCreateOrder differ:\n%s\n%s", a, b)
+	}
+}
+
+func TestCallDefaults(t *testing.T) {
+	s := newTestSession(t)
+
+	// a missing orderExpiry is the 28 days default, not 0
+	txInfo, err := s.Call("SignCreateGroupedOrders", `{"groupingType":1,"nonce":1,"orders":[
+		{"marketIndex":0,"baseAmount":1000,"price":300000,"isAsk":0,"type":0,"timeInForce":1},
+		{"marketIndex":0,"baseAmount":0,"price":250000,"isAsk":1,"type":2,"timeInForce":0,"reduceOnly":1,"triggerPrice":250000}
+	]}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx struct {
+		Orders []struct{ OrderExpiry int64 }
+	}
+	if err := json.Unmarshal([]byte(txInfo), &tx); err != nil {
+		t.Fatal(err)
+	}
+	for i, order := range tx.Orders {
+		if order.OrderExpiry <= 0 {
+			t.Fatalf("order %d has no default expiry: %s", i, txInfo)
+		}
+	}
+
+	// a missing fee is AutoTransferFee, an explicit one is kept
+	for data, expected := range map[string]types.USDC{
+		`{"toAccountIndex":101,"usdcAmount":"1","memo":""}`:             client.AutoTransferFee,
+		`{"toAccountIndex":101,"usdcAmount":"1","fee":"0.5","memo":""}`: types.OneUSDC / 2,
+		`{"toAccountIndex":101,"usdcAmount":"1","fee":"0","memo":""}`:   0,
+	} {
+		params, err := NewParams("SignTransfer")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := strictUnmarshal([]byte(data), params); err != nil {
+			t.Fatalf("%s: %v", data, err)
+		}
+		if p := params.(*TransferParams); p.Fee != expected || p.Nonce != AutoNonce || p.ToAccountIndex != 101 {
+			t.Errorf("%s: unexpected params %+v", data, *p)
+		}
+	}
+
+	// a missing nonce is AutoNonce, which needs a url
+	if _, err := s.Call("SignCancelOrder", `{"marketIndex":0,"index":1}`); err == nil || !strings.Contains(err.Error(), "nonce was not provided") {
+		t.Fatalf("expected a missing nonce error, got %v", err)
+	}
+}
+
+// tickingClock advances by 1ms on every call, like a real clock between two orders
+type tickingClock struct {
+	now time.Time
+}
+
+func (c *tickingClock) Now() time.Time {
+	c.now = c.now.Add(time.Millisecond)
+	return c.now
+}
+
+func TestGroupedOrdersShareDefaultExpiry(t *testing.T) {
+	s := newTestSession(t)
+	txClient, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	txClient.SetClock(&tickingClock{now: time.UnixMilli(1767225600000)})
+
+	// OCO orders must have the same expiry
+	if _, err := s.Call("SignCreateGroupedOrders", `{"groupingType":2,"nonce":1,"orders":[
+		{"marketIndex":0,"baseAmount":1000,"price":350000,"isAsk":1,"type":4,"timeInForce":0,"reduceOnly":1,"triggerPrice":350000},
+		{"marketIndex":0,"baseAmount":1000,"price":250000,"isAsk":1,"type":2,"timeInForce":0,"reduceOnly":1,"triggerPrice":250000}
+	]}`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCallErrors(t *testing.T) {
+	if _, err := NewSession().Call("SignCancelOrder", `{"nonce":1}`); !errors.Is(err, ErrClientNotCreated) {
+		t.Fatalf("expected ErrClientNotCreated, got %v", err)
+	}
+
+	s := newTestSession(t)
+	for name, call := range map[string][2]string{
+		"unknown method":  {"SignNothing", `{}`},
+		"unknown field":   {"SignCancelOrder", `{"marketIndex":0,"idx":1,"nonce":1}`},
+		"out of range":    {"SignCancelOrder", `{"marketIndex":256,"index":1,"nonce":1}`},
+		"invalid json":    {"SignCancelOrder", `{"marketIndex":`},
+		"unknown api key": {"SwitchAPIKey", `{"apiKeyIndex":4}`},
+	} {
+		if _, err := s.Call(call[0], call[1]); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	// AutoTransferFee is requested by omitting the fee, not with a negative one
+	for _, fee := range []string{"-0.000001", "-1"} {
+		_, err := s.Call("SignTransfer", `{"toAccountIndex":101,"usdcAmount":"1","fee":"`+fee+`","memo":"","nonce":1}`)
+		if ErrorCodeOf(err) != ErrorCodeInvalidParams || !strings.Contains(err.Error(), "negative") {
+			t.Errorf("fee %s: expected a negative fee error, got %v", fee, err)
+		}
+	}
+}
+
+// TestParamsMatchTxReq checks the params of the Sign methods keep the field names of the types.*TxReq requests,
+// which the bindings & their type definitions expose
+func TestParamsMatchTxReq(t *testing.T) {
+	requests := map[string]any{
+		"SignChangePubKey":        types.ChangePubKeyReq{},
+		"SignCreateOrder":         types.CreateOrderTxReq{},
+		"SignCreateGroupedOrders": types.CreateGroupedOrdersTxReq{},
+		"SignCancelOrder":         types.CancelOrderTxReq{},
+		"SignWithdraw":            types.WithdrawTxReq{},
+		"SignCreateSubAccount":    struct{}{},
+		"SignCancelAllOrders":     types.CancelAllOrdersTxReq{},
+		"SignModifyOrder":         types.ModifyOrderTxReq{},
+		"SignTransfer":            types.TransferTxReq{},
+		"SignCreatePublicPool":    types.CreatePublicPoolTxReq{},
+		"SignUpdatePublicPool":    types.UpdatePublicPoolTxReq{},
+		"SignMintShares":          types.MintSharesTxReq{},
+		"SignBurnShares":          types.BurnSharesTxReq{},
+		"SignUpdateLeverage":      types.UpdateLeverageTxReq{},
+		"SignUpdateMargin":        types.UpdateMarginTxReq{},
+	}
+
+	for _, name := range Methods() {
+		if !strings.HasPrefix(name, "Sign") {
+			continue
+		}
+		if _, ok := TxType(name); !ok {
+			t.Errorf("%s: no tx type", name)
+		}
+		req, ok := requests[name]
+		if !ok {
+			t.Errorf("%s: no request to compare its params with", name)
+			continue
+		}
+		params, err := NewParams(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{}
+		reqType := reflect.TypeOf(req)
+		for i := 0; i < reqType.NumField(); i++ {
+			expected = append(expected, LowerCamelCase(reqType.Field(i).Name))
+		}
+		expected = append(expected, "nonce")
+		if got := jsonNames(reflect.TypeOf(params).Elem()); !reflect.DeepEqual(got, expected) {
+			t.Errorf("%s: params are %v, expected %v", name, got, expected)
+		}
+	}
+
+	if got, expected := jsonNames(reflect.TypeOf(OrderParams{})), jsonNames(reflect.TypeOf(CreateOrderParams{})); !reflect.DeepEqual(append(got, "nonce"), expected) {
+		t.Errorf("orders of SignCreateGroupedOrders are %v, expected %v", got, expected)
+	}
+}
+
+// jsonNames returns the JSON names of the fields of a struct, including the ones of its embedded structs
+func jsonNames(t reflect.Type) []string {
+	names := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			names = append(names, jsonNames(f.Type)...)
+			continue
+		}
+		names = append(names, strings.Split(f.Tag.Get("json"), ",")[0])
+	}
+	return names
+}
+
+func withoutSig(t *testing.T, txInfo string) string {
+	obj := map[string]any{}
+	if err := json.Unmarshal([]byte(txInfo), &obj); err != nil {
+		t.Fatal(err)
+	}
+	delete(obj, "Sig")
+	b, err := json.Marshal(obj)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
@@ -0,0 +1,195 @@
+package core
+
+import (
+	"errors"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/signer"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// ErrorCode is the stable code of an error, returned by every binding alongside its message, so apps can react
+// to errors and localize them without parsing the messages. Codes are never reused, new ones are appended.
+type ErrorCode int
+
+const (
+	ErrorCodeNone           ErrorCode = 0
+	ErrorCodeUnknown        ErrorCode = 1 // not one of the errors below, the message tells why
+	ErrorCodeInvalidParams  ErrorCode = 2 // the params can't be decoded, or the method doesn't exist
+	ErrorCodeNotInitialized ErrorCode = 3 // no client was created for the handle, or no client for the api key
+	ErrorCodeInvalidKey     ErrorCode = 4 // the private or public key is invalid, or isn't the one registered on Lighter
+	ErrorCodeNonce          ErrorCode = 5 // the nonce was left out and couldn't be fetched from Lighter
+	ErrorCodeNetwork        ErrorCode = 6 // the request to Lighter failed, or the client has no url
+	ErrorCodeRejected       ErrorCode = 7 // Lighter responded with an error, e.g. it rejected the tx
+	ErrorCodeUnavailable    ErrorCode = 8 // the request isn't available in signonly builds
+
+	// ErrorCodeValidation is the first code of the validation errors of the tx fields, one per error of txtypes
+	ErrorCodeValidation ErrorCode = 1000
+)
+
+var categoryNames = map[ErrorCode]string{
+	ErrorCodeNone:           "None",
+	ErrorCodeUnknown:        "Unknown",
+	ErrorCodeInvalidParams:  "InvalidParams",
+	ErrorCodeNotInitialized: "NotInitialized",
+	ErrorCodeInvalidKey:     "InvalidKey",
+	ErrorCodeNonce:          "Nonce",
+	ErrorCodeNetwork:        "Network",
+	ErrorCodeRejected:       "Rejected",
+	ErrorCodeUnavailable:    "Unavailable",
+}
+
+// validationErrors maps the validation errors of txtypes to their codes
+var validationErrors = []struct {
+	code ErrorCode
+	name string
+	err  error
+}{
+	{1001, "AccountIndexTooLow", txtypes.ErrAccountIndexTooLow},
+	{1002, "AccountIndexTooHigh", txtypes.ErrAccountIndexTooHigh},
+	{1003, "NonceTooLow", txtypes.ErrNonceTooLow},
+	{1004, "InvalidCancelAllTimeInForce", txtypes.ErrInvalidCancelAllTimeInForce},
+	{1005, "OrderReduceOnlyInvalid", txtypes.ErrOrderReduceOnlyInvalid},
+	{1006, "OrderTriggerPriceInvalid", txtypes.ErrOrderTriggerPriceInvalid},
+	{1007, "OrderExpiryInvalid", txtypes.ErrOrderExpiryInvalid},
+	{1008, "ExpiredAtInvalid", txtypes.ErrExpiredAtInvalid},
+	{1009, "CancelAllTimeIsNotInRange", txtypes.ErrCancelAllTimeIsNotInRange},
+	{1010, "CancelAllTimeIsNotNil", txtypes.ErrCancelAllTimeisNotNill},
+	{1011, "PubKeyInvalid", txtypes.ErrPubKeyInvalid},
+	{1012, "ToAccountIndexTooLow", txtypes.ErrToAccountIndexTooLow},
+	{1013, "ToAccountIndexTooHigh", txtypes.ErrToAccountIndexTooHigh},
+	{1014, "FromAccountIndexTooLow", txtypes.ErrFromAccountIndexTooLow},
+	{1015, "FromAccountIndexTooHigh", txtypes.ErrFromAccountIndexTooHigh},
+	{1016, "ApiKeyIndexTooLow", txtypes.ErrApiKeyIndexTooLow},
+	{1017, "ApiKeyIndexTooHigh", txtypes.ErrApiKeyIndexTooHigh},
+	{1018, "PublicPoolIndexTooLow", txtypes.ErrPublicPoolIndexTooLow},
+	{1019, "PublicPoolIndexTooHigh", txtypes.ErrPublicPoolIndexTooHigh},
+	{1020, "InvalidPoolOperatorFee", txtypes.ErrInvalidPoolOperatorFee},
+	{1021, "InvalidPoolStatus", txtypes.ErrInvalidPoolStatus},
+	{1022, "PoolInitialTotalSharesTooLow", txtypes.ErrPoolInitialTotalSharesTooLow},
+	{1023, "PoolInitialTotalSharesTooHigh", txtypes.ErrPoolInitialTotalSharesTooHigh},
+	{1024, "PoolMinOperatorShareRateTooLow", txtypes.ErrPoolMinOperatorShareRateTooLow},
+	{1025, "PoolMinOperatorShareRateTooHigh", txtypes.ErrPoolMinOperatorShareRateTooHigh},
+	{1026, "PoolMintShareAmountTooLow", txtypes.ErrPoolMintShareAmountTooLow},
+	{1027, "PoolMintShareAmountTooHigh", txtypes.ErrPoolMintShareAmountTooHigh},
+	{1028, "PoolBurnShareAmountTooLow", txtypes.ErrPoolBurnShareAmountTooLow},
+	{1029, "PoolBurnShareAmountTooHigh", txtypes.ErrPoolBurnShareAmountTooHigh},
+	{1030, "WithdrawalAmountTooLow", txtypes.ErrWithdrawalAmountTooLow},
+	{1031, "WithdrawalAmountTooHigh", txtypes.ErrWithdrawalAmountTooHigh},
+	{1032, "TransferAmountTooLow", txtypes.ErrTransferAmountTooLow},
+	{1033, "TransferAmountTooHigh", txtypes.ErrTransferAmountTooHigh},
+	{1034, "MarketIndexTooLow", txtypes.ErrMarketIndexTooLow},
+	{1035, "MarketIndexTooHigh", txtypes.ErrMarketIndexTooHigh},
+	{1036, "MarketIndexMismatch", txtypes.ErrMarketIndexMismatch},
+	{1037, "InitialMarginFractionTooLow", txtypes.ErrInitialMarginFractionTooLow},
+	{1038, "InitialMarginFractionTooHigh", txtypes.ErrInitialMarginFractionTooHigh},
+	{1039, "ClientOrderIndexTooLow", txtypes.ErrClientOrderIndexTooLow},
+	{1040, "ClientOrderIndexTooHigh", txtypes.ErrClientOrderIndexTooHigh},
+	{1041, "ClientOrderIndexNotNil", txtypes.ErrClientOrderIndexNotNil},
+	{1042, "OrderIndexTooLow", txtypes.ErrOrderIndexTooLow},
+	{1043, "OrderIndexTooHigh", txtypes.ErrOrderIndexTooHigh},
+	{1044, "BaseAmountTooLow", txtypes.ErrBaseAmountTooLow},
+	{1045, "BaseAmountTooHigh", txtypes.ErrBaseAmountTooHigh},
+	{1046, "BaseAmountsNotEqual", txtypes.ErrBaseAmountsNotEqual},
+	{1047, "BaseAmountNotNil", txtypes.ErrBaseAmountNotNil},
+	{1048, "PriceTooLow", txtypes.ErrPriceTooLow},
+	{1049, "PriceTooHigh", txtypes.ErrPriceTooHigh},
+	{1050, "IsAskInvalid", txtypes.ErrIsAskInvalid},
+	{1051, "OrderTypeInvalid", txtypes.ErrOrderTypeInvalid},
+	{1052, "OrderTimeInForceInvalid", txtypes.ErrOrderTimeInForceInvalid},
+	{1053, "GroupingTypeInvalid", txtypes.ErrGroupingTypeInvalid},
+	{1054, "OrderGroupSizeInvalid", txtypes.ErrOrderGroupSizeInvalid},
+	{1055, "InvalidSignature", txtypes.ErrInvalidSignature},
+	{1056, "InvalidMarginMode", txtypes.ErrInvalidMarginMode},
+	{1057, "CancelModeInvalid", txtypes.ErrCancelModeInvalid},
+	{1058, "InvalidUpdateMarginDirection", txtypes.ErrInvalidUpdateMarginDirection},
+	{1059, "TransferFeeNegative", txtypes.ErrTransferFeeNegative},
+	{1060, "TransferFeeTooHigh", txtypes.ErrTransferFeeTooHigh},
+}
+
+// String returns the name of the code, like "NotInitialized" or "AccountIndexTooLow"
+func (c ErrorCode) String() string {
+	if name, ok := categoryNames[c]; ok {
+		return name
+	}
+	for _, v := range validationErrors {
+		if v.code == c {
+			return v.name
+		}
+	}
+	return categoryNames[ErrorCodeUnknown]
+}
+
+// ErrorCodes returns the codes by name, for the bindings exposing the catalog
+func ErrorCodes() map[string]ErrorCode {
+	codes := make(map[string]ErrorCode, len(categoryNames)+len(validationErrors))
+	for code, name := range categoryNames {
+		codes[name] = code
+	}
+	for _, v := range validationErrors {
+		codes[v.name] = v.code
+	}
+	return codes
+}
+
+// codedError sets the code of the errors which can't be recognized by their type
+type codedError struct {
+	code ErrorCode
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+// WithErrorCode sets the code of err returned by ErrorCodeOf, e.g. for the params a binding fails to convert
+func WithErrorCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &codedError{code: code, err: err}
+}
+
+// ErrorCodeOf returns the code of an error returned by the Session, or ErrorCodeNone if err is nil
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ErrorCodeNone
+	}
+
+	var coded *codedError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	for _, v := range validationErrors {
+		if errors.Is(err, v.err) {
+			return v.code
+		}
+	}
+	if errors.Is(err, signer.ErrInvalidSignature) {
+		return ErrorCodeInvalidKey
+	}
+	if errors.Is(err, client.ErrSignOnly) {
+		return ErrorCodeUnavailable
+	}
+	if errors.As(err, new(*client.NonceError)) {
+		return ErrorCodeNonce
+	}
+	if errors.As(err, new(*client.APIError)) {
+		return ErrorCodeRejected
+	}
+	var statusErr *client.StatusError
+	if errors.As(err, &statusErr) {
+		if statusErr.StatusCode >= 400 && statusErr.StatusCode < 500 {
+			return ErrorCodeRejected
+		}
+		return ErrorCodeNetwork
+	}
+	if errors.As(err, new(*client.RequestError)) {
+		return ErrorCodeNetwork
+	}
+	return ErrorCodeUnknown
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestErrorCodes(t *testing.T) {
+	s := NewSession()
+	if _, err := s.Call("SignCancelOrder", `{"marketIndex":1,"index":7,"nonce":1}`); ErrorCodeOf(err) != ErrorCodeNotInitialized {
+		t.Fatalf("expected NotInitialized, got %v", err)
+	}
+	if err := s.CreateClient("", "0x12", 304, 3, 100); ErrorCodeOf(err) != ErrorCodeInvalidKey {
+		t.Fatalf("expected InvalidKey, got %v", err)
+	}
+
+	s = newTestSession(t)
+	tests := []struct {
+		method, params string
+		code           ErrorCode
+	}{
+		{"Unknown", "", ErrorCodeInvalidParams},
+		{"SignCancelOrder", `{"marketIndex":1,"index":"7"}`, ErrorCodeInvalidParams},
+		{"SignCancelOrder", `{"marketIndex":1,"index":7}`, ErrorCodeNonce},
+		{"SignCancelOrder", `{"marketIndex":1,"index":7,"nonce":-5}`, 1003},
+		{"SignWithdraw", `{"usdcAmount":"0","nonce":1}`, 1030},
+		{"SignChangePubKey", `{"pubKey":"0x12","nonce":1}`, ErrorCodeInvalidKey},
+		{"SwitchAPIKey", `{"apiKeyIndex":9}`, ErrorCodeNotInitialized},
+		{"GetNextNonce", "", ErrorCodeNetwork},
+	}
+	for _, test := range tests {
+		_, err := s.Call(test.method, test.params)
+		if code := ErrorCodeOf(err); code != test.code {
+			t.Errorf("%s(%s) returned %v (%s), expected %s", test.method, test.params, err, code, test.code)
+		}
+	}
+
+	if code := ErrorCodeOf(nil); code != ErrorCodeNone {
+		t.Errorf("expected None for nil, got %s", code)
+	}
+	if code := ErrorCodeOf(errors.New("other")); code != ErrorCodeUnknown {
+		t.Errorf("expected Unknown, got %s", code)
+	}
+	if code := ErrorCodeOf(fmt.Errorf("wrapped. err: %w", ErrClientNotCreated)); code != ErrorCodeNotInitialized {
+		t.Errorf("expected NotInitialized for a wrapped error, got %s", code)
+	}
+}
+
+func TestErrorCodeNames(t *testing.T) {
+	codes := ErrorCodes()
+	seen := map[ErrorCode]string{}
+	for name, code := range codes {
+		if other, ok := seen[code]; ok {
+			t.Errorf("%s and %s have the same code %d", name, other, code)
+		}
+		seen[code] = name
+		if code.String() != name {
+			t.Errorf("code %d is named %s, expected %s", code, code.String(), name)
+		}
+	}
+	if codes["WithdrawalAmountTooLow"] != 1030 || codes["NotInitialized"] != 3 {
+		t.Error("the codes changed, they have to stay stable")
+	}
+}
+
+// TestValidationErrorsComplete checks every error declared by txtypes has a code, so a new validation
+// error can't be returned to the apps as Unknown
+func TestValidationErrorsComplete(t *testing.T) {
+	// the names of the codes fix the typos of these errors
+	renamed := map[string]string{"ErrCancelAllTimeisNotNill": "CancelAllTimeIsNotNil"}
+
+	file, err := parser.ParseFile(token.NewFileSet(), "../types/txtypes/errors.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	declared := 0
+	ast.Inspect(file, func(n ast.Node) bool {
+		spec, ok := n.(*ast.ValueSpec)
+		if !ok {
+			return true
+		}
+		for _, ident := range spec.Names {
+			if !strings.HasPrefix(ident.Name, "Err") {
+				continue
+			}
+			declared++
+			name, ok := renamed[ident.Name]
+			if !ok {
+				name = strings.TrimPrefix(ident.Name, "Err")
+			}
+			if _, ok := ErrorCodes()[name]; !ok {
+				t.Errorf("txtypes.%s has no code in validationErrors", ident.Name)
+			}
+		}
+		return true
+	})
+	if declared != len(validationErrors) {
+		t.Errorf("txtypes declares %d errors, validationErrors has %d", declared, len(validationErrors))
+	}
+
+	for i, v := range validationErrors {
+		for _, other := range validationErrors[i+1:] {
+			if v.err == other.err {
+				t.Errorf("%s and %s map the same error", v.name, other.name)
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/elliottech/lighter-go/client"
+)
+
+// The params of the methods sending requests to Lighter, with the url of the current client
+
+type SendTxParams struct {
+	TxType uint8  `json:"txType"` // see TxType
+	TxInfo string `json:"txInfo"` // as returned by the Sign methods
+}
+
+type GetNextNonceParams struct{}
+
+type GetApiKeysParams struct {
+	AccountIndex int64 `json:"accountIndex"`
+	ApiKeyIndex  uint8 `json:"apiKeyIndex"`
+}
+
+type GetTransferFeeInfoParams struct {
+	ToAccountIndex int64 `json:"toAccountIndex"`
+}
+
+type SetFatFingerProtectionParams struct {
+	Enabled bool `json:"enabled"`
+}
+
+// httpClient returns the HTTP client of the current client, or an error if it was created without url
+func (s *Session) httpClient() (*client.HTTPClient, *client.TxClient, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return nil, nil, err
+	}
+	if txClient.HTTP() == nil {
+		return nil, nil, WithErrorCode(ErrorCodeNetwork, fmt.Errorf("client has no url, create it with the url of Lighter to send requests"))
+	}
+	return txClient.HTTP(), txClient, nil
+}
+
+// SendTx sends a signed tx and returns its hash. Orders too far from the market price are rejected,
+// unless the fat finger protection was disabled with SetFatFingerProtection.
+func (s *Session) SendTx(p SendTxParams) (string, error) {
+	httpClient, _, err := s.httpClient()
+	if err != nil {
+		return "", err
+	}
+	return httpClient.SendRawTxInfo(p.TxType, p.TxInfo)
+}
+
+// GetNextNonce returns the next nonce of the current api key, as a decimal string
+func (s *Session) GetNextNonce() (string, error) {
+	httpClient, txClient, err := s.httpClient()
+	if err != nil {
+		return "", err
+	}
+	nonce, err := httpClient.GetNextNonce(txClient.GetAccountIndex(), txClient.GetApiKeyIndex())
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(nonce, 10), nil
+}
+
+// GetApiKeys returns the api keys registered on Lighter, as a JSON array of client.ApiKey
+func (s *Session) GetApiKeys(p GetApiKeysParams) (string, error) {
+	httpClient, _, err := s.httpClient()
+	if err != nil {
+		return "", err
+	}
+	keys, err := httpClient.GetApiKey(p.AccountIndex, p.ApiKeyIndex)
+	if err != nil {
+		return "", err
+	}
+	if keys.ApiKeys == nil {
+		keys.ApiKeys = []*client.ApiKey{}
+	}
+	b, err := json.Marshal(keys.ApiKeys)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// GetTransferFeeInfo returns the fee of a transfer from the account of the current client, as a USDC decimal string
+func (s *Session) GetTransferFeeInfo(p GetTransferFeeInfoParams) (string, error) {
+	httpClient, txClient, err := s.httpClient()
+	if err != nil {
+		return "", err
+	}
+	authToken, err := txClient.GetCachedAuthToken()
+	if err != nil {
+		return "", err
+	}
+	feeInfo, err := httpClient.GetTransferFeeInfo(txClient.GetAccountIndex(), p.ToAccountIndex, authToken)
+	if err != nil {
+		return "", err
+	}
+	return feeInfo.TransferFee.String(), nil
+}
+
+// SetFatFingerProtection enables or disables the price protection of the orders sent with SendTx.
+// It's enabled by default.
+func (s *Session) SetFatFingerProtection(enabled bool) error {
+	httpClient, _, err := s.httpClient()
+	if err != nil {
+		return err
+	}
+	httpClient.SetFatFingerProtection(enabled)
+	return nil
+}
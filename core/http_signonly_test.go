@@ -0,0 +1,28 @@
+//go:build signonly
+
+package core
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elliottech/lighter-go/client"
+)
+
+func TestSignOnly(t *testing.T) {
+	s := NewSession()
+	if err := s.CreateClient("https://mainnet.zklighter.elliot.ai", testPrivateKey, 304, 3, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.Call("GetNextNonce", ""); !errors.Is(err, client.ErrSignOnly) {
+		t.Fatalf("expected ErrSignOnly, got %v", err)
+	}
+	// the nonce is fetched when it's left out
+	if _, err := s.Call("SignCancelOrder", `{"marketIndex":0,"index":1}`); !errors.Is(err, client.ErrSignOnly) {
+		t.Fatalf("expected ErrSignOnly, got %v", err)
+	}
+	if _, err := s.Call("SignCancelOrder", `{"marketIndex":0,"index":1,"nonce":1}`); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,214 @@
+//go:build !signonly
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/types"
+)
+
+// countingTransport counts the requests sent through it
+type countingTransport struct {
+	requests atomic.Int64
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.requests.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newLighterServer(t *testing.T, sentTxs chan<- map[string]string) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/sendTx", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		sentTxs <- map[string]string{
+			"tx_type":          r.PostForm.Get("tx_type"),
+			"tx_info":          r.PostForm.Get("tx_info"),
+			"price_protection": r.PostForm.Get("price_protection"),
+		}
+		fmt.Fprint(w, `{"code":200,"tx_hash":"0xabc"}`)
+	})
+	mux.HandleFunc("/api/v1/nextNonce", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":200,"nonce":9007199254740993}`)
+	})
+	mux.HandleFunc("/api/v1/apikeys", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"code":200,"api_keys":[{"account_index":%s,"api_key_index":%s,"nonce":1,"public_key":"00"}]}`,
+			r.URL.Query().Get("account_index"), r.URL.Query().Get("api_key_index"))
+	})
+	mux.HandleFunc("/api/v1/transferFeeInfo", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":200,"transfer_fee_usdc":500000}`)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPMethods(t *testing.T) {
+	sentTxs := make(chan map[string]string, 1)
+	server := newLighterServer(t, sentTxs)
+	transport := &countingTransport{}
+
+	s := NewSession()
+	s.SetTransport(transport)
+	if err := s.CreateClient(server.URL, testPrivateKey, 304, 3, 100); err != nil {
+		t.Fatal(err)
+	}
+
+	nonce, err := s.Call("GetNextNonce", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nonce != "9007199254740993" {
+		t.Fatalf("unexpected nonce %s", nonce)
+	}
+
+	keys, err := s.Call("GetApiKeys", `{"accountIndex":100,"apiKeyIndex":3}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var parsed []struct {
+		AccountIndex int64 `json:"account_index"`
+		ApiKeyIndex  uint8 `json:"api_key_index"`
+	}
+	if err := json.Unmarshal([]byte(keys), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	if len(parsed) != 1 || parsed[0].AccountIndex != 100 || parsed[0].ApiKeyIndex != 3 {
+		t.Fatalf("unexpected api keys %s", keys)
+	}
+
+	fee, err := s.Call("GetTransferFeeInfo", `{"toAccountIndex":101}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee != "0.5" {
+		t.Fatalf("unexpected fee %s", fee)
+	}
+
+	txInfo, err := s.Call("SignCancelOrder", `{"marketIndex":1,"index":7,"nonce":44}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txType, _ := TxType("SignCancelOrder")
+	for _, fatFingerProtection := range []bool{true, false} {
+		if err := s.SetFatFingerProtection(fatFingerProtection); err != nil {
+			t.Fatal(err)
+		}
+		txHash, err := s.SendTx(SendTxParams{TxType: txType, TxInfo: txInfo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if txHash != "0xabc" {
+			t.Fatalf("unexpected tx hash %s", txHash)
+		}
+
+		sent := <-sentTxs
+		if sent["tx_type"] != fmt.Sprint(txType) || sent["tx_info"] != txInfo {
+			t.Fatalf("unexpected tx sent %v", sent)
+		}
+		if expected := map[bool]string{true: "", false: "false"}[fatFingerProtection]; sent["price_protection"] != expected {
+			t.Fatalf("price_protection is %q with fat finger protection %v", sent["price_protection"], fatFingerProtection)
+		}
+	}
+
+	if transport.requests.Load() != 5 {
+		t.Fatalf("expected the 5 requests to use the transport of the session, got %d", transport.requests.Load())
+	}
+}
+
+func TestHTTPMethodsWithoutURL(t *testing.T) {
+	s := newTestSession(t)
+	if _, err := s.GetNextNonce(); err == nil {
+		t.Fatal("expected an error for a client without url")
+	}
+}
+
+func TestHTTPErrorCodes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v1/sendTx", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"code":21120,"message":"invalid signature"}`)
+	})
+	mux.HandleFunc("/api/v1/nextNonce", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	s := NewSession()
+	if err := s.CreateClient(server.URL, testPrivateKey, 304, 3, 100); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Call("SendTx", `{"txType":15,"txInfo":"{}"}`); ErrorCodeOf(err) != ErrorCodeRejected {
+		t.Errorf("expected Rejected, got %v", err)
+	}
+	if _, err := s.Call("GetNextNonce", ""); ErrorCodeOf(err) != ErrorCodeNetwork {
+		t.Errorf("expected Network, got %v", err)
+	}
+	if _, err := s.Call("SignCancelOrder", `{"marketIndex":1,"index":7}`); ErrorCodeOf(err) != ErrorCodeNonce {
+		t.Errorf("expected Nonce, got %v", err)
+	}
+
+	server.Close()
+	if _, err := s.Call("GetNextNonce", ""); ErrorCodeOf(err) != ErrorCodeNetwork {
+		t.Errorf("expected Network for a closed server, got %v", err)
+	}
+}
+
+func TestMaxTransferFee(t *testing.T) {
+	server := newLighterServer(t, make(chan map[string]string, 1))
+
+	s := NewSession()
+	if err := s.CreateClient(server.URL, testPrivateKey, 304, 3, 100); err != nil {
+		t.Fatal(err)
+	}
+	transfer := TransferParams{ToAccountIndex: 101, USDCAmount: types.OneUSDC, Fee: client.AutoTransferFee, Memo: strings.Repeat(" ", 32), Nonce: 1}
+
+	// the fee of the server is 0.5 USDC
+	if _, err := s.Call("SetMaxTransferFee", `{"maxFee":"0.25"}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignTransfer(transfer); err == nil {
+		t.Fatal("expected an error for a fee higher than the max transfer fee")
+	}
+
+	// the max transfer fee applies to the api keys added afterwards
+	if err := s.AddAPIKey(testPrivateKey, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SwitchAPIKey(4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.SignTransfer(transfer); err == nil {
+		t.Fatal("expected an error for a fee higher than the max transfer fee of an added api key")
+	}
+
+	if err := s.SetMaxTransferFee(types.OneUSDC); err != nil {
+		t.Fatal(err)
+	}
+	txInfo, err := s.SignTransfer(transfer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tx struct{ Fee int64 }
+	if err := json.Unmarshal([]byte(txInfo), &tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Fee != int64(types.OneUSDC/2) {
+		t.Fatalf("expected a fee of 0.5 USDC, got %d", tx.Fee)
+	}
+
+	if err := s.SetMaxTransferFee(-1); ErrorCodeOf(err) != ErrorCodeInvalidParams {
+		t.Fatalf("expected InvalidParams for a negative max transfer fee, got %v", err)
+	}
+}
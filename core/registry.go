@@ -0,0 +1,60 @@
+package core
+
+import (
+	"fmt"
+	"sync"
+)
+
+// Registry maps opaque handles to sessions, for bindings which can't hold a Go pointer, like the C one.
+// Handles start at 1, so 0 is never a valid handle, and are not reused. It's safe for concurrent use.
+type Registry struct {
+	mu       sync.RWMutex
+	sessions map[int64]*Session
+	last     int64
+}
+
+func NewRegistry() *Registry {
+	return &Registry{sessions: make(map[int64]*Session)}
+}
+
+// Add registers the session and returns its handle
+func (r *Registry) Add(s *Session) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.last++
+	r.sessions[r.last] = s
+	return r.last
+}
+
+// Get returns the session of the handle, or an error if it was never created or was already removed
+func (r *Registry) Get(handle int64) (*Session, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.sessions[handle]
+	if !ok {
+		return nil, WithErrorCode(ErrorCodeNotInitialized, fmt.Errorf("invalid client handle %d", handle))
+	}
+	return s, nil
+}
+
+// Remove releases the handle. Calls already running with its session finish normally.
+func (r *Registry) Remove(handle int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.sessions[handle]; !ok {
+		return WithErrorCode(ErrorCodeNotInitialized, fmt.Errorf("invalid client handle %d", handle))
+	}
+	delete(r.sessions, handle)
+	return nil
+}
+
+// Len returns the number of live handles
+func (r *Registry) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return len(r.sessions)
+}
@@ -0,0 +1,299 @@
+// Package core implements the API shared by the C, WASM and mobile bindings.
+// Argument defaults, validation and the JSON returned to the apps live here, and the bindings only convert
+// their arguments & results, so every feature lands in every binding at once.
+package core
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/signer"
+	"github.com/elliottech/lighter-go/types"
+	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
+	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
+)
+
+// Version of the library. The major version changes when the API of a binding breaks.
+const Version = "2.0.0"
+
+// AutoNonce and DefaultOrderExpiry can be passed instead of a nonce or an order expiry,
+// to fetch the nonce from Lighter or to expire the order in 28 days.
+// CachedAuthToken can be passed instead of an auth token deadline, to reuse a token refreshed automatically.
+const (
+	AutoNonce          int64 = -1
+	DefaultOrderExpiry int64 = -1
+	CachedAuthToken    int64 = -1
+
+	defaultOrderExpiryDuration = time.Hour * 24 * 28
+	defaultAuthTokenDuration   = time.Hour * 7
+)
+
+var ErrClientNotCreated = WithErrorCode(ErrorCodeNotInitialized, errors.New("client is not created, call CreateClient() first"))
+
+// Session holds the clients of one account, one per api key, and the one currently used for signing.
+// It's safe for concurrent use.
+type Session struct {
+	mu             sync.RWMutex
+	txClient       *client.TxClient
+	clients        map[uint8]*client.TxClient
+	newHTTPClient  func(url string) *client.HTTPClient // replaced by SetTransport
+	maxTransferFee types.USDC
+}
+
+func NewSession() *Session {
+	return &Session{
+		clients:        make(map[uint8]*client.TxClient),
+		newHTTPClient:  client.NewHTTPClient,
+		maxTransferFee: client.DefaultMaxTransferFee,
+	}
+}
+
+// GenerateAPIKey generates a new API key pair, hex encoded. The key is random if seed is empty.
+func GenerateAPIKey(seed string) (privateKey, publicKey string) {
+	seedP := &seed
+	if seed == "" {
+		seedP = nil
+	}
+
+	key := curve.SampleScalar(seedP)
+	return "0x" + hex.EncodeToString(key.ToLittleEndianBytes()), "0x" + hex.EncodeToString(schnorr.SchnorrPkFromSk(key).ToLittleEndianBytes())
+}
+
+// APIKeyIndex checks an api key index passed by a binding as a wider integer, which would select another key if truncated
+func APIKeyIndex(apiKeyIndex int64) (uint8, error) {
+	var params ParamChecker
+	keyIndex := params.Uint8("api key index", apiKeyIndex)
+	return keyIndex, params.Err()
+}
+
+// ParamChecker checks the params passed by a binding as wider integers, like a C int for a uint8 market index,
+// which would become other values if truncated. Err returns the first param out of range, with ErrorCodeInvalidParams.
+type ParamChecker struct {
+	err error
+}
+
+func (c *ParamChecker) Uint8(name string, v int64) uint8 {
+	return checkUint[uint8](c, name, v, math.MaxUint8)
+}
+
+func (c *ParamChecker) Uint16(name string, v int64) uint16 {
+	return checkUint[uint16](c, name, v, math.MaxUint16)
+}
+
+func (c *ParamChecker) Uint32(name string, v int64) uint32 {
+	return checkUint[uint32](c, name, v, math.MaxUint32)
+}
+
+func (c *ParamChecker) Err() error {
+	return c.err
+}
+
+func checkUint[T uint8 | uint16 | uint32](c *ParamChecker, name string, v int64, max int64) T {
+	if v < 0 || v > max {
+		if c.err == nil {
+			c.err = WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("invalid %s %d, should be within [0, %d]", name, v, max))
+		}
+		return 0
+	}
+	return T(v)
+}
+
+// Client returns the client used for signing, or ErrClientNotCreated
+func (s *Session) Client() (*client.TxClient, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.txClient == nil {
+		return nil, ErrClientNotCreated
+	}
+	return s.txClient, nil
+}
+
+// CreateClient creates a client for the api key and makes it the one used for signing.
+// Clients of the same account share their ClientOrderIDAllocator.
+func (s *Session) CreateClient(url, privateKey string, chainId uint32, apiKeyIndex uint8, accountIndex int64) error {
+	if accountIndex <= 0 {
+		return WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("invalid account index"))
+	}
+
+	s.mu.RLock()
+	newHTTPClient := s.newHTTPClient
+	s.mu.RUnlock()
+
+	txClient, err := client.NewTxClient(newHTTPClient(url), privateKey, accountIndex, apiKeyIndex, chainId)
+	if err != nil {
+		return WithErrorCode(ErrorCodeInvalidKey, fmt.Errorf("error occurred when creating TxClient. err: %w", err))
+	}
+	s.addClient(txClient)
+	return nil
+}
+
+// CreateClientWithKeyManager is CreateClient for a key which isn't held as bytes, like a signer.KeyProvider
+func (s *Session) CreateClientWithKeyManager(url string, keyManager signer.KeyManager, chainId uint32, apiKeyIndex uint8, accountIndex int64) error {
+	if accountIndex <= 0 {
+		return WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("invalid account index"))
+	}
+
+	s.mu.RLock()
+	newHTTPClient := s.newHTTPClient
+	s.mu.RUnlock()
+
+	s.addClient(client.NewTxClientWithKeyManager(newHTTPClient(url), keyManager, accountIndex, apiKeyIndex, chainId))
+	return nil
+}
+
+// AddAPIKey creates a client for another api key of the account, using the url & chain id of the current client.
+// Unlike CreateClient, the current client is not changed, use SwitchAPIKey to sign with the new key.
+func (s *Session) AddAPIKey(privateKey string, apiKeyIndex uint8) error {
+	current, err := s.Client()
+	if err != nil {
+		return err
+	}
+
+	txClient, err := client.NewTxClient(current.HTTP(), privateKey, current.GetAccountIndex(), apiKeyIndex, current.GetChainId())
+	if err != nil {
+		return WithErrorCode(ErrorCodeInvalidKey, fmt.Errorf("error occurred when creating TxClient. err: %w", err))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.clients[apiKeyIndex]; ok {
+		return WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("api key %d is already registered", apiKeyIndex))
+	}
+	txClient.SetClientOrderIDAllocator(current.ClientOrderIDAllocator())
+	txClient.SetMaxTransferFee(s.maxTransferFee)
+	s.clients[apiKeyIndex] = txClient
+	return nil
+}
+
+// addClient makes txClient the client used for signing
+func (s *Session) addClient(txClient *client.TxClient) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, other := range s.clients {
+		if other.GetAccountIndex() == txClient.GetAccountIndex() {
+			txClient.SetClientOrderIDAllocator(other.ClientOrderIDAllocator())
+			break
+		}
+	}
+	txClient.SetMaxTransferFee(s.maxTransferFee)
+	s.clients[txClient.GetApiKeyIndex()] = txClient
+	s.txClient = txClient
+}
+
+// SetMaxTransferFee sets the highest fee accepted by SignTransfer when the fee is client.AutoTransferFee, for every api key.
+// It's client.DefaultMaxTransferFee (10 USDC) by default.
+func (s *Session) SetMaxTransferFee(maxFee types.USDC) error {
+	if maxFee < 0 {
+		return WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("max transfer fee should not be negative"))
+	}
+	if err := maxFee.Validate(); err != nil {
+		return WithErrorCode(ErrorCodeInvalidParams, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.maxTransferFee = maxFee
+	for _, c := range s.clients {
+		c.SetMaxTransferFee(maxFee)
+	}
+	return nil
+}
+
+// ConfigureClientOrderIndex configures the allocator used for AutoClientOrderIndex, for every api key of the account.
+// lastClientOrderIndex is the last index allocated by a previous run, or 0 if unknown.
+func (s *Session) ConfigureClientOrderIndex(tagBits uint8, tag, lastClientOrderIndex int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.txClient == nil {
+		return ErrClientNotCreated
+	}
+
+	allocator, err := client.NewClientOrderIDAllocator(tagBits, tag)
+	if err != nil {
+		return err
+	}
+	if err := allocator.Restore(lastClientOrderIndex); err != nil {
+		return err
+	}
+	for _, c := range s.clients {
+		if c.GetAccountIndex() == s.txClient.GetAccountIndex() {
+			c.SetClientOrderIDAllocator(allocator)
+		}
+	}
+	return nil
+}
+
+// CheckClient checks a client was created for the api key & account, and that its key is the one registered on Lighter
+func (s *Session) CheckClient(apiKeyIndex uint8, accountIndex int64) error {
+	s.mu.RLock()
+	txClient, ok := s.clients[apiKeyIndex]
+	s.mu.RUnlock()
+	if !ok {
+		return WithErrorCode(ErrorCodeNotInitialized, fmt.Errorf("api key not registered"))
+	}
+
+	if txClient.GetAccountIndex() != accountIndex {
+		return WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("accountIndex does not match. expected %v but got %v", txClient.GetAccountIndex(), accountIndex))
+	}
+
+	if txClient.HTTP() == nil {
+		return WithErrorCode(ErrorCodeNetwork, fmt.Errorf("client has no url, the api key can't be checked"))
+	}
+	key, err := txClient.HTTP().GetApiKey(accountIndex, apiKeyIndex)
+	if err != nil {
+		return fmt.Errorf("failed to get Api Keys. err: %w", err)
+	}
+	if len(key.ApiKeys) == 0 {
+		return WithErrorCode(ErrorCodeInvalidKey, fmt.Errorf("api key %d is not registered on Lighter", apiKeyIndex))
+	}
+
+	pubKeyBytes := txClient.GetKeyManager().PubKeyBytes()
+	pubKeyStr := hex.EncodeToString(pubKeyBytes[:])
+
+	ak := key.ApiKeys[0]
+	if ak.PublicKey != pubKeyStr {
+		return WithErrorCode(ErrorCodeInvalidKey, fmt.Errorf("private key does not match the one on Lighter. ownPubKey: %s response: %+v", pubKeyStr, ak))
+	}
+	return nil
+}
+
+// SwitchAPIKey makes the client of the api key the one used for signing
+func (s *Session) SwitchAPIKey(apiKeyIndex uint8) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	txClient, ok := s.clients[apiKeyIndex]
+	if !ok {
+		return WithErrorCode(ErrorCodeNotInitialized, fmt.Errorf("no client initialized for api key %d", apiKeyIndex))
+	}
+	s.txClient = txClient
+	return nil
+}
+
+// CreateAuthToken creates an auth token valid until deadline, a unix timestamp in seconds.
+// A deadline of 0 creates a token valid for 7 hours, and CachedAuthToken returns a cached token,
+// which is refreshed automatically and valid for at least 1 hour.
+func (s *Session) CreateAuthToken(deadline int64) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	switch deadline {
+	case CachedAuthToken:
+		return txClient.GetCachedAuthToken()
+	case 0:
+		deadline = txClient.Now().Add(defaultAuthTokenDuration).Unix()
+	}
+	return txClient.GetAuthToken(time.Unix(deadline, 0))
+}
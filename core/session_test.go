@@ -0,0 +1,192 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/types"
+)
+
+const otherPrivateKey = "0x2827262524232221201f1e1d1c1b1a191817161514131211100f0e0d0c0b0a090807060504030201"
+
+func TestAddAPIKey(t *testing.T) {
+	s := newTestSession(t)
+
+	if err := s.AddAPIKey(otherPrivateKey, 3); err == nil {
+		t.Fatal("expected an error when adding an api key twice")
+	}
+	if err := s.AddAPIKey(otherPrivateKey, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	// the current client doesn't change until SwitchAPIKey
+	current, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current.GetApiKeyIndex() != 3 {
+		t.Fatalf("expected api key 3, got %d", current.GetApiKeyIndex())
+	}
+
+	if err := s.SwitchAPIKey(4); err != nil {
+		t.Fatal(err)
+	}
+	added, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if added.GetAccountIndex() != current.GetAccountIndex() || added.GetChainId() != current.GetChainId() {
+		t.Fatalf("added api key has account %d & chain %d, expected %d & %d",
+			added.GetAccountIndex(), added.GetChainId(), current.GetAccountIndex(), current.GetChainId())
+	}
+	if added.ClientOrderIDAllocator() != current.ClientOrderIDAllocator() {
+		t.Fatal("api keys of the same account should share the client order index allocator")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	a, b := NewSession(), NewSession()
+
+	ha, hb := r.Add(a), r.Add(b)
+	if ha == 0 || hb == 0 || ha == hb {
+		t.Fatalf("invalid handles %d & %d", ha, hb)
+	}
+	if s, err := r.Get(hb); err != nil || s != b {
+		t.Fatalf("Get(%d) = %p, %v", hb, s, err)
+	}
+
+	if err := r.Remove(ha); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := r.Get(ha); err == nil {
+		t.Fatal("expected an error for a removed handle")
+	}
+	if err := r.Remove(ha); err == nil {
+		t.Fatal("expected an error when removing a handle twice")
+	}
+	if r.Len() != 1 {
+		t.Fatalf("expected 1 live handle, got %d", r.Len())
+	}
+}
+
+func TestCreateAuthToken(t *testing.T) {
+	s := newTestSession(t)
+	txClient, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 0 creates a new token valid for 7 hours
+	token, err := s.CreateAuthToken(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := fmt.Sprintf("%d:100:3:", txClient.Now().Add(7*time.Hour).Unix()); !strings.HasPrefix(token, expected) {
+		t.Fatalf("expected a token starting with %s, got %s", expected, token)
+	}
+
+	cached, err := s.CreateAuthToken(CachedAuthToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := s.CreateAuthToken(CachedAuthToken); again != cached {
+		t.Fatal("expected the cached token to be reused")
+	}
+	if expected := fmt.Sprintf("%d:100:3:", txClient.Now().Add(client.DefaultAuthTokenLifetime).Unix()); !strings.HasPrefix(cached, expected) {
+		t.Fatalf("expected a token starting with %s, got %s", expected, cached)
+	}
+
+	if _, err := s.CreateAuthToken(txClient.Now().Add(8 * time.Hour).Unix()); err == nil {
+		t.Fatal("expected an error for a deadline over 7 hours")
+	}
+}
+
+// TestConcurrentConfiguration changes the clients while other goroutines sign with them, for go test -race
+func TestConcurrentConfiguration(t *testing.T) {
+	s := newTestSession(t)
+	if err := s.AddAPIKey(otherPrivateKey, 4); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				if _, err := s.Call("SignCreateOrder", `{"marketIndex":1,"clientOrderIndex":-1,"baseAmount":1000,"price":300000,"isAsk":1,"type":0,"timeInForce":1,"nonce":1}`); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	signed := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(signed)
+	}()
+
+	for j := 0; ; j++ {
+		select {
+		case <-signed:
+			return
+		default:
+		}
+		if err := s.ConfigureClientOrderIndex(8, int64(j%256), 0); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SetMaxTransferFee(types.USDC(j%10) * types.OneUSDC); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.SwitchAPIKey(uint8(3 + j%2)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestDefaultSession(t *testing.T) {
+	if _, err := Call("SignCancelOrder", `{"marketIndex":1,"index":7,"nonce":1}`); ErrorCodeOf(err) != ErrorCodeNotInitialized {
+		t.Fatalf("expected the default session to have no client, got %v", err)
+	}
+	if _, err := Call("GenerateAPIKey", `{"seed":"default"}`); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParamChecker(t *testing.T) {
+	var params ParamChecker
+	if params.Uint8("market index", 255) != 255 || params.Uint16("initial margin fraction", 65535) != 65535 ||
+		params.Uint32("price", 1<<32-1) != 1<<32-1 || params.Err() != nil {
+		t.Fatalf("expected the max values to be accepted, got %v", params.Err())
+	}
+
+	for _, check := range []func(*ParamChecker){
+		func(p *ParamChecker) { p.Uint8("market index", 256) },
+		func(p *ParamChecker) { p.Uint8("market index", -1) },
+		func(p *ParamChecker) { p.Uint16("initial margin fraction", 1<<16) },
+		func(p *ParamChecker) { p.Uint16("initial margin fraction", -1) },
+		func(p *ParamChecker) { p.Uint32("price", 1<<32) },
+		func(p *ParamChecker) { p.Uint32("price", -1) },
+	} {
+		var params ParamChecker
+		check(&params)
+		if err := params.Err(); ErrorCodeOf(err) != ErrorCodeInvalidParams {
+			t.Errorf("expected an invalid params error, got %v", err)
+		}
+	}
+
+	// the first param out of range is reported
+	params = ParamChecker{}
+	params.Uint8("market index", 300)
+	params.Uint8("is ask", 2)
+	params.Uint8("order type", -1)
+	if err := params.Err(); err == nil || !strings.Contains(err.Error(), "market index 300") {
+		t.Fatalf("expected the market index error, got %v", err)
+	}
+}
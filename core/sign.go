@@ -0,0 +1,551 @@
+package core
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+
+	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/types"
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// The params of the Sign methods use the field names of the types.*TxReq requests.
+// Nonce is AutoNonce (-1) to fetch the nonce from Lighter, and the fields tagged omitempty are optional.
+
+type ChangePubKeyParams struct {
+	PubKey string `json:"pubKey"` // hex encoded, 40 bytes
+	Nonce  int64  `json:"nonce"`
+}
+
+// OrderParams is an order of SignCreateOrder or SignCreateGroupedOrders.
+// ClientOrderIndex can be client.AutoClientOrderIndex (-1), and OrderExpiry can be DefaultOrderExpiry (-1).
+type OrderParams struct {
+	MarketIndex      uint8  `json:"marketIndex"`
+	ClientOrderIndex int64  `json:"clientOrderIndex"`
+	BaseAmount       int64  `json:"baseAmount"`
+	Price            uint32 `json:"price"`
+	IsAsk            uint8  `json:"isAsk"`
+	Type             uint8  `json:"type"`
+	TimeInForce      uint8  `json:"timeInForce"`
+	ReduceOnly       uint8  `json:"reduceOnly,omitempty"`
+	TriggerPrice     uint32 `json:"triggerPrice,omitempty"`
+	OrderExpiry      int64  `json:"orderExpiry"`
+}
+
+// txTypes maps the Sign methods to the type of their tx
+var txTypes = map[string]uint8{
+	"SignChangePubKey":        txtypes.TxTypeL2ChangePubKey,
+	"SignCreateOrder":         txtypes.TxTypeL2CreateOrder,
+	"SignCreateGroupedOrders": txtypes.TxTypeL2CreateGroupedOrders,
+	"SignCancelOrder":         txtypes.TxTypeL2CancelOrder,
+	"SignWithdraw":            txtypes.TxTypeL2Withdraw,
+	"SignCreateSubAccount":    txtypes.TxTypeL2CreateSubAccount,
+	"SignCancelAllOrders":     txtypes.TxTypeL2CancelAllOrders,
+	"SignModifyOrder":         txtypes.TxTypeL2ModifyOrder,
+	"SignTransfer":            txtypes.TxTypeL2Transfer,
+	"SignCreatePublicPool":    txtypes.TxTypeL2CreatePublicPool,
+	"SignUpdatePublicPool":    txtypes.TxTypeL2UpdatePublicPool,
+	"SignMintShares":          txtypes.TxTypeL2MintShares,
+	"SignBurnShares":          txtypes.TxTypeL2BurnShares,
+	"SignUpdateLeverage":      txtypes.TxTypeL2UpdateLeverage,
+	"SignUpdateMargin":        txtypes.TxTypeL2UpdateMargin,
+}
+
+// TxType returns the type of the tx signed by a Sign method, to send it with SendTx
+func TxType(method string) (uint8, bool) {
+	txType, ok := txTypes[method]
+	return txType, ok
+}
+
+// NewOrderParams returns the defaults of an order, the ones of a missing field
+func NewOrderParams() OrderParams {
+	return OrderParams{OrderExpiry: DefaultOrderExpiry}
+}
+
+type CreateOrderParams struct {
+	OrderParams
+	Nonce int64 `json:"nonce"`
+}
+
+type CreateGroupedOrdersParams struct {
+	GroupingType uint8         `json:"groupingType"`
+	Orders       []OrderParams `json:"orders"`
+	Nonce        int64         `json:"nonce"`
+}
+
+type CancelOrderParams struct {
+	MarketIndex uint8 `json:"marketIndex"`
+	Index       int64 `json:"index"`
+	Nonce       int64 `json:"nonce"`
+}
+
+type WithdrawParams struct {
+	USDCAmount types.USDC `json:"usdcAmount"`
+	Nonce      int64      `json:"nonce"`
+}
+
+type CreateSubAccountParams struct {
+	Nonce int64 `json:"nonce"`
+}
+
+type CancelAllOrdersParams struct {
+	TimeInForce uint8 `json:"timeInForce"`
+	Time        int64 `json:"time"`
+	Nonce       int64 `json:"nonce"`
+}
+
+type ModifyOrderParams struct {
+	MarketIndex  uint8  `json:"marketIndex"`
+	Index        int64  `json:"index"`
+	BaseAmount   int64  `json:"baseAmount"`
+	Price        uint32 `json:"price"`
+	TriggerPrice uint32 `json:"triggerPrice"`
+	Nonce        int64  `json:"nonce"`
+}
+
+// TransferParams Fee can be client.AutoTransferFee (-1), and Memo must be exactly 32 bytes.
+// In JSON, the fee is requested from Lighter when fee is omitted, and a negative fee is rejected.
+type TransferParams struct {
+	ToAccountIndex int64      `json:"toAccountIndex"`
+	USDCAmount     types.USDC `json:"usdcAmount"`
+	Fee            types.USDC `json:"fee"` // client.AutoTransferFee requests the fee from Lighter
+	Memo           string     `json:"memo"`
+	Nonce          int64      `json:"nonce"`
+}
+
+type CreatePublicPoolParams struct {
+	OperatorFee          int64 `json:"operatorFee"`
+	InitialTotalShares   int64 `json:"initialTotalShares"`
+	MinOperatorShareRate int64 `json:"minOperatorShareRate"`
+	Nonce                int64 `json:"nonce"`
+}
+
+type UpdatePublicPoolParams struct {
+	PublicPoolIndex      int64 `json:"publicPoolIndex"`
+	Status               uint8 `json:"status"`
+	OperatorFee          int64 `json:"operatorFee"`
+	MinOperatorShareRate int64 `json:"minOperatorShareRate"`
+	Nonce                int64 `json:"nonce"`
+}
+
+type SharesParams struct {
+	PublicPoolIndex int64 `json:"publicPoolIndex"`
+	ShareAmount     int64 `json:"shareAmount"`
+	Nonce           int64 `json:"nonce"`
+}
+
+type UpdateLeverageParams struct {
+	MarketIndex           uint8  `json:"marketIndex"`
+	InitialMarginFraction uint16 `json:"initialMarginFraction"`
+	MarginMode            uint8  `json:"marginMode"`
+	Nonce                 int64  `json:"nonce"`
+}
+
+type UpdateMarginParams struct {
+	MarketIndex uint8      `json:"marketIndex"`
+	USDCAmount  types.USDC `json:"usdcAmount"`
+	Direction   uint8      `json:"direction"`
+	Nonce       int64      `json:"nonce"`
+}
+
+// UnmarshalJSON defaults the OrderExpiry of the orders to DefaultOrderExpiry, as a missing expiry is different from 0
+func (p *CreateGroupedOrdersParams) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		GroupingType uint8             `json:"groupingType"`
+		Orders       []json.RawMessage `json:"orders"`
+		Nonce        *int64            `json:"nonce"`
+	}
+	if err := strictUnmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	p.GroupingType = raw.GroupingType
+	if raw.Nonce != nil {
+		p.Nonce = *raw.Nonce
+	}
+	orders, err := parseOrders(raw.Orders)
+	if err != nil {
+		return err
+	}
+	p.Orders = orders
+	return nil
+}
+
+// UnmarshalJSON rejects a negative fee, so client.AutoTransferFee is only requested by omitting the fee
+func (p *TransferParams) UnmarshalJSON(data []byte) error {
+	type params TransferParams
+	raw := struct {
+		*params
+		Fee *types.USDC `json:"fee"`
+	}{params: (*params)(p)}
+	if err := strictUnmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.Fee != nil {
+		if *raw.Fee < 0 {
+			return fmt.Errorf("fee %s is negative, omit it to request the fee from Lighter", raw.Fee)
+		}
+		p.Fee = *raw.Fee
+	}
+	return nil
+}
+
+// ParseOrders parses a JSON array of orders, as taken by SignCreateGroupedOrders
+func ParseOrders(ordersJSON string) ([]OrderParams, error) {
+	var raw []json.RawMessage
+	if err := json.Unmarshal([]byte(ordersJSON), &raw); err != nil {
+		return nil, WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("failed to parse orders JSON: %w", err))
+	}
+	orders, err := parseOrders(raw)
+	if err != nil {
+		return nil, WithErrorCode(ErrorCodeInvalidParams, err)
+	}
+	return orders, nil
+}
+
+func parseOrders(raw []json.RawMessage) ([]OrderParams, error) {
+	orders := make([]OrderParams, len(raw))
+	for i, order := range raw {
+		orders[i] = NewOrderParams()
+		if err := strictUnmarshal(order, &orders[i]); err != nil {
+			return nil, fmt.Errorf("order %d: %w", i, err)
+		}
+	}
+	return orders, nil
+}
+
+func (s *Session) SignChangePubKey(p ChangePubKeyParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	pubKeyBytes, err := decodeHex(p.PubKey)
+	if err != nil {
+		return "", WithErrorCode(ErrorCodeInvalidKey, err)
+	}
+	if len(pubKeyBytes) != 40 {
+		return "", WithErrorCode(ErrorCodeInvalidKey, fmt.Errorf("invalid pub key length. expected 40 but got %v", len(pubKeyBytes)))
+	}
+	req := &types.ChangePubKeyReq{}
+	copy(req.PubKey[:], pubKeyBytes)
+
+	tx, err := txClient.GetChangePubKeyTransaction(req, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTxWithMessage(tx, tx.GetL1SignatureBody())
+}
+
+func (s *Session) SignCreateOrder(p CreateOrderParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetCreateOrderTransaction(p.OrderParams.request(defaultOrderExpiry(txClient)), transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignCreateGroupedOrders(p CreateGroupedOrdersParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	// the orders of a group must have the same expiry, so the default one is computed once
+	defaultExpiry := defaultOrderExpiry(txClient)
+	orders := make([]*types.CreateOrderTxReq, 0, len(p.Orders))
+	for _, order := range p.Orders {
+		orders = append(orders, order.request(defaultExpiry))
+	}
+	req, err := types.NewGroupedOrders(p.GroupingType, orders)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetCreateGroupedOrdersTransaction(req, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignCancelOrder(p CancelOrderParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetCancelOrderTransaction(&types.CancelOrderTxReq{
+		MarketIndex: p.MarketIndex,
+		Index:       p.Index,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignWithdraw(p WithdrawParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetWithdrawTransaction(&types.WithdrawTxReq{
+		USDCAmount: p.USDCAmount,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignCreateSubAccount(p CreateSubAccountParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetCreateSubAccountTransaction(transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignCancelAllOrders(p CancelAllOrdersParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetCancelAllOrdersTransaction(&types.CancelAllOrdersTxReq{
+		TimeInForce: p.TimeInForce,
+		Time:        p.Time,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignModifyOrder(p ModifyOrderParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetModifyOrderTransaction(&types.ModifyOrderTxReq{
+		MarketIndex:  p.MarketIndex,
+		Index:        p.Index,
+		BaseAmount:   p.BaseAmount,
+		Price:        p.Price,
+		TriggerPrice: p.TriggerPrice,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignTransfer(p TransferParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	if len(p.Memo) != 32 {
+		return "", WithErrorCode(ErrorCodeInvalidParams, fmt.Errorf("memo expected to be 32 bytes long"))
+	}
+	req := &types.TransferTxReq{
+		ToAccountIndex: p.ToAccountIndex,
+		USDCAmount:     p.USDCAmount,
+		Fee:            p.Fee,
+	}
+	copy(req.Memo[:], p.Memo)
+
+	tx, err := txClient.GetTransferTransaction(req, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTxWithMessage(tx, tx.GetL1SignatureBody())
+}
+
+func (s *Session) SignCreatePublicPool(p CreatePublicPoolParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetCreatePublicPoolTransaction(&types.CreatePublicPoolTxReq{
+		OperatorFee:          p.OperatorFee,
+		InitialTotalShares:   p.InitialTotalShares,
+		MinOperatorShareRate: p.MinOperatorShareRate,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignUpdatePublicPool(p UpdatePublicPoolParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetUpdatePublicPoolTransaction(&types.UpdatePublicPoolTxReq{
+		PublicPoolIndex:      p.PublicPoolIndex,
+		Status:               p.Status,
+		OperatorFee:          p.OperatorFee,
+		MinOperatorShareRate: p.MinOperatorShareRate,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignMintShares(p SharesParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetMintSharesTransaction(&types.MintSharesTxReq{
+		PublicPoolIndex: p.PublicPoolIndex,
+		ShareAmount:     p.ShareAmount,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignBurnShares(p SharesParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetBurnSharesTransaction(&types.BurnSharesTxReq{
+		PublicPoolIndex: p.PublicPoolIndex,
+		ShareAmount:     p.ShareAmount,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignUpdateLeverage(p UpdateLeverageParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetUpdateLeverageTransaction(&types.UpdateLeverageTxReq{
+		MarketIndex:           p.MarketIndex,
+		InitialMarginFraction: p.InitialMarginFraction,
+		MarginMode:            p.MarginMode,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func (s *Session) SignUpdateMargin(p UpdateMarginParams) (string, error) {
+	txClient, err := s.Client()
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := txClient.GetUpdateMarginTransaction(&types.UpdateMarginTxReq{
+		MarketIndex: p.MarketIndex,
+		USDCAmount:  p.USDCAmount,
+		Direction:   p.Direction,
+	}, transactOpts(p.Nonce))
+	if err != nil {
+		return "", err
+	}
+	return marshalTx(tx)
+}
+
+func defaultOrderExpiry(txClient *client.TxClient) int64 {
+	return txClient.Now().Add(defaultOrderExpiryDuration).UnixMilli()
+}
+
+// request converts the order, replacing DefaultOrderExpiry by defaultExpiry
+func (p OrderParams) request(defaultExpiry int64) *types.CreateOrderTxReq {
+	orderExpiry := p.OrderExpiry
+	if orderExpiry == DefaultOrderExpiry {
+		orderExpiry = defaultExpiry
+	}
+
+	return &types.CreateOrderTxReq{
+		MarketIndex:      p.MarketIndex,
+		ClientOrderIndex: p.ClientOrderIndex,
+		BaseAmount:       p.BaseAmount,
+		Price:            p.Price,
+		IsAsk:            p.IsAsk,
+		Type:             p.Type,
+		TimeInForce:      p.TimeInForce,
+		ReduceOnly:       p.ReduceOnly,
+		TriggerPrice:     p.TriggerPrice,
+		OrderExpiry:      orderExpiry,
+	}
+}
+
+func transactOpts(nonce int64) *types.TransactOpts {
+	ops := new(types.TransactOpts)
+	if nonce != AutoNonce {
+		ops.Nonce = &nonce
+	}
+	return ops
+}
+
+func marshalTx(tx txtypes.TxInfo) (string, error) {
+	txInfoBytes, err := json.Marshal(tx)
+	if err != nil {
+		return "", err
+	}
+	return string(txInfoBytes), nil
+}
+
+// marshalTxWithMessage adds the MessageToSign of txs which have to be signed by the L1 address as well
+func marshalTxWithMessage(tx txtypes.TxInfo, messageToSign string) (string, error) {
+	txInfoBytes, err := json.Marshal(tx)
+	if err != nil {
+		return "", err
+	}
+	// numbers are kept as json.Number, so 64-bit values don't lose precision through float64
+	obj := make(map[string]interface{})
+	decoder := json.NewDecoder(bytes.NewReader(txInfoBytes))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return "", err
+	}
+	obj["MessageToSign"] = messageToSign
+	txInfoBytes, err = json.Marshal(obj)
+	if err != nil {
+		return "", err
+	}
+	return string(txInfoBytes), nil
+}
+
+// decodeHex decodes a 0x prefixed hex string, like the keys returned by GenerateAPIKey
+func decodeHex(s string) ([]byte, error) {
+	if len(s) < 2 || (s[:2] != "0x" && s[:2] != "0X") {
+		return nil, fmt.Errorf("hex string without 0x prefix")
+	}
+	return hex.DecodeString(s[2:])
+}
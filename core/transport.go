@@ -0,0 +1,25 @@
+//go:build !signonly
+
+package core
+
+import (
+	"net/http"
+
+	"github.com/elliottech/lighter-go/client"
+)
+
+// SetTransport sets the transport of the HTTP requests of the clients created afterwards,
+// for platforms where the default transport can't open connections, like browsers.
+// It's not available in signonly builds, which have no HTTP client.
+func (s *Session) SetTransport(transport http.RoundTripper) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.newHTTPClient = func(url string) *client.HTTPClient {
+		httpClient := client.NewHTTPClient(url)
+		if httpClient != nil {
+			httpClient.SetTransport(transport)
+		}
+		return httpClient
+	}
+}
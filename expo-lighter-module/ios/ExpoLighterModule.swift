@@ -12,7 +12,8 @@ public class ExpoLighterModule: Module {
       return [
         "privateKey": result?.privateKey ?? "",
         "publicKey": result?.publicKey ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
@@ -20,7 +21,7 @@ public class ExpoLighterModule: Module {
       return MobileCreateClient(url, privateKey, chainId, apiKeyIndex, accountIndex)
     }
 
-    Function("checkClient") { (apiKeyIndex: Int, accountIndex: Int64) -> String in
+    AsyncFunction("checkClient") { (apiKeyIndex: Int, accountIndex: Int64) -> String in
       return MobileCheckClient(apiKeyIndex, accountIndex)
     }
 
@@ -30,7 +31,10 @@ public class ExpoLighterModule: Module {
 
     // MARK: - Trading Operations
 
-    Function("signCreateOrder") { (params: [String: Any]) -> [String: String] in
+    // The sign functions send HTTP requests with nonce -1, or fee -1 for signTransfer, and so does checkClient.
+    // They're AsyncFunctions, which run off the JS thread and resolve a Promise.
+
+    AsyncFunction("signCreateOrder") { (params: [String: Any]) -> [String: Any] in
       guard let marketIndex = params["marketIndex"] as? Int,
             let clientOrderIndex = params["clientOrderIndex"] as? Int64,
             let baseAmount = params["baseAmount"] as? Int64,
@@ -42,7 +46,8 @@ public class ExpoLighterModule: Module {
             let triggerPrice = params["triggerPrice"] as? Int else {
         return [
           "json": "",
-          "error": "Missing required parameters for signCreateOrder"
+          "error": "Missing required parameters for signCreateOrder",
+          "errorCode": 2 // MobileErrorCodeInvalidParams
         ]
       }
       
@@ -64,42 +69,46 @@ public class ExpoLighterModule: Module {
       )
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signCreateGroupedOrders") { (groupingType: Int, ordersJSON: String, nonce: Int64) -> [String: String] in
+    AsyncFunction("signCreateGroupedOrders") { (groupingType: Int, ordersJSON: String, nonce: Int64) -> [String: Any] in
       let result = MobileSignCreateGroupedOrders(groupingType, ordersJSON, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signCancelOrder") { (marketIndex: Int, orderIndex: Int64, nonce: Int64) -> [String: String] in
+    AsyncFunction("signCancelOrder") { (marketIndex: Int, orderIndex: Int64, nonce: Int64) -> [String: Any] in
       let result = MobileSignCancelOrder(marketIndex, orderIndex, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signCancelAllOrders") { (timeInForce: Int, time: Int64, nonce: Int64) -> [String: String] in
+    AsyncFunction("signCancelAllOrders") { (timeInForce: Int, time: Int64, nonce: Int64) -> [String: Any] in
       let result = MobileSignCancelAllOrders(timeInForce, time, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signModifyOrder") { (
+    AsyncFunction("signModifyOrder") { (
       marketIndex: Int,
       index: Int64,
       baseAmount: Int64,
       price: Int64,
       triggerPrice: Int64,
       nonce: Int64
-    ) -> [String: String] in
+    ) -> [String: Any] in
       let result = MobileSignModifyOrder(
         marketIndex,
         index,
@@ -110,27 +119,29 @@ public class ExpoLighterModule: Module {
       )
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
     // MARK: - Account Management
 
-    Function("signWithdraw") { (usdcAmount: Int64, nonce: Int64) -> [String: String] in
+    AsyncFunction("signWithdraw") { (usdcAmount: Int64, nonce: Int64) -> [String: Any] in
       let result = MobileSignWithdraw(usdcAmount, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signTransfer") { (
+    AsyncFunction("signTransfer") { (
       toAccountIndex: Int64,
       usdcAmount: Int64,
       fee: Int64,
       memo: String,
       nonce: Int64
-    ) -> [String: String] in
+    ) -> [String: Any] in
       let result = MobileSignTransfer(
         toAccountIndex,
         usdcAmount,
@@ -140,34 +151,37 @@ public class ExpoLighterModule: Module {
       )
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signCreateSubAccount") { (nonce: Int64) -> [String: String] in
+    AsyncFunction("signCreateSubAccount") { (nonce: Int64) -> [String: Any] in
       let result = MobileSignCreateSubAccount(nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signChangePubKey") { (pubKey: String, nonce: Int64) -> [String: String] in
+    AsyncFunction("signChangePubKey") { (pubKey: String, nonce: Int64) -> [String: Any] in
       let result = MobileSignChangePubKey(pubKey, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
     // MARK: - Pool Operations
 
-    Function("signCreatePublicPool") { (
+    AsyncFunction("signCreatePublicPool") { (
       operatorFee: Int64,
       initialTotalShares: Int64,
       minOperatorShareRate: Int64,
       nonce: Int64
-    ) -> [String: String] in
+    ) -> [String: Any] in
       let result = MobileSignCreatePublicPool(
         operatorFee,
         initialTotalShares,
@@ -176,17 +190,18 @@ public class ExpoLighterModule: Module {
       )
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signUpdatePublicPool") { (
+    AsyncFunction("signUpdatePublicPool") { (
       publicPoolIndex: Int64,
       status: Int,
       operatorFee: Int64,
       minOperatorShareRate: Int64,
       nonce: Int64
-    ) -> [String: String] in
+    ) -> [String: Any] in
       let result = MobileSignUpdatePublicPool(
         publicPoolIndex,
         status,
@@ -196,63 +211,77 @@ public class ExpoLighterModule: Module {
       )
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signMintShares") { (publicPoolIndex: Int64, shareAmount: Int64, nonce: Int64) -> [String: String] in
+    AsyncFunction("signMintShares") { (publicPoolIndex: Int64, shareAmount: Int64, nonce: Int64) -> [String: Any] in
       let result = MobileSignMintShares(publicPoolIndex, shareAmount, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signBurnShares") { (publicPoolIndex: Int64, shareAmount: Int64, nonce: Int64) -> [String: String] in
+    AsyncFunction("signBurnShares") { (publicPoolIndex: Int64, shareAmount: Int64, nonce: Int64) -> [String: Any] in
       let result = MobileSignBurnShares(publicPoolIndex, shareAmount, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
     // MARK: - Position Management
 
-    Function("signUpdateLeverage") { (
+    AsyncFunction("signUpdateLeverage") { (
       marketIndex: Int,
       initialMarginFraction: Int,
       marginMode: Int,
       nonce: Int64
-    ) -> [String: String] in
+    ) -> [String: Any] in
       let result = MobileSignUpdateLeverage(marketIndex, initialMarginFraction, marginMode, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
-    Function("signUpdateMargin") { (
+    AsyncFunction("signUpdateMargin") { (
       marketIndex: Int,
       usdcAmount: Int64,
       direction: Int,
       nonce: Int64
-    ) -> [String: String] in
+    ) -> [String: Any] in
       let result = MobileSignUpdateMargin(marketIndex, usdcAmount, direction, nonce)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
 
     // MARK: - Authentication
 
-    Function("createAuthToken") { (deadline: Int64) -> [String: String] in
+    Function("createAuthToken") { (deadline: Int64) -> [String: Any] in
       let result = MobileCreateAuthToken(deadline)
       return [
         "json": result?.json ?? "",
-        "error": result?.error ?? ""
+        "error": result?.error ?? "",
+        "errorCode": errorCode(result)
       ]
     }
   }
+
+  // errorCode reads the code of a TxResult or APIKeyResult, 0 with a Lighter.xcframework built before the error codes
+  private func errorCode(_ result: NSObject?) -> Int {
+    guard let result = result, result.responds(to: Selector(("errorCode"))) else {
+      return 0
+    }
+    return (result.value(forKey: "errorCode") as? Int) ?? 0
+  }
 }
 
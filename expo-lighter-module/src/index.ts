@@ -5,11 +5,13 @@ export interface APIKeyResult {
   privateKey: string;
   publicKey: string;
   error: string;
+  errorCode: number; // 0 on success, the codes are listed in the Error Handling section of mobile/README.md
 }
 
 export interface TxResult {
   json: string;
   error: string;
+  errorCode: number; // 0 on success, the codes are listed in the Error Handling section of mobile/README.md
 }
 
 // Native module
@@ -17,6 +19,9 @@ const ExpoLighter = requireNativeModule('ExpoLighter');
 
 /**
  * Lighter SDK for React Native/Expo
+ *
+ * The sign methods and checkClient can send requests to Lighter, e.g. to fetch the nonce when it's -1,
+ * so they return a Promise and run off the JS thread.
  */
 export class LighterSDK {
   // MARK: - Key Management
@@ -44,10 +49,10 @@ export class LighterSDK {
   }
 
   /**
-   * Check that the client is properly configured
+   * Check that the client is properly configured, with a request to Lighter
    * @returns Empty string on success, error message on failure
    */
-  static checkClient(apiKeyIndex: number, accountIndex: number): string {
+  static checkClient(apiKeyIndex: number, accountIndex: number): Promise<string> {
     return ExpoLighter.checkClient(apiKeyIndex, accountIndex);
   }
 
@@ -78,7 +83,7 @@ export class LighterSDK {
     triggerPrice: number;
     orderExpiry?: number; // default -1
     nonce?: number; // default -1
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signCreateOrder({
       marketIndex: params.marketIndex,
       clientOrderIndex: params.clientOrderIndex,
@@ -115,7 +120,7 @@ export class LighterSDK {
       orderExpiry?: number;
     }>,
     nonce: number = -1
-  ): TxResult {
+  ): Promise<TxResult> {
     const ordersJSON = JSON.stringify(orders);
     return ExpoLighter.signCreateGroupedOrders(groupingType, ordersJSON, nonce);
   }
@@ -127,7 +132,7 @@ export class LighterSDK {
     marketIndex: number,
     orderIndex: number,
     nonce: number = -1
-  ): TxResult {
+  ): Promise<TxResult> {
     return ExpoLighter.signCancelOrder(marketIndex, orderIndex, nonce);
   }
 
@@ -138,7 +143,7 @@ export class LighterSDK {
     timeInForce: number,
     time: number,
     nonce: number = -1
-  ): TxResult {
+  ): Promise<TxResult> {
     return ExpoLighter.signCancelAllOrders(timeInForce, time, nonce);
   }
 
@@ -152,7 +157,7 @@ export class LighterSDK {
     price: number;
     triggerPrice: number;
     nonce?: number;
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signModifyOrder(
       params.marketIndex,
       params.index,
@@ -169,12 +174,13 @@ export class LighterSDK {
    * Sign a withdraw transaction
    * @param usdcAmount Amount in USDC base units
    */
-  static signWithdraw(usdcAmount: number, nonce: number = -1): TxResult {
+  static signWithdraw(usdcAmount: number, nonce: number = -1): Promise<TxResult> {
     return ExpoLighter.signWithdraw(usdcAmount, nonce);
   }
 
   /**
    * Sign a transfer transaction
+   * @param fee Pass -1 to request the fee from Lighter, rejected above 10 USDC
    * @param memo Must be exactly 32 bytes
    */
   static signTransfer(params: {
@@ -183,7 +189,7 @@ export class LighterSDK {
     fee: number;
     memo: string; // Must be 32 bytes
     nonce?: number;
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signTransfer(
       params.toAccountIndex,
       params.usdcAmount,
@@ -196,7 +202,7 @@ export class LighterSDK {
   /**
    * Sign a create sub-account transaction
    */
-  static signCreateSubAccount(nonce: number = -1): TxResult {
+  static signCreateSubAccount(nonce: number = -1): Promise<TxResult> {
     return ExpoLighter.signCreateSubAccount(nonce);
   }
 
@@ -204,7 +210,7 @@ export class LighterSDK {
    * Sign a change public key transaction
    * @param pubKey Hex-encoded public key (40 bytes)
    */
-  static signChangePubKey(pubKey: string, nonce: number = -1): TxResult {
+  static signChangePubKey(pubKey: string, nonce: number = -1): Promise<TxResult> {
     return ExpoLighter.signChangePubKey(pubKey, nonce);
   }
 
@@ -218,7 +224,7 @@ export class LighterSDK {
     initialTotalShares: number;
     minOperatorShareRate: number;
     nonce?: number;
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signCreatePublicPool(
       params.operatorFee,
       params.initialTotalShares,
@@ -236,7 +242,7 @@ export class LighterSDK {
     operatorFee: number;
     minOperatorShareRate: number;
     nonce?: number;
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signUpdatePublicPool(
       params.publicPoolIndex,
       params.status,
@@ -253,7 +259,7 @@ export class LighterSDK {
     publicPoolIndex: number,
     shareAmount: number,
     nonce: number = -1
-  ): TxResult {
+  ): Promise<TxResult> {
     return ExpoLighter.signMintShares(publicPoolIndex, shareAmount, nonce);
   }
 
@@ -264,7 +270,7 @@ export class LighterSDK {
     publicPoolIndex: number,
     shareAmount: number,
     nonce: number = -1
-  ): TxResult {
+  ): Promise<TxResult> {
     return ExpoLighter.signBurnShares(publicPoolIndex, shareAmount, nonce);
   }
 
@@ -278,7 +284,7 @@ export class LighterSDK {
     initialMarginFraction: number;
     marginMode: number;
     nonce?: number;
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signUpdateLeverage(
       params.marketIndex,
       params.initialMarginFraction,
@@ -295,7 +301,7 @@ export class LighterSDK {
     usdcAmount: number;
     direction: number;
     nonce?: number;
-  }): TxResult {
+  }): Promise<TxResult> {
     return ExpoLighter.signUpdateMargin(
       params.marketIndex,
       params.usdcAmount,
@@ -308,7 +314,7 @@ export class LighterSDK {
 
   /**
    * Create an authentication token
-   * @param deadline Unix timestamp, 0 for default (7 hours from now)
+   * @param deadline Unix timestamp, 0 for default (7 hours from now), -1 for a cached token valid for at least 1 hour
    */
   static createAuthToken(deadline: number = 0): TxResult {
     return ExpoLighter.createAuthToken(deadline);
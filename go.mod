@@ -4,17 +4,14 @@ go 1.24.0
 
 require (
 	github.com/elliottech/poseidon_crypto v0.0.11
-	github.com/ethereum/go-ethereum v1.15.6
+	golang.org/x/mobile v0.0.0-20251113184115-a159579294ab
 )
 
 require (
 	github.com/bits-and-blooms/bitset v1.17.0 // indirect
 	github.com/consensys/gnark-crypto v0.14.0 // indirect
-	github.com/holiman/uint256 v1.3.2 // indirect
-	golang.org/x/crypto v0.35.0 // indirect
-	golang.org/x/mobile v0.0.0-20251113184115-a159579294ab // indirect
+	github.com/stretchr/testify v1.10.0 // indirect
 	golang.org/x/mod v0.30.0 // indirect
 	golang.org/x/sync v0.18.0 // indirect
-	golang.org/x/sys v0.38.0 // indirect
 	golang.org/x/tools v0.39.0 // indirect
 )
@@ -0,0 +1,181 @@
+package mobile
+
+import (
+	"encoding/json"
+)
+
+// TxCallback receives the result of an Async method. It's called from a Go thread, not the one which called
+// the method, so apps have to dispatch to their main thread to update the UI.
+type TxCallback interface {
+	// OnSuccess receives the result as JSON, {"txType": 14, "txInfo": "..."} for the Sign methods
+	OnSuccess(json string)
+	// OnError receives one of the ErrorCode constants, and the message of the error
+	OnError(code int, message string)
+}
+
+// async runs fn without blocking the caller, and passes its result to callback
+func async(callback TxCallback, fn func() (string, error)) {
+	go func() {
+		result, err := func() (result string, err error) {
+			defer recoverError(&err)
+			return fn()
+		}()
+		if err != nil {
+			callback.OnError(ErrorCode(err), err.Error())
+			return
+		}
+		callback.OnSuccess(result)
+	}()
+}
+
+// signAsync runs a Sign method without blocking the caller, as it can fetch the nonce or the transfer fee.
+// The result is the SignedTx, {"txType": 14, "txInfo": "..."}, so it can be passed to SendTxAsync as is.
+func signAsync(callback TxCallback, sign func() (*SignedTx, error)) {
+	async(callback, func() (string, error) {
+		tx, err := sign()
+		if err != nil {
+			return "", err
+		}
+		return marshalJSON(tx)
+	})
+}
+
+func marshalJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// SendTxAsync is SendTx without blocking the caller, the result is {"txHash": "..."}
+func (c *Client) SendTxAsync(txType int, txInfo string, callback TxCallback) {
+	async(callback, func() (string, error) {
+		txHash, err := c.SendTx(txType, txInfo)
+		if err != nil {
+			return "", err
+		}
+		return marshalJSON(map[string]string{"txHash": txHash})
+	})
+}
+
+// GetNextNonceAsync is GetNextNonce without blocking the caller, the result is {"nonce": 123}
+func (c *Client) GetNextNonceAsync(callback TxCallback) {
+	async(callback, func() (string, error) {
+		nonce, err := c.GetNextNonce()
+		if err != nil {
+			return "", err
+		}
+		return marshalJSON(map[string]int64{"nonce": nonce})
+	})
+}
+
+// CheckClientAsync is CheckClient without blocking the caller, the result is {}
+func (c *Client) CheckClientAsync(apiKeyIndex int, accountIndex int64, callback TxCallback) {
+	async(callback, func() (string, error) {
+		return "{}", c.CheckClient(apiKeyIndex, accountIndex)
+	})
+}
+
+// CallAsync is Call without blocking the caller
+func (c *Client) CallAsync(method, paramsJSON string, callback TxCallback) {
+	async(callback, func() (string, error) {
+		return c.Call(method, paramsJSON)
+	})
+}
+
+// The Sign methods without blocking the caller, for when they fetch the nonce from Lighter, with nonce -1.
+// The result is the tx type & info, {"txType": 14, "txInfo": "..."}.
+
+func (c *Client) SignChangePubKeyAsync(pubKey string, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignChangePubKey(pubKey, nonce)
+	})
+}
+
+func (c *Client) SignCreateOrderAsync(marketIndex int, clientOrderIndex, baseAmount int64, price int,
+	isAsk, orderType, timeInForce, reduceOnly int, triggerPrice int,
+	orderExpiry, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignCreateOrder(marketIndex, clientOrderIndex, baseAmount, price,
+			isAsk, orderType, timeInForce, reduceOnly, triggerPrice, orderExpiry, nonce)
+	})
+}
+
+func (c *Client) SignCreateGroupedOrdersAsync(groupingType int, ordersJSON string, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignCreateGroupedOrders(groupingType, ordersJSON, nonce)
+	})
+}
+
+func (c *Client) SignCancelOrderAsync(marketIndex int, orderIndex, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignCancelOrder(marketIndex, orderIndex, nonce)
+	})
+}
+
+func (c *Client) SignWithdrawAsync(usdcAmount int64, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignWithdraw(usdcAmount, nonce)
+	})
+}
+
+func (c *Client) SignCreateSubAccountAsync(nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignCreateSubAccount(nonce)
+	})
+}
+
+func (c *Client) SignCancelAllOrdersAsync(timeInForce int, time, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignCancelAllOrders(timeInForce, time, nonce)
+	})
+}
+
+func (c *Client) SignModifyOrderAsync(marketIndex int, index, baseAmount int64, price, triggerPrice int64, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignModifyOrder(marketIndex, index, baseAmount, price, triggerPrice, nonce)
+	})
+}
+
+func (c *Client) SignTransferAsync(toAccountIndex, usdcAmount, fee int64, memo string, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignTransfer(toAccountIndex, usdcAmount, fee, memo, nonce)
+	})
+}
+
+func (c *Client) SignCreatePublicPoolAsync(operatorFee, initialTotalShares, minOperatorShareRate, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignCreatePublicPool(operatorFee, initialTotalShares, minOperatorShareRate, nonce)
+	})
+}
+
+func (c *Client) SignUpdatePublicPoolAsync(publicPoolIndex int64, status int, operatorFee, minOperatorShareRate, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignUpdatePublicPool(publicPoolIndex, status, operatorFee, minOperatorShareRate, nonce)
+	})
+}
+
+func (c *Client) SignMintSharesAsync(publicPoolIndex, shareAmount, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignMintShares(publicPoolIndex, shareAmount, nonce)
+	})
+}
+
+func (c *Client) SignBurnSharesAsync(publicPoolIndex, shareAmount, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignBurnShares(publicPoolIndex, shareAmount, nonce)
+	})
+}
+
+func (c *Client) SignUpdateLeverageAsync(marketIndex, initialMarginFraction, marginMode int, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignUpdateLeverage(marketIndex, initialMarginFraction, marginMode, nonce)
+	})
+}
+
+func (c *Client) SignUpdateMarginAsync(marketIndex int, usdcAmount int64, direction int, nonce int64, callback TxCallback) {
+	signAsync(callback, func() (*SignedTx, error) {
+		return c.SignUpdateMargin(marketIndex, usdcAmount, direction, nonce)
+	})
+}
@@ -0,0 +1,95 @@
+package mobile
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// chanCallback sends the result of an Async method to a channel
+type chanCallback struct {
+	results chan string
+	errors  chan string
+}
+
+func newChanCallback() *chanCallback {
+	return &chanCallback{results: make(chan string, 1), errors: make(chan string, 1)}
+}
+
+func (c *chanCallback) OnSuccess(json string) {
+	c.results <- json
+}
+
+func (c *chanCallback) OnError(code int, message string) {
+	c.errors <- fmt.Sprintf("%d: %s", code, message)
+}
+
+func (c *chanCallback) wait(t *testing.T) (result string, errMessage string) {
+	t.Helper()
+
+	select {
+	case result = <-c.results:
+	case errMessage = <-c.errors:
+	case <-time.After(10 * time.Second):
+		t.Fatal("callback not called")
+	}
+	return result, errMessage
+}
+
+func TestAsync(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/nextNonce":
+			fmt.Fprint(w, `{"code":200,"nonce":42}`)
+		case "/api/v1/sendTx":
+			fmt.Fprint(w, `{"code":200,"tx_hash":"0xabc"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(server.URL, testPrivateKey, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	callback := newChanCallback()
+	c.GetNextNonceAsync(callback)
+	if result, errMessage := callback.wait(t); result != `{"nonce":42}` {
+		t.Fatalf("unexpected result %q, error %q", result, errMessage)
+	}
+
+	// the nonce is fetched
+	c.SignCancelOrderAsync(0, 1, -1, callback)
+	result, errMessage := callback.wait(t)
+	var signed SignedTx
+	if err := json.Unmarshal([]byte(result), &signed); err != nil {
+		t.Fatalf("%v, error %q", err, errMessage)
+	}
+	if signed.TxType != txtypes.TxTypeL2CancelOrder {
+		t.Fatalf("unexpected tx type %d", signed.TxType)
+	}
+	var tx struct{ Nonce int64 }
+	if err := json.Unmarshal([]byte(signed.TxInfo), &tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce != 42 {
+		t.Fatalf("unexpected nonce %d", tx.Nonce)
+	}
+
+	c.SendTxAsync(signed.TxType, signed.TxInfo, callback)
+	if result, errMessage := callback.wait(t); result != `{"txHash":"0xabc"}` {
+		t.Fatalf("unexpected result %q, error %q", result, errMessage)
+	}
+
+	c.CallAsync("SwitchAPIKey", `{"apiKeyIndex":4}`, callback)
+	if result, errMessage := callback.wait(t); errMessage != "3: no client initialized for api key 4" {
+		t.Fatalf("unexpected result %q, error %q", result, errMessage)
+	}
+}
@@ -0,0 +1,416 @@
+package mobile
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/elliottech/lighter-go/core"
+	"github.com/elliottech/lighter-go/types"
+)
+
+// Client signs for one account, with its own api keys, so an app can hold a Client per account or sub-account.
+// It's safe for concurrent use. The methods returning an error throw in Swift & Java.
+type Client struct {
+	session *core.Session
+}
+
+// NewClient creates a client for the api key of the account
+// url: API endpoint URL, use "" to sign without sending requests, with explicit nonces
+// privateKey: hex-encoded private key
+// chainId: blockchain chain ID
+// apiKeyIndex: index of the API key (0-255)
+// accountIndex: account index (must be > 0)
+func NewClient(url, privateKey string, chainId, apiKeyIndex int, accountIndex int64) (c *Client, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	chain := params.Uint32("chain id", int64(chainId))
+	keyIndex := params.Uint8("api key index", int64(apiKeyIndex))
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	session := core.NewSession()
+	if err := session.CreateClient(url, privateKey, chain, keyIndex, accountIndex); err != nil {
+		return nil, err
+	}
+	return &Client{session: session}, nil
+}
+
+// recoverError is deferred by the methods below, so a panic is returned as an error
+func recoverError(err *error) {
+	if r := recover(); r != nil {
+		*err = fmt.Errorf("%v", r)
+	}
+}
+
+// signedTx adds the tx type of the Sign method to its tx info
+func signedTx(method, txInfo string, err error) (*SignedTx, error) {
+	if err != nil {
+		return nil, err
+	}
+	txType, _ := core.TxType(method)
+	return &SignedTx{TxType: int(txType), TxInfo: txInfo}, nil
+}
+
+// AccountIndex returns the account of the client
+func (c *Client) AccountIndex() int64 {
+	txClient, err := c.session.Client()
+	if err != nil {
+		return 0
+	}
+	return txClient.GetAccountIndex()
+}
+
+// AddAPIKey adds another api key of the account, use SwitchAPIKey to sign with it
+func (c *Client) AddAPIKey(privateKey string, apiKeyIndex int) (err error) {
+	defer recoverError(&err)
+
+	keyIndex, err := core.APIKeyIndex(int64(apiKeyIndex))
+	if err != nil {
+		return err
+	}
+	return c.session.AddAPIKey(privateKey, keyIndex)
+}
+
+// SwitchAPIKey switches the active API key to the one at the specified index
+func (c *Client) SwitchAPIKey(apiKeyIndex int) (err error) {
+	defer recoverError(&err)
+
+	keyIndex, err := core.APIKeyIndex(int64(apiKeyIndex))
+	if err != nil {
+		return err
+	}
+	return c.session.SwitchAPIKey(keyIndex)
+}
+
+// ConfigureClientOrderIndex configures the allocator used when SignCreateOrder is called with clientOrderIndex -1
+// tagBits: number of high bits used for a strategy tag (0-8), use 0 for no tag
+// tag: strategy tag encoded in every allocated client order index
+// lastClientOrderIndex: last index allocated by a previous run, use 0 if unknown
+func (c *Client) ConfigureClientOrderIndex(tagBits int, tag, lastClientOrderIndex int64) (err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	bits := params.Uint8("tag bits", int64(tagBits))
+	if err := params.Err(); err != nil {
+		return err
+	}
+	return c.session.ConfigureClientOrderIndex(bits, tag, lastClientOrderIndex)
+}
+
+// SetMaxTransferFee sets the highest fee accepted by SignTransfer when the fee is -1
+// maxFee: USDC amount with 6 decimals, 10 USDC by default
+func (c *Client) SetMaxTransferFee(maxFee int64) (err error) {
+	defer recoverError(&err)
+
+	return c.session.SetMaxTransferFee(types.USDC(maxFee))
+}
+
+// CheckClient verifies that the api key of the client matches the one on Lighter
+func (c *Client) CheckClient(apiKeyIndex int, accountIndex int64) (err error) {
+	defer recoverError(&err)
+
+	keyIndex, err := core.APIKeyIndex(int64(apiKeyIndex))
+	if err != nil {
+		return err
+	}
+	return c.session.CheckClient(keyIndex, accountIndex)
+}
+
+// SendTx sends a signed transaction to Lighter and returns its hash.
+// Orders too far from the market price are rejected, unless disabled with SetFatFingerProtection.
+func (c *Client) SendTx(txType int, txInfo string) (txHash string, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.SendTxParams{TxType: params.Uint8("tx type", int64(txType)), TxInfo: txInfo}
+	if err := params.Err(); err != nil {
+		return "", err
+	}
+	return c.session.SendTx(p)
+}
+
+// GetNextNonce returns the next nonce of the current api key, from Lighter
+func (c *Client) GetNextNonce() (nonce int64, err error) {
+	defer recoverError(&err)
+
+	s, err := c.session.GetNextNonce()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
+
+// SetFatFingerProtection enables or disables the rejection of orders too far from the market price by SendTx
+func (c *Client) SetFatFingerProtection(enabled bool) (err error) {
+	defer recoverError(&err)
+
+	return c.session.SetFatFingerProtection(enabled)
+}
+
+// CreateAuthToken creates an authentication token
+// deadline: Unix timestamp, use 0 for 7 hours from now, or -1 for a cached token which is refreshed automatically and valid for at least 1 hour
+func (c *Client) CreateAuthToken(deadline int64) (token string, err error) {
+	defer recoverError(&err)
+
+	return c.session.CreateAuthToken(deadline)
+}
+
+// SignChangePubKey signs a change public key transaction
+// pubKey: hex-encoded new public key (40 bytes)
+// nonce: transaction nonce, use -1 for automatic
+func (c *Client) SignChangePubKey(pubKey string, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignChangePubKey(core.ChangePubKeyParams{
+		PubKey: pubKey,
+		Nonce:  nonce,
+	})
+	return signedTx("SignChangePubKey", txInfo, err)
+}
+
+// SignCreateOrder signs a create order transaction
+// Pass -1 for clientOrderIndex to allocate one automatically, see ConfigureClientOrderIndex
+// Pass -1 for orderExpiry to use default (28 days)
+// Pass -1 for nonce for automatic nonce
+func (c *Client) SignCreateOrder(marketIndex int, clientOrderIndex, baseAmount int64, price int,
+	isAsk, orderType, timeInForce, reduceOnly int, triggerPrice int,
+	orderExpiry, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.CreateOrderParams{
+		OrderParams: core.OrderParams{
+			MarketIndex:      params.Uint8("market index", int64(marketIndex)),
+			ClientOrderIndex: clientOrderIndex,
+			BaseAmount:       baseAmount,
+			Price:            params.Uint32("price", int64(price)),
+			IsAsk:            params.Uint8("is ask", int64(isAsk)),
+			Type:             params.Uint8("order type", int64(orderType)),
+			TimeInForce:      params.Uint8("time in force", int64(timeInForce)),
+			ReduceOnly:       params.Uint8("reduce only", int64(reduceOnly)),
+			TriggerPrice:     params.Uint32("trigger price", int64(triggerPrice)),
+			OrderExpiry:      orderExpiry,
+		},
+		Nonce: nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignCreateOrder(p)
+	return signedTx("SignCreateOrder", txInfo, err)
+}
+
+// SignCreateGroupedOrders signs a create grouped orders transaction
+// ordersJSON should be a JSON array of order objects
+// groupingType: 0 = None, 1 = OCO (One-Cancels-Other), 2 = OTO (One-Triggers-Other), 3 = OTOCO
+func (c *Client) SignCreateGroupedOrders(groupingType int, ordersJSON string, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	grouping := params.Uint8("grouping type", int64(groupingType))
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	orders, err := core.ParseOrders(ordersJSON)
+	if err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignCreateGroupedOrders(core.CreateGroupedOrdersParams{
+		GroupingType: grouping,
+		Orders:       orders,
+		Nonce:        nonce,
+	})
+	return signedTx("SignCreateGroupedOrders", txInfo, err)
+}
+
+// SignCancelOrder signs a cancel order transaction
+func (c *Client) SignCancelOrder(marketIndex int, orderIndex, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.CancelOrderParams{
+		MarketIndex: params.Uint8("market index", int64(marketIndex)),
+		Index:       orderIndex,
+		Nonce:       nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignCancelOrder(p)
+	return signedTx("SignCancelOrder", txInfo, err)
+}
+
+// SignWithdraw signs a withdraw transaction
+func (c *Client) SignWithdraw(usdcAmount int64, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignWithdraw(core.WithdrawParams{
+		USDCAmount: types.USDC(usdcAmount),
+		Nonce:      nonce,
+	})
+	return signedTx("SignWithdraw", txInfo, err)
+}
+
+// SignCreateSubAccount signs a create sub account transaction
+func (c *Client) SignCreateSubAccount(nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignCreateSubAccount(core.CreateSubAccountParams{
+		Nonce: nonce,
+	})
+	return signedTx("SignCreateSubAccount", txInfo, err)
+}
+
+// SignCancelAllOrders signs a cancel all orders transaction
+func (c *Client) SignCancelAllOrders(timeInForce int, time, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.CancelAllOrdersParams{
+		TimeInForce: params.Uint8("time in force", int64(timeInForce)),
+		Time:        time,
+		Nonce:       nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignCancelAllOrders(p)
+	return signedTx("SignCancelAllOrders", txInfo, err)
+}
+
+// SignModifyOrder signs a modify order transaction
+func (c *Client) SignModifyOrder(marketIndex int, index, baseAmount int64, price, triggerPrice int64, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.ModifyOrderParams{
+		MarketIndex:  params.Uint8("market index", int64(marketIndex)),
+		Index:        index,
+		BaseAmount:   baseAmount,
+		Price:        params.Uint32("price", price),
+		TriggerPrice: params.Uint32("trigger price", triggerPrice),
+		Nonce:        nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignModifyOrder(p)
+	return signedTx("SignModifyOrder", txInfo, err)
+}
+
+// SignTransfer signs a transfer transaction
+// Pass -1 for fee to request it from Lighter (fails if higher than the max transfer fee, see SetMaxTransferFee)
+// memo must be exactly 32 bytes
+func (c *Client) SignTransfer(toAccountIndex, usdcAmount, fee int64, memo string, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignTransfer(core.TransferParams{
+		ToAccountIndex: toAccountIndex,
+		USDCAmount:     types.USDC(usdcAmount),
+		Fee:            types.USDC(fee),
+		Memo:           memo,
+		Nonce:          nonce,
+	})
+	return signedTx("SignTransfer", txInfo, err)
+}
+
+// SignCreatePublicPool signs a create public pool transaction
+func (c *Client) SignCreatePublicPool(operatorFee, initialTotalShares, minOperatorShareRate, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignCreatePublicPool(core.CreatePublicPoolParams{
+		OperatorFee:          operatorFee,
+		InitialTotalShares:   initialTotalShares,
+		MinOperatorShareRate: minOperatorShareRate,
+		Nonce:                nonce,
+	})
+	return signedTx("SignCreatePublicPool", txInfo, err)
+}
+
+// SignUpdatePublicPool signs an update public pool transaction
+func (c *Client) SignUpdatePublicPool(publicPoolIndex int64, status int, operatorFee, minOperatorShareRate, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.UpdatePublicPoolParams{
+		PublicPoolIndex:      publicPoolIndex,
+		Status:               params.Uint8("status", int64(status)),
+		OperatorFee:          operatorFee,
+		MinOperatorShareRate: minOperatorShareRate,
+		Nonce:                nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignUpdatePublicPool(p)
+	return signedTx("SignUpdatePublicPool", txInfo, err)
+}
+
+// SignMintShares signs a mint shares transaction
+func (c *Client) SignMintShares(publicPoolIndex, shareAmount, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignMintShares(core.SharesParams{
+		PublicPoolIndex: publicPoolIndex,
+		ShareAmount:     shareAmount,
+		Nonce:           nonce,
+	})
+	return signedTx("SignMintShares", txInfo, err)
+}
+
+// SignBurnShares signs a burn shares transaction
+func (c *Client) SignBurnShares(publicPoolIndex, shareAmount, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	txInfo, err := c.session.SignBurnShares(core.SharesParams{
+		PublicPoolIndex: publicPoolIndex,
+		ShareAmount:     shareAmount,
+		Nonce:           nonce,
+	})
+	return signedTx("SignBurnShares", txInfo, err)
+}
+
+// SignUpdateLeverage signs an update leverage transaction
+func (c *Client) SignUpdateLeverage(marketIndex, initialMarginFraction, marginMode int, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.UpdateLeverageParams{
+		MarketIndex:           params.Uint8("market index", int64(marketIndex)),
+		InitialMarginFraction: params.Uint16("initial margin fraction", int64(initialMarginFraction)),
+		MarginMode:            params.Uint8("margin mode", int64(marginMode)),
+		Nonce:                 nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignUpdateLeverage(p)
+	return signedTx("SignUpdateLeverage", txInfo, err)
+}
+
+// SignUpdateMargin signs an update margin transaction
+func (c *Client) SignUpdateMargin(marketIndex int, usdcAmount int64, direction int, nonce int64) (tx *SignedTx, err error) {
+	defer recoverError(&err)
+
+	var params core.ParamChecker
+	p := core.UpdateMarginParams{
+		MarketIndex: params.Uint8("market index", int64(marketIndex)),
+		USDCAmount:  types.USDC(usdcAmount),
+		Direction:   params.Uint8("direction", int64(direction)),
+		Nonce:       nonce,
+	}
+	if err := params.Err(); err != nil {
+		return nil, err
+	}
+	txInfo, err := c.session.SignUpdateMargin(p)
+	return signedTx("SignUpdateMargin", txInfo, err)
+}
+
+// Call runs any method by name, with its params as a JSON object, e.g. Call("SignCancelOrder", `{"marketIndex":0,"index":12}`)
+// A missing nonce is -1 (automatic).
+func (c *Client) Call(method, paramsJSON string) (result string, err error) {
+	defer recoverError(&err)
+
+	return c.session.Call(method, paramsJSON)
+}
@@ -0,0 +1,201 @@
+package mobile
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+const testPrivateKey = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728"
+
+func TestClients(t *testing.T) {
+	other, err := NewAPIKeyPair("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := NewClient("", testPrivateKey, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewClient("", other.PrivateKey, 304, 3, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, c := range []*Client{a, b} {
+		tx, err := c.SignCancelOrder(0, 1, 7)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tx.TxType != txtypes.TxTypeL2CancelOrder {
+			t.Errorf("unexpected tx type %d", tx.TxType)
+		}
+		var txInfo struct{ AccountIndex int64 }
+		if err := json.Unmarshal([]byte(tx.TxInfo), &txInfo); err != nil {
+			t.Fatal(err)
+		}
+		if txInfo.AccountIndex != c.AccountIndex() {
+			t.Errorf("signed for account %d instead of %d", txInfo.AccountIndex, c.AccountIndex())
+		}
+	}
+
+	if err := a.SwitchAPIKey(4); ErrorCode(err) != ErrorCodeNotInitialized {
+		t.Errorf("expected an error for an unknown api key, got %v", err)
+	}
+	if _, err := NewClient("", testPrivateKey, 304, 3, 0); ErrorCode(err) != ErrorCodeInvalidParams {
+		t.Errorf("expected an error for an invalid account, got %v", err)
+	}
+	// 259 would be api key 3 once truncated to a byte
+	if err := a.SwitchAPIKey(259); ErrorCode(err) != ErrorCodeInvalidParams {
+		t.Errorf("expected an error for an api key index above 255, got %v", err)
+	}
+	if _, err := NewClient("", testPrivateKey, 304, -1, 100); ErrorCode(err) != ErrorCodeInvalidParams {
+		t.Errorf("expected an error for a negative api key index, got %v", err)
+	}
+	_, err = a.SignCancelOrder(0, 1, -5)
+	if name := ErrorCodeName(ErrorCode(err)); name != "NonceTooLow" {
+		t.Errorf("expected a NonceTooLow error, got %s %v", name, err)
+	}
+}
+
+func TestShim(t *testing.T) {
+	if result := SignCancelOrder(0, 1, 7); result.Error == "" || result.ErrorCode != ErrorCodeNotInitialized {
+		t.Fatalf("expected an error before CreateClient, got %d %q", result.ErrorCode, result.Error)
+	}
+	if err := CreateClient("", testPrivateKey, 304, 3, 100); err != "" {
+		t.Fatal(err)
+	}
+	result := SignCancelOrder(0, 1, 7)
+	if result.Error != "" || result.ErrorCode != ErrorCodeNone {
+		t.Fatal(result.Error)
+	}
+	if !json.Valid([]byte(result.JSON)) {
+		t.Fatalf("invalid tx info %s", result.JSON)
+	}
+
+	// the Result variants return the code of the error message
+	for _, tc := range []struct {
+		result   *ErrorResult
+		expected int
+	}{
+		{SwitchAPIKeyResult(3), ErrorCodeNone},
+		{SwitchAPIKeyResult(4), ErrorCodeNotInitialized},
+		{CheckClientResult(256, 100), ErrorCodeInvalidParams},
+		{SetMaxTransferFeeResult(-1), ErrorCodeInvalidParams},
+		{CreateClientResult("", testPrivateKey, 304, 256, 100), ErrorCodeInvalidParams},
+	} {
+		if tc.result.ErrorCode != tc.expected || (tc.result.Error == "") != (tc.expected == ErrorCodeNone) {
+			t.Errorf("expected error code %d, got %d %q", tc.expected, tc.result.ErrorCode, tc.result.Error)
+		}
+	}
+}
+
+// TestClientParamsRange checks the ints narrowed to smaller fields are rejected when out of range, instead of
+// being truncated to other values, e.g. market 256 to market 0
+func TestClientParamsRange(t *testing.T) {
+	c, err := NewClient("", testPrivateKey, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	orderJSON := `[{"marketIndex":0,"baseAmount":1000,"price":300000,"isAsk":0,"type":0,"timeInForce":1}]`
+	createOrder := func(marketIndex, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice int) error {
+		_, err := c.SignCreateOrder(marketIndex, 7, 1000, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice, -1, 42)
+		return err
+	}
+	shimError := func(err string) error {
+		if err == "" {
+			return nil
+		}
+		return errors.New(err)
+	}
+	for _, tc := range []struct {
+		name   string
+		values []int
+		call   func(v int) error
+	}{
+		{"chain id", []int{1 << 32, -1}, func(v int) error {
+			_, err := NewClient("", testPrivateKey, v, 3, 100)
+			return err
+		}},
+		{"chain id", []int{1 << 32, -1}, func(v int) error {
+			return shimError(CreateClient("", testPrivateKey, v, 3, 100))
+		}},
+		{"api key index", []int{256, -1}, func(v int) error {
+			return shimError(CreateClient("", testPrivateKey, 304, v, 100))
+		}},
+		{"tag bits", []int{256, -1}, func(v int) error {
+			return c.ConfigureClientOrderIndex(v, 0, 0)
+		}},
+		{"tx type", []int{256, -1}, func(v int) error {
+			_, err := c.SendTx(v, "{}")
+			return err
+		}},
+		{"market index", []int{256, -1}, func(v int) error { return createOrder(v, 300000, 0, 0, 1, 0, 0) }},
+		{"price", []int{1 << 32, -1}, func(v int) error { return createOrder(0, v, 0, 0, 1, 0, 0) }},
+		{"is ask", []int{256, -1}, func(v int) error { return createOrder(0, 300000, v, 0, 1, 0, 0) }},
+		{"order type", []int{256, -1}, func(v int) error { return createOrder(0, 300000, 0, v, 1, 0, 0) }},
+		{"time in force", []int{256, -1}, func(v int) error { return createOrder(0, 300000, 0, 0, v, 0, 0) }},
+		{"reduce only", []int{256, -1}, func(v int) error { return createOrder(0, 300000, 0, 0, 1, v, 0) }},
+		{"trigger price", []int{1 << 32, -1}, func(v int) error { return createOrder(0, 300000, 0, 0, 1, 0, v) }},
+		{"grouping type", []int{256, -1}, func(v int) error {
+			_, err := c.SignCreateGroupedOrders(v, orderJSON, 42)
+			return err
+		}},
+		{"market index", []int{256, -1}, func(v int) error {
+			_, err := c.SignCancelOrder(v, 1, 42)
+			return err
+		}},
+		{"time in force", []int{256, -1}, func(v int) error {
+			_, err := c.SignCancelAllOrders(v, 0, 42)
+			return err
+		}},
+		{"market index", []int{256, -1}, func(v int) error {
+			_, err := c.SignModifyOrder(v, 1, 1000, 300000, 0, 42)
+			return err
+		}},
+		{"price", []int{1 << 32, -1}, func(v int) error {
+			_, err := c.SignModifyOrder(0, 1, 1000, int64(v), 0, 42)
+			return err
+		}},
+		{"trigger price", []int{1 << 32, -1}, func(v int) error {
+			_, err := c.SignModifyOrder(0, 1, 1000, 300000, int64(v), 42)
+			return err
+		}},
+		{"status", []int{256, -1}, func(v int) error {
+			_, err := c.SignUpdatePublicPool(1000, v, 50_000, 1000, 42)
+			return err
+		}},
+		{"market index", []int{256, -1}, func(v int) error {
+			_, err := c.SignUpdateLeverage(v, 500, 0, 42)
+			return err
+		}},
+		{"initial margin fraction", []int{1 << 16, -1}, func(v int) error {
+			_, err := c.SignUpdateLeverage(0, v, 0, 42)
+			return err
+		}},
+		{"margin mode", []int{256, -1}, func(v int) error {
+			_, err := c.SignUpdateLeverage(0, 500, v, 42)
+			return err
+		}},
+		{"market index", []int{256, -1}, func(v int) error {
+			_, err := c.SignUpdateMargin(v, 1_000_000, 0, 42)
+			return err
+		}},
+		{"direction", []int{256, -1}, func(v int) error {
+			_, err := c.SignUpdateMargin(0, 1_000_000, v, 42)
+			return err
+		}},
+	} {
+		for _, v := range tc.values {
+			err := tc.call(v)
+			if err == nil || !strings.Contains(err.Error(), fmt.Sprintf("invalid %s %d", tc.name, v)) {
+				t.Errorf("%s %d: expected an invalid params error, got %v", tc.name, v, err)
+			}
+		}
+	}
+}
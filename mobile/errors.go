@@ -0,0 +1,32 @@
+package mobile
+
+import (
+	"github.com/elliottech/lighter-go/core"
+)
+
+// The codes of the errors, in TxResult.ErrorCode, APIKeyResult.ErrorCode and TxCallback.OnError, and returned by
+// ErrorCode for the errors thrown by Client. They're stable, so apps can react to errors and localize them
+// without parsing the messages. The codes from ErrorCodeValidation are the validation errors of the tx fields,
+// named by ErrorCodeName, e.g. "BaseAmountTooLow".
+const (
+	ErrorCodeNone           = int(core.ErrorCodeNone)
+	ErrorCodeUnknown        = int(core.ErrorCodeUnknown)
+	ErrorCodeInvalidParams  = int(core.ErrorCodeInvalidParams)
+	ErrorCodeNotInitialized = int(core.ErrorCodeNotInitialized)
+	ErrorCodeInvalidKey     = int(core.ErrorCodeInvalidKey)
+	ErrorCodeNonce          = int(core.ErrorCodeNonce)
+	ErrorCodeNetwork        = int(core.ErrorCodeNetwork)
+	ErrorCodeRejected       = int(core.ErrorCodeRejected)
+	ErrorCodeUnavailable    = int(core.ErrorCodeUnavailable)
+	ErrorCodeValidation     = int(core.ErrorCodeValidation)
+)
+
+// ErrorCode returns the code of an error thrown by the methods of Client, or ErrorCodeNone if err is nil
+func ErrorCode(err error) int {
+	return int(core.ErrorCodeOf(err))
+}
+
+// ErrorCodeName returns the name of an error code, like "NotInitialized" or "BaseAmountTooLow"
+func ErrorCodeName(code int) string {
+	return core.ErrorCode(code).String()
+}
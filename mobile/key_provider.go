@@ -0,0 +1,40 @@
+package mobile
+
+import (
+	"github.com/elliottech/lighter-go/core"
+	"github.com/elliottech/lighter-go/signer"
+)
+
+// KeyProvider signs with an api key which the app doesn't pass to Go, e.g. one kept in the iOS Keychain
+// and only decrypted when signing. It's implemented by the app, and called from Go threads, concurrently.
+type KeyProvider interface {
+	// Sign returns the 80 bytes signature of the 40 bytes hash of a tx, or throws
+	Sign(hash []byte) ([]byte, error)
+	// PublicKey returns the 40 bytes public key of the api key
+	PublicKey() []byte
+}
+
+// NewClientWithKeyProvider creates a client signing with the api key of keyProvider, see NewClient
+func NewClientWithKeyProvider(url string, keyProvider KeyProvider, chainId, apiKeyIndex int, accountIndex int64) (c *Client, err error) {
+	defer recoverError(&err)
+
+	session := core.NewSession()
+	if err := createClientWithKeyProvider(session, url, keyProvider, chainId, apiKeyIndex, accountIndex); err != nil {
+		return nil, err
+	}
+	return &Client{session: session}, nil
+}
+
+func createClientWithKeyProvider(session *core.Session, url string, keyProvider KeyProvider, chainId, apiKeyIndex int, accountIndex int64) error {
+	var params core.ParamChecker
+	chain := params.Uint32("chain id", int64(chainId))
+	keyIndex := params.Uint8("api key index", int64(apiKeyIndex))
+	if err := params.Err(); err != nil {
+		return err
+	}
+	keyManager, err := signer.NewKeyManagerFromProvider(keyProvider)
+	if err != nil {
+		return core.WithErrorCode(core.ErrorCodeInvalidKey, err)
+	}
+	return session.CreateClientWithKeyManager(url, keyManager, chain, keyIndex, accountIndex)
+}
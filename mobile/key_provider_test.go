@@ -0,0 +1,115 @@
+package mobile
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elliottech/lighter-go/signer"
+	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
+)
+
+// testKeyProvider signs with a key held in Go, as a Keychain would, and records the signatures
+type testKeyProvider struct {
+	key signer.KeyManager
+	err error
+
+	mu   sync.Mutex
+	sigs [][]byte
+}
+
+func (p *testKeyProvider) Sign(hash []byte) ([]byte, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	sig, err := p.key.Sign(hash, p2.NewPoseidon2())
+	if err != nil {
+		return nil, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sigs = append(p.sigs, sig)
+	return sig, nil
+}
+
+func (p *testKeyProvider) PublicKey() []byte {
+	pubKey := p.key.PubKeyBytes()
+	return pubKey[:]
+}
+
+func TestKeyProvider(t *testing.T) {
+	b, err := hex.DecodeString(strings.TrimPrefix(testPrivateKey, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := signer.NewKeyManager(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	provider := &testKeyProvider{key: key}
+
+	if _, err := NewClientWithKeyProvider("", provider, -1, 3, 100); ErrorCode(err) != ErrorCodeInvalidParams {
+		t.Errorf("expected an error for a negative chain id, got %v", err)
+	}
+	if err := CreateClientWithKeyProvider("", provider, 304, 256, 100); err == "" {
+		t.Error("expected an error for an api key index above 255")
+	}
+	c, err := NewClientWithKeyProvider("", provider, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := c.SignCancelOrder(0, 1, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txInfo struct{ Sig []byte }
+	if err := json.Unmarshal([]byte(tx.TxInfo), &txInfo); err != nil {
+		t.Fatal(err)
+	}
+	if len(provider.sigs) != 1 || !bytes.Equal(txInfo.Sig, provider.sigs[0]) {
+		t.Errorf("the tx isn't signed by the key provider")
+	}
+
+	provider.err = errors.New("user canceled")
+	if _, err := c.SignCancelOrder(0, 1, 8); err == nil || !strings.Contains(err.Error(), "user canceled") {
+		t.Errorf("expected the error of the key provider, got %v", err)
+	}
+
+	if _, err := NewClientWithKeyProvider("", &badKeyProvider{}, 304, 3, 100); err == nil {
+		t.Error("expected an error for an invalid public key")
+	}
+
+	// the Keychain signs with another key than the public key it returns
+	other, err := NewAPIKeyPair("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPubKey, err := hex.DecodeString(strings.TrimPrefix(other.PublicKey, "0x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrong, err := NewClientWithKeyProvider("", &wrongKeyProvider{testKeyProvider: &testKeyProvider{key: key}, pubKey: otherPubKey}, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := wrong.SignCancelOrder(0, 1, 9); ErrorCode(err) != ErrorCodeInvalidKey {
+		t.Errorf("expected an InvalidKey error for a signature of another key, got %v", err)
+	}
+}
+
+// wrongKeyProvider returns another public key than the one it signs with
+type wrongKeyProvider struct {
+	*testKeyProvider
+	pubKey []byte
+}
+
+func (p *wrongKeyProvider) PublicKey() []byte { return p.pubKey }
+
+type badKeyProvider struct{}
+
+func (badKeyProvider) Sign(hash []byte) ([]byte, error) { return nil, nil }
+func (badKeyProvider) PublicKey() []byte                { return []byte{1, 2, 3} }
@@ -1,48 +1,64 @@
 package mobile
 
 import (
-	"encoding/json"
-	"fmt"
-	"strings"
-	"time"
-
-	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/core"
 	"github.com/elliottech/lighter-go/types"
-	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
-	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
-var (
-	txClient        *client.TxClient
-	backupTxClients map[int]*client.TxClient
-)
+// The functions below are the API from before Client, kept as a shim over defaultClient, which uses core.DefaultSession.
+// CreateClient adds an api key to defaultClient, and the other functions sign with its current api key.
+// The methods of Client only convert their arguments & results, defaults and validation are implemented
+// by the core package, shared with the C & WASM bindings.
+// The functions returning an error message have a Result variant returning an ErrorResult, with the code of the error.
+var defaultClient = &Client{session: core.DefaultSession}
 
-// GenerateAPIKey generates a new API key pair from an optional seed
-// Pass empty string for seed to generate random key
-func GenerateAPIKey(seed string) *APIKeyResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// This shouldn't happen, but just in case
-		}
-	}()
+func errorResult(err error) *ErrorResult {
+	if err != nil {
+		return &ErrorResult{Error: err.Error(), ErrorCode: ErrorCode(err)}
+	}
+	return &ErrorResult{}
+}
+
+func errResult(err error) *TxResult {
+	return &TxResult{Error: err.Error(), ErrorCode: ErrorCode(err)}
+}
 
-	seedP := &seed
-	if seed == "" {
-		seedP = nil
+func txResult(tx *SignedTx, err error) *TxResult {
+	if err != nil {
+		return errResult(err)
 	}
+	return &TxResult{JSON: tx.TxInfo}
+}
 
-	key := curve.SampleScalar(seedP)
-	publicKeyStr := hexutil.Encode(schnorr.SchnorrPkFromSk(key).ToLittleEndianBytes())
-	privateKeyStr := hexutil.Encode(key.ToLittleEndianBytes())
+func stringResult(s string, err error) *TxResult {
+	if err != nil {
+		return errResult(err)
+	}
+	return &TxResult{JSON: s}
+}
 
+// GenerateAPIKey generates a new API key pair from an optional seed
+// Pass empty string for seed to generate random key
+func GenerateAPIKey(seed string) *APIKeyResult {
+	pair, err := NewAPIKeyPair(seed)
+	if err != nil {
+		return &APIKeyResult{Error: err.Error(), ErrorCode: ErrorCode(err)}
+	}
 	return &APIKeyResult{
-		PrivateKey: privateKeyStr,
-		PublicKey:  publicKeyStr,
-		Error:      "",
+		PrivateKey: pair.PrivateKey,
+		PublicKey:  pair.PublicKey,
 	}
 }
 
+// NewAPIKeyPair generates a new API key pair from an optional seed
+// Pass empty string for seed to generate random key
+func NewAPIKeyPair(seed string) (pair *APIKeyPair, err error) {
+	defer recoverError(&err)
+
+	privateKey, publicKey := core.GenerateAPIKey(seed)
+	return &APIKeyPair{PrivateKey: privateKey, PublicKey: publicKey}, nil
+}
+
 // CreateClient creates a new transaction client
 // url: API endpoint URL
 // privateKey: hex-encoded private key
@@ -50,759 +66,192 @@ func GenerateAPIKey(seed string) *APIKeyResult {
 // apiKeyIndex: index of the API key (0-255)
 // accountIndex: account index (must be > 0)
 func CreateClient(url, privateKey string, chainId, apiKeyIndex int, accountIndex int64) string {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
+	return CreateClientResult(url, privateKey, chainId, apiKeyIndex, accountIndex).Error
+}
 
-	if accountIndex <= 0 {
-		return "invalid account index"
-	}
+// CreateClientResult is CreateClient returning the code of the error too
+func CreateClientResult(url, privateKey string, chainId, apiKeyIndex int, accountIndex int64) *ErrorResult {
+	return errorResult(func() (err error) {
+		defer recoverError(&err)
 
-	httpClient := client.NewHTTPClient(url)
-	var err error
-	txClient, err = client.NewTxClient(httpClient, privateKey, accountIndex, uint8(apiKeyIndex), uint32(chainId))
-	if err != nil {
-		return fmt.Sprintf("error occurred when creating TxClient. err: %v", err)
-	}
-
-	if backupTxClients == nil {
-		backupTxClients = make(map[int]*client.TxClient)
-	}
-	backupTxClients[apiKeyIndex] = txClient
+		var params core.ParamChecker
+		chain := params.Uint32("chain id", int64(chainId))
+		keyIndex := params.Uint8("api key index", int64(apiKeyIndex))
+		if err := params.Err(); err != nil {
+			return err
+		}
+		return defaultClient.session.CreateClient(url, privateKey, chain, keyIndex, accountIndex)
+	}())
+}
 
-	return ""
+// CreateClientWithKeyProvider is CreateClient signing with the api key of keyProvider, so the private key
+// doesn't cross the bridge
+func CreateClientWithKeyProvider(url string, keyProvider KeyProvider, chainId, apiKeyIndex int, accountIndex int64) string {
+	return CreateClientWithKeyProviderResult(url, keyProvider, chainId, apiKeyIndex, accountIndex).Error
 }
 
-// CheckClient verifies that the client is properly configured and matches the API key on Lighter
-func CheckClient(apiKeyIndex int, accountIndex int64) string {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
+// CreateClientWithKeyProviderResult is CreateClientWithKeyProvider returning the code of the error too
+func CreateClientWithKeyProviderResult(url string, keyProvider KeyProvider, chainId, apiKeyIndex int, accountIndex int64) *ErrorResult {
+	return errorResult(func() (err error) {
+		defer recoverError(&err)
 
-	client, ok := backupTxClients[apiKeyIndex]
-	if !ok {
-		return "api key not registered"
-	}
+		return createClientWithKeyProvider(defaultClient.session, url, keyProvider, chainId, apiKeyIndex, accountIndex)
+	}())
+}
 
-	if client.GetApiKeyIndex() != uint8(apiKeyIndex) {
-		return fmt.Sprintf("apiKeyIndex does not match. expected %v but got %v", client.GetApiKeyIndex(), apiKeyIndex)
-	}
-	if client.GetAccountIndex() != accountIndex {
-		return fmt.Sprintf("accountIndex does not match. expected %v but got %v", client.GetAccountIndex(), accountIndex)
-	}
+// ConfigureClientOrderIndex configures the allocator used when SignCreateOrder is called with clientOrderIndex -1
+// tagBits: number of high bits used for a strategy tag (0-8), use 0 for no tag
+// tag: strategy tag encoded in every allocated client order index
+// lastClientOrderIndex: last index allocated by a previous run, use 0 if unknown
+func ConfigureClientOrderIndex(tagBits int, tag, lastClientOrderIndex int64) string {
+	return ConfigureClientOrderIndexResult(tagBits, tag, lastClientOrderIndex).Error
+}
 
-	// check that the API key registered on Lighter matches this one
-	key, err := client.HTTP().GetApiKey(accountIndex, uint8(apiKeyIndex))
-	if err != nil {
-		return fmt.Sprintf("failed to get Api Keys. err: %v", err)
-	}
+// ConfigureClientOrderIndexResult is ConfigureClientOrderIndex returning the code of the error too
+func ConfigureClientOrderIndexResult(tagBits int, tag, lastClientOrderIndex int64) *ErrorResult {
+	return errorResult(defaultClient.ConfigureClientOrderIndex(tagBits, tag, lastClientOrderIndex))
+}
 
-	pubKeyBytes := client.GetKeyManager().PubKeyBytes()
-	pubKeyStr := hexutil.Encode(pubKeyBytes[:])
-	pubKeyStr = strings.Replace(pubKeyStr, "0x", "", 1)
+// SetMaxTransferFee sets the highest fee accepted by SignTransfer when the fee is -1
+// maxFee: USDC amount with 6 decimals, 10 USDC by default
+func SetMaxTransferFee(maxFee int64) string {
+	return SetMaxTransferFeeResult(maxFee).Error
+}
 
-	ak := key.ApiKeys[0]
-	if ak.PublicKey != pubKeyStr {
-		return fmt.Sprintf("private key does not match the one on Lighter. ownPubKey: %s response: %+v", pubKeyStr, ak)
-	}
+// SetMaxTransferFeeResult is SetMaxTransferFee returning the code of the error too
+func SetMaxTransferFeeResult(maxFee int64) *ErrorResult {
+	return errorResult(defaultClient.SetMaxTransferFee(maxFee))
+}
 
-	return ""
+// CheckClient verifies that the client is properly configured and matches the API key on Lighter
+func CheckClient(apiKeyIndex int, accountIndex int64) string {
+	return CheckClientResult(apiKeyIndex, accountIndex).Error
+}
+
+// CheckClientResult is CheckClient returning the code of the error too
+func CheckClientResult(apiKeyIndex int, accountIndex int64) *ErrorResult {
+	return errorResult(defaultClient.CheckClient(apiKeyIndex, accountIndex))
 }
 
 // SignChangePubKey signs a change public key transaction
 // pubKey: hex-encoded new public key (40 bytes)
 // nonce: transaction nonce, use -1 for automatic
 func SignChangePubKey(pubKey string, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	// handle PubKey
-	pubKeyBytes, err := hexutil.Decode(pubKey)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-	if len(pubKeyBytes) != 40 {
-		return &TxResult{Error: fmt.Sprintf("invalid pub key length. expected 40 but got %v", len(pubKeyBytes))}
-	}
-	var pubKeyArray [40]byte
-	copy(pubKeyArray[:], pubKeyBytes)
-
-	txInfo := &types.ChangePubKeyReq{
-		PubKey: pubKeyArray,
-	}
-	ops := &types.TransactOpts{}
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetChangePubKeyTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	// marshal the tx
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignChangePubKey(pubKey, nonce))
 }
 
 // SignCreateOrder signs a create order transaction
+// Pass -1 for clientOrderIndex to allocate one automatically, see ConfigureClientOrderIndex
 // Pass -1 for orderExpiry to use default (28 days)
 // Pass -1 for nonce for automatic nonce
 func SignCreateOrder(marketIndex int, clientOrderIndex, baseAmount int64, price int,
 	isAsk, orderType, timeInForce, reduceOnly int, triggerPrice int,
 	orderExpiry, nonce int64) *TxResult {
-
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	if orderExpiry == -1 {
-		orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
-	}
-
-	txInfo := &types.CreateOrderTxReq{
-		MarketIndex:      uint8(marketIndex),
-		ClientOrderIndex: clientOrderIndex,
-		BaseAmount:       baseAmount,
-		Price:            uint32(price),
-		IsAsk:            uint8(isAsk),
-		Type:             uint8(orderType),
-		TimeInForce:      uint8(timeInForce),
-		ReduceOnly:       uint8(reduceOnly),
-		TriggerPrice:     uint32(triggerPrice),
-		OrderExpiry:      orderExpiry,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateOrderTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignCreateOrder(marketIndex, clientOrderIndex, baseAmount, price,
+		isAsk, orderType, timeInForce, reduceOnly, triggerPrice, orderExpiry, nonce))
 }
 
 // SignCreateGroupedOrders signs a create grouped orders transaction
 // ordersJSON should be a JSON array of order objects
 // groupingType: 0 = None, 1 = OCO (One-Cancels-Other), 2 = OTO (One-Triggers-Other), 3 = OTOCO
 func SignCreateGroupedOrders(groupingType int, ordersJSON string, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	// Parse orders from JSON
-	var ordersData []map[string]interface{}
-	if err := json.Unmarshal([]byte(ordersJSON), &ordersData); err != nil {
-		return &TxResult{Error: fmt.Sprintf("failed to parse orders JSON: %v", err)}
-	}
-
-	// Convert to CreateOrderTxReq slice
-	orders := make([]*types.CreateOrderTxReq, 0, len(ordersData))
-	for i, orderData := range ordersData {
-		marketIndex, ok := orderData["marketIndex"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: marketIndex is required", i)}
-		}
-
-		clientOrderIndex, ok := orderData["clientOrderIndex"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: clientOrderIndex is required", i)}
-		}
-
-		baseAmount, ok := orderData["baseAmount"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: baseAmount is required", i)}
-		}
-
-		price, ok := orderData["price"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: price is required", i)}
-		}
-
-		isAsk, ok := orderData["isAsk"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: isAsk is required", i)}
-		}
-
-		orderType, ok := orderData["type"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: type is required", i)}
-		}
-
-		timeInForce, ok := orderData["timeInForce"].(float64)
-		if !ok {
-			return &TxResult{Error: fmt.Sprintf("order %d: timeInForce is required", i)}
-		}
-
-		reduceOnly := uint8(0)
-		if ro, ok := orderData["reduceOnly"].(float64); ok {
-			reduceOnly = uint8(ro)
-		}
-
-		triggerPrice := uint32(0)
-		if tp, ok := orderData["triggerPrice"].(float64); ok {
-			triggerPrice = uint32(tp)
-		}
-
-		orderExpiry := int64(-1)
-		if oe, ok := orderData["orderExpiry"].(float64); ok {
-			orderExpiry = int64(oe)
-		}
-		if orderExpiry == -1 {
-			orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
-		}
-
-		orders = append(orders, &types.CreateOrderTxReq{
-			MarketIndex:      uint8(marketIndex),
-			ClientOrderIndex: int64(clientOrderIndex),
-			BaseAmount:       int64(baseAmount),
-			Price:            uint32(price),
-			IsAsk:            uint8(isAsk),
-			Type:             uint8(orderType),
-			TimeInForce:      uint8(timeInForce),
-			ReduceOnly:       reduceOnly,
-			TriggerPrice:     triggerPrice,
-			OrderExpiry:      orderExpiry,
-		})
-	}
-
-	txInfo := &types.CreateGroupedOrdersTxReq{
-		GroupingType: uint8(groupingType),
-		Orders:       orders,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateGroupedOrdersTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignCreateGroupedOrders(groupingType, ordersJSON, nonce))
 }
 
 // SignCancelOrder signs a cancel order transaction
 func SignCancelOrder(marketIndex int, orderIndex, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.CancelOrderTxReq{
-		MarketIndex: uint8(marketIndex),
-		Index:       orderIndex,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCancelOrderTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignCancelOrder(marketIndex, orderIndex, nonce))
 }
 
 // SignWithdraw signs a withdraw transaction
 func SignWithdraw(usdcAmount int64, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := types.WithdrawTxReq{
-		USDCAmount: uint64(usdcAmount),
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetWithdrawTransaction(&txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignWithdraw(usdcAmount, nonce))
 }
 
 // SignCreateSubAccount signs a create sub account transaction
 func SignCreateSubAccount(nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateSubAccountTransaction(ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignCreateSubAccount(nonce))
 }
 
 // SignCancelAllOrders signs a cancel all orders transaction
 func SignCancelAllOrders(timeInForce int, time, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.CancelAllOrdersTxReq{
-		TimeInForce: uint8(timeInForce),
-		Time:        time,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCancelAllOrdersTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignCancelAllOrders(timeInForce, time, nonce))
 }
 
 // SignModifyOrder signs a modify order transaction
 func SignModifyOrder(marketIndex int, index, baseAmount int64, price, triggerPrice int64, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.ModifyOrderTxReq{
-		MarketIndex:  uint8(marketIndex),
-		Index:        index,
-		BaseAmount:   baseAmount,
-		Price:        uint32(price),
-		TriggerPrice: uint32(triggerPrice),
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetModifyOrderTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignModifyOrder(marketIndex, index, baseAmount, price, triggerPrice, nonce))
 }
 
 // SignTransfer signs a transfer transaction
+// Pass -1 for fee to request it from Lighter (fails if higher than the max transfer fee, see SetMaxTransferFee)
 // memo must be exactly 32 bytes
 func SignTransfer(toAccountIndex, usdcAmount, fee int64, memo string, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	memoArray := [32]byte{}
-	if len(memo) != 32 {
-		return &TxResult{Error: "memo expected to be 32 bytes long"}
-	}
-	for i := 0; i < 32; i++ {
-		memoArray[i] = byte(memo[i])
-	}
-
-	txInfo := &types.TransferTxReq{
-		ToAccountIndex: toAccountIndex,
-		USDCAmount:     usdcAmount,
-		Fee:            fee,
-		Memo:           memoArray,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetTransferTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignTransfer(toAccountIndex, usdcAmount, fee, memo, nonce))
 }
 
 // SignCreatePublicPool signs a create public pool transaction
 func SignCreatePublicPool(operatorFee, initialTotalShares, minOperatorShareRate, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.CreatePublicPoolTxReq{
-		OperatorFee:          operatorFee,
-		InitialTotalShares:   initialTotalShares,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignCreatePublicPool(operatorFee, initialTotalShares, minOperatorShareRate, nonce))
 }
 
 // SignUpdatePublicPool signs an update public pool transaction
 func SignUpdatePublicPool(publicPoolIndex int64, status int, operatorFee, minOperatorShareRate, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.UpdatePublicPoolTxReq{
-		PublicPoolIndex:      publicPoolIndex,
-		Status:               uint8(status),
-		OperatorFee:          operatorFee,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignUpdatePublicPool(publicPoolIndex, status, operatorFee, minOperatorShareRate, nonce))
 }
 
 // SignMintShares signs a mint shares transaction
 func SignMintShares(publicPoolIndex, shareAmount, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.MintSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetMintSharesTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignMintShares(publicPoolIndex, shareAmount, nonce))
 }
 
 // SignBurnShares signs a burn shares transaction
 func SignBurnShares(publicPoolIndex, shareAmount, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.BurnSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetBurnSharesTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignBurnShares(publicPoolIndex, shareAmount, nonce))
 }
 
 // SignUpdateLeverage signs an update leverage transaction
 func SignUpdateLeverage(marketIndex, initialMarginFraction, marginMode int, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.UpdateLeverageTxReq{
-		MarketIndex:           uint8(marketIndex),
-		InitialMarginFraction: uint16(initialMarginFraction),
-		MarginMode:            uint8(marginMode),
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdateLeverageTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignUpdateLeverage(marketIndex, initialMarginFraction, marginMode, nonce))
 }
 
 // SignUpdateMargin signs an update margin transaction
 func SignUpdateMargin(marketIndex int, usdcAmount int64, direction int, nonce int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "Client is not created, call CreateClient() first"}
-	}
-
-	txInfo := &types.UpdateMarginTxReq{
-		MarketIndex: uint8(marketIndex),
-		USDCAmount:  usdcAmount,
-		Direction:   uint8(direction),
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdateMarginTransaction(txInfo, ops)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: string(txInfoBytes), Error: ""}
+	return txResult(defaultClient.SignUpdateMargin(marketIndex, usdcAmount, direction, nonce))
 }
 
 // CreateAuthToken creates an authentication token
-// deadline: Unix timestamp, use 0 for default (7 hours from now)
+// deadline: Unix timestamp, use 0 for 7 hours from now, or -1 for a cached token which is refreshed automatically and valid for at least 1 hour
 func CreateAuthToken(deadline int64) *TxResult {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
-
-	if txClient == nil {
-		return &TxResult{Error: "client is not created, call CreateClient() first"}
-	}
-
-	if deadline == 0 {
-		deadline = time.Now().Add(time.Hour * 7).Unix()
-	}
-
-	authToken, err := txClient.GetAuthToken(time.Unix(deadline, 0))
-	if err != nil {
-		return &TxResult{Error: err.Error()}
-	}
-
-	return &TxResult{JSON: authToken, Error: ""}
+	return stringResult(defaultClient.CreateAuthToken(deadline))
 }
 
 // SwitchAPIKey switches the active API key to the one at the specified index
 func SwitchAPIKey(apiKeyIndex int) string {
-	defer func() {
-		if r := recover(); r != nil {
-			// Handle panic
-		}
-	}()
+	return SwitchAPIKeyResult(apiKeyIndex).Error
+}
 
-	txClient = backupTxClients[apiKeyIndex]
-	if txClient == nil {
-		return "no client initialized for api key"
-	}
+// SwitchAPIKeyResult is SwitchAPIKey returning the code of the error too
+func SwitchAPIKeyResult(apiKeyIndex int) *ErrorResult {
+	return errorResult(defaultClient.SwitchAPIKey(apiKeyIndex))
+}
 
-	return ""
+// Call runs any function by name, with its params as a JSON object, e.g. Call("SignCancelOrder", `{"marketIndex":0,"index":12}`)
+// A missing nonce is -1 (automatic). The result JSON is in TxResult.JSON.
+func Call(method, paramsJSON string) *TxResult {
+	return stringResult(defaultClient.Call(method, paramsJSON))
 }
 
+// ParseUSDC converts a decimal USDC amount like "12.5" to the integer amount with 6 decimals expected by the Sign functions
+func ParseUSDC(amount string) (int64, error) {
+	usdc, err := types.ParseUSDC(amount)
+	if err != nil {
+		return 0, core.WithErrorCode(core.ErrorCodeInvalidParams, err)
+	}
+	return int64(usdc), nil
+}
+
+// FormatUSDC converts an integer USDC amount with 6 decimals to a decimal string like "12.5"
+func FormatUSDC(amount int64) string {
+	return types.USDC(amount).String()
+}
@@ -0,0 +1,7 @@
+//go:build tools
+
+package mobile
+
+// gomobile bind compiles this package with golang.org/x/mobile/bind, which has to stay in go.mod.
+// Nothing imports it otherwise, so `go mod tidy` would drop it.
+import _ "golang.org/x/mobile/bind"
@@ -5,11 +5,30 @@ type APIKeyResult struct {
 	PrivateKey string
 	PublicKey  string
 	Error      string
+	ErrorCode  int // one of the ErrorCode constants, set with Error
 }
 
 // TxResult holds the result of a transaction signing operation
 type TxResult struct {
-	JSON  string
-	Error string
+	JSON      string
+	Error     string
+	ErrorCode int // one of the ErrorCode constants, set with Error
 }
 
+// ErrorResult holds the error of a function without a result, Error is empty on success
+type ErrorResult struct {
+	Error     string
+	ErrorCode int // one of the ErrorCode constants, set with Error
+}
+
+// APIKeyPair is an API key pair, hex encoded
+type APIKeyPair struct {
+	PrivateKey string
+	PublicKey  string
+}
+
+// SignedTx is a signed transaction, to send to Lighter with its type
+type SignedTx struct {
+	TxType int    `json:"txType"`
+	TxInfo string `json:"txInfo"`
+}
@@ -0,0 +1,159 @@
+package main
+
+// The functions of this file call the exported functions through their C signatures, like the apps loading the
+// library do, for the ABI tests. cgo can't be used in _test.go files, so they live here, and aren't part of the
+// released library, which is built from sharedlib.go only.
+
+/*
+#include "lighter.h"
+*/
+import "C"
+
+import (
+	"errors"
+	"unsafe"
+
+	"github.com/elliottech/lighter-go/core"
+)
+
+// cErr converts the err & errCode of a result returned to C, which the caller frees
+func cErr(p *C.char, code C.int) error {
+	if p == nil {
+		return nil
+	}
+	return core.WithErrorCode(core.ErrorCode(code), errors.New(C.GoString(p)))
+}
+
+// takeErr converts an Err returned to C, and frees it
+func takeErr(r C.Err) error {
+	defer C.FreeErr(r)
+
+	return cErr(r.err, r.errCode)
+}
+
+// takeStrOrErr converts a StrOrErr returned to C, and frees it
+func takeStrOrErr(r C.StrOrErr) (string, error) {
+	defer C.FreeStrOrErr(r)
+
+	if r.err != nil {
+		return "", cErr(r.err, r.errCode)
+	}
+	return C.GoString(r.str), nil
+}
+
+// withCStrings passes strs to fn as C strings, freed once fn returns
+func withCStrings(fn func(cs []*C.char), strs ...string) {
+	cs := make([]*C.char, len(strs))
+	for i, s := range strs {
+		cs[i] = C.CString(s)
+		defer C.free(unsafe.Pointer(cs[i]))
+	}
+	fn(cs)
+}
+
+func abiVersion() string {
+	return C.GoString(C.LighterVersion())
+}
+
+func abiErrorCodeName(code core.ErrorCode) string {
+	return C.GoString(C.LighterErrorCodeName(C.int(code)))
+}
+
+func abiGenerateAPIKey(seed string) (privateKey, publicKey string, err error) {
+	withCStrings(func(cs []*C.char) {
+		r := C.GenerateAPIKey(cs[0])
+		defer C.FreeApiKeyResponse(r)
+
+		if r.err != nil {
+			err = cErr(r.err, r.errCode)
+			return
+		}
+		privateKey, publicKey = C.GoString(r.privateKey), C.GoString(r.publicKey)
+	}, seed)
+	return
+}
+
+func abiCreateClient(url, privateKey string, chainId, apiKeyIndex int, accountIndex int64) (handle int64, err error) {
+	withCStrings(func(cs []*C.char) {
+		r := C.CreateClient(cs[0], cs[1], C.int(chainId), C.int(apiKeyIndex), C.longlong(accountIndex))
+		defer C.FreeClientOrErr(r)
+
+		handle, err = int64(r.handle), cErr(r.err, r.errCode)
+	}, url, privateKey)
+	return
+}
+
+func abiAddAPIKey(handle int64, privateKey string, apiKeyIndex int) (err error) {
+	withCStrings(func(cs []*C.char) {
+		err = takeErr(C.AddAPIKey(C.longlong(handle), cs[0], C.int(apiKeyIndex)))
+	}, privateKey)
+	return
+}
+
+func abiSwitchAPIKey(handle int64, apiKeyIndex int) error {
+	return takeErr(C.SwitchAPIKey(C.longlong(handle), C.int(apiKeyIndex)))
+}
+
+func abiSetMaxTransferFee(handle int64, maxFee int64) error {
+	return takeErr(C.SetMaxTransferFee(C.longlong(handle), C.longlong(maxFee)))
+}
+
+func abiDestroyClient(handle int64) error {
+	return takeErr(C.DestroyClient(C.longlong(handle)))
+}
+
+func abiConfigureClientOrderIndex(handle int64, tagBits int, tag, lastClientOrderIndex int64) error {
+	return takeErr(C.ConfigureClientOrderIndex(C.longlong(handle), C.int(tagBits), C.longlong(tag), C.longlong(lastClientOrderIndex)))
+}
+
+func abiSignCreateOrder(handle int64, marketIndex int, clientOrderIndex, baseAmount int64, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice int, orderExpiry, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignCreateOrder(C.longlong(handle), C.int(marketIndex), C.longlong(clientOrderIndex), C.longlong(baseAmount),
+		C.int(price), C.int(isAsk), C.int(orderType), C.int(timeInForce), C.int(reduceOnly), C.int(triggerPrice), C.longlong(orderExpiry), C.longlong(nonce)))
+}
+
+func abiSignCreateGroupedOrders(handle int64, groupingType int, ordersJSON string, nonce int64) (txInfo string, err error) {
+	withCStrings(func(cs []*C.char) {
+		txInfo, err = takeStrOrErr(C.SignCreateGroupedOrders(C.longlong(handle), C.int(groupingType), cs[0], C.longlong(nonce)))
+	}, ordersJSON)
+	return
+}
+
+func abiSignCancelOrder(handle int64, marketIndex int, orderIndex, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignCancelOrder(C.longlong(handle), C.int(marketIndex), C.longlong(orderIndex), C.longlong(nonce)))
+}
+
+func abiSignCancelAllOrders(handle int64, timeInForce int, time, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignCancelAllOrders(C.longlong(handle), C.int(timeInForce), C.longlong(time), C.longlong(nonce)))
+}
+
+func abiSignModifyOrder(handle int64, marketIndex int, index, baseAmount, price, triggerPrice, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignModifyOrder(C.longlong(handle), C.int(marketIndex), C.longlong(index), C.longlong(baseAmount),
+		C.longlong(price), C.longlong(triggerPrice), C.longlong(nonce)))
+}
+
+func abiSignUpdatePublicPool(handle int64, publicPoolIndex int64, status int, operatorFee, minOperatorShareRate, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignUpdatePublicPool(C.longlong(handle), C.longlong(publicPoolIndex), C.int(status), C.longlong(operatorFee),
+		C.longlong(minOperatorShareRate), C.longlong(nonce)))
+}
+
+func abiSignUpdateLeverage(handle int64, marketIndex, initialMarginFraction, marginMode int, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignUpdateLeverage(C.longlong(handle), C.int(marketIndex), C.int(initialMarginFraction), C.int(marginMode), C.longlong(nonce)))
+}
+
+func abiSignUpdateMargin(handle int64, marketIndex int, usdcAmount int64, direction int, nonce int64) (string, error) {
+	return takeStrOrErr(C.SignUpdateMargin(C.longlong(handle), C.int(marketIndex), C.longlong(usdcAmount), C.int(direction), C.longlong(nonce)))
+}
+
+func abiSignTransfer(handle int64, toAccountIndex, usdcAmount, fee int64, memo string, nonce int64) (txInfo string, err error) {
+	withCStrings(func(cs []*C.char) {
+		txInfo, err = takeStrOrErr(C.SignTransfer(C.longlong(handle), C.longlong(toAccountIndex), C.longlong(usdcAmount), C.longlong(fee), cs[0], C.longlong(nonce)))
+	}, memo)
+	return
+}
+
+func abiCall(handle int64, method, paramsJSON string) (ret string, err error) {
+	withCStrings(func(cs []*C.char) {
+		ret, err = takeStrOrErr(C.Call(C.longlong(handle), cs[0], cs[1]))
+	}, method, paramsJSON)
+	return
+}
@@ -0,0 +1,295 @@
+//go:build cgo
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/elliottech/lighter-go/core"
+)
+
+const (
+	testPrivateKey  = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728"
+	otherPrivateKey = "0x2827262524232221201f1e1d1c1b1a191817161514131211100f0e0d0c0b0a090807060504030201"
+)
+
+// checkNoLeaks fails the test if a string returned to C during the test wasn't freed
+func checkNoLeaks(t *testing.T) {
+	before := liveCStrings.Load()
+	t.Cleanup(func() {
+		if live := liveCStrings.Load(); live != before {
+			t.Fatalf("%d strings returned to C were not freed", live-before)
+		}
+	})
+}
+
+func TestErrorCodeName(t *testing.T) {
+	if name := abiErrorCodeName(core.ErrorCodeNotInitialized); name != "NotInitialized" {
+		t.Fatalf("unexpected name %q", name)
+	}
+	if name := abiErrorCodeName(-1); name != "Unknown" {
+		t.Fatalf("unexpected name %q for an unknown code", name)
+	}
+}
+
+func TestVersion(t *testing.T) {
+	if v := abiVersion(); v != core.Version {
+		t.Fatalf("LighterVersion() = %q, expected %q", v, core.Version)
+	}
+}
+
+// TestHeader checks the checked-in header matches the exported functions, regenerate it with `just header`
+func TestHeader(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the shared library")
+	}
+
+	out := filepath.Join(t.TempDir(), "lighter.so")
+	cmd := exec.Command("go", "build", "-buildmode=c-shared", "-trimpath", "-o", out, "sharedlib.go")
+	if b, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("failed to build the shared library. err: %v\n%s", err, b)
+	}
+
+	generated, err := os.ReadFile(filepath.Join(filepath.Dir(out), "lighter.h"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkedIn, err := os.ReadFile("lighter.h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, checkedIn) {
+		t.Fatal("lighter.h is out of date, run `just header`")
+	}
+}
+
+func TestABI(t *testing.T) {
+	checkNoLeaks(t)
+
+	privateKey, publicKey, err := abiGenerateAPIKey("seed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(privateKey) != 82 || len(publicKey) != 82 {
+		t.Fatalf("unexpected key pair %s %s", privateKey, publicKey)
+	}
+
+	if _, err := abiCreateClient("", testPrivateKey, 304, 3, 0); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for an invalid account index, got %v", err)
+	}
+	handle, err := abiCreateClient("", testPrivateKey, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txInfo, err := abiSignCreateOrder(handle, 1, 7, 1000, 300000, 1, 0, 1, 0, 0, -1, 42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var order struct{ AccountIndex, Nonce int64 }
+	if err := json.Unmarshal([]byte(txInfo), &order); err != nil {
+		t.Fatal(err)
+	}
+	if order.AccountIndex != 100 || order.Nonce != 42 {
+		t.Fatalf("unexpected tx info %s", txInfo)
+	}
+
+	if _, err := abiSignTransfer(handle, 101, 1_000_000, 0, "memo too long, more than 32 bytes long", 43); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for an invalid memo, got %v", err)
+	}
+	if err := abiSetMaxTransferFee(handle, -1); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for a negative max transfer fee, got %v", err)
+	}
+	if err := abiSetMaxTransferFee(handle, 2_000_000); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := abiSignCreateOrder(handle, 1, 7, 1000, 300000, 1, 0, 1, 0, 0, -1, -1); core.ErrorCodeOf(err) != core.ErrorCodeNonce {
+		t.Fatalf("expected an error for an automatic nonce without url, got %v", err)
+	}
+	_, err = abiSignCreateOrder(handle, 1, 7, 0, 300000, 1, 0, 1, 0, 0, -1, 42)
+	if code := core.ErrorCodeOf(err); abiErrorCodeName(code) != "BaseAmountTooLow" {
+		t.Fatalf("expected a BaseAmountTooLow error, got %d %v", code, err)
+	}
+	if _, err := abiCall(handle, "SignCancelOrder", `{"marketIndex":1,"index":7,"nonce":44}`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := abiCall(handle, "Unknown", ""); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for an unknown method, got %v", err)
+	}
+
+	if err := abiDestroyClient(handle); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := abiSignCreateOrder(handle, 1, 7, 1000, 300000, 1, 0, 1, 0, 0, -1, 42); core.ErrorCodeOf(err) != core.ErrorCodeNotInitialized {
+		t.Fatalf("expected an error for a destroyed handle, got %v", err)
+	}
+	if err := abiDestroyClient(handle); err == nil {
+		t.Fatal("expected an error when destroying a handle twice")
+	}
+}
+
+// TestABIParamsRange checks the C ints narrowed to smaller fields are rejected when out of range, instead of
+// being truncated to other values, e.g. market 256 to market 0
+func TestABIParamsRange(t *testing.T) {
+	checkNoLeaks(t)
+
+	handle, err := abiCreateClient("", testPrivateKey, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer abiDestroyClient(handle)
+
+	orderJSON := `[{"marketIndex":0,"baseAmount":1000,"price":300000,"isAsk":0,"type":0,"timeInForce":1}]`
+	createOrder := func(marketIndex, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice int) error {
+		_, err := abiSignCreateOrder(handle, marketIndex, 7, 1000, price, isAsk, orderType, timeInForce, reduceOnly, triggerPrice, -1, 42)
+		return err
+	}
+	for _, tc := range []struct {
+		name   string
+		values []int64
+		call   func(v int64) error
+	}{
+		{"chain id", []int64{-1}, func(v int64) error {
+			_, err := abiCreateClient("", testPrivateKey, int(v), 3, 100)
+			return err
+		}},
+		{"tag bits", []int64{256, -1}, func(v int64) error {
+			return abiConfigureClientOrderIndex(handle, int(v), 0, 0)
+		}},
+		{"market index", []int64{256, -1}, func(v int64) error { return createOrder(int(v), 300000, 0, 0, 1, 0, 0) }},
+		{"price", []int64{-1}, func(v int64) error { return createOrder(0, int(v), 0, 0, 1, 0, 0) }},
+		{"is ask", []int64{256, -1}, func(v int64) error { return createOrder(0, 300000, int(v), 0, 1, 0, 0) }},
+		{"order type", []int64{256, -1}, func(v int64) error { return createOrder(0, 300000, 0, int(v), 1, 0, 0) }},
+		{"time in force", []int64{256, -1}, func(v int64) error { return createOrder(0, 300000, 0, 0, int(v), 0, 0) }},
+		{"reduce only", []int64{256, -1}, func(v int64) error { return createOrder(0, 300000, 0, 0, 1, int(v), 0) }},
+		{"trigger price", []int64{-1}, func(v int64) error { return createOrder(0, 300000, 0, 0, 1, 0, int(v)) }},
+		{"grouping type", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignCreateGroupedOrders(handle, int(v), orderJSON, 42)
+			return err
+		}},
+		{"market index", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignCancelOrder(handle, int(v), 1, 42)
+			return err
+		}},
+		{"time in force", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignCancelAllOrders(handle, int(v), 0, 42)
+			return err
+		}},
+		{"market index", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignModifyOrder(handle, int(v), 1, 1000, 300000, 0, 42)
+			return err
+		}},
+		{"price", []int64{1 << 32, -1}, func(v int64) error {
+			_, err := abiSignModifyOrder(handle, 0, 1, 1000, v, 0, 42)
+			return err
+		}},
+		{"trigger price", []int64{1 << 32, -1}, func(v int64) error {
+			_, err := abiSignModifyOrder(handle, 0, 1, 1000, 300000, v, 42)
+			return err
+		}},
+		{"status", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignUpdatePublicPool(handle, 1000, int(v), 50_000, 1000, 42)
+			return err
+		}},
+		{"market index", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignUpdateLeverage(handle, int(v), 500, 0, 42)
+			return err
+		}},
+		{"initial margin fraction", []int64{1 << 16, -1}, func(v int64) error {
+			_, err := abiSignUpdateLeverage(handle, 0, int(v), 0, 42)
+			return err
+		}},
+		{"margin mode", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignUpdateLeverage(handle, 0, 500, int(v), 42)
+			return err
+		}},
+		{"market index", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignUpdateMargin(handle, int(v), 1_000_000, 0, 42)
+			return err
+		}},
+		{"direction", []int64{256, -1}, func(v int64) error {
+			_, err := abiSignUpdateMargin(handle, 0, 1_000_000, int(v), 42)
+			return err
+		}},
+	} {
+		for _, v := range tc.values {
+			err := tc.call(v)
+			if core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams || !strings.Contains(err.Error(), fmt.Sprintf("invalid %s %d", tc.name, v)) {
+				t.Errorf("%s %d: expected an invalid params error, got %v", tc.name, v, err)
+			}
+		}
+	}
+}
+
+func TestABIHandles(t *testing.T) {
+	checkNoLeaks(t)
+
+	first, err := abiCreateClient("", testPrivateKey, 304, 3, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer abiDestroyClient(first)
+	second, err := abiCreateClient("", otherPrivateKey, 304, 5, 200)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer abiDestroyClient(second)
+
+	if err := abiAddAPIKey(first, otherPrivateKey, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := abiSwitchAPIKey(first, 4); err != nil {
+		t.Fatal(err)
+	}
+	// 259 would be api key 3 once truncated to a byte
+	if err := abiSwitchAPIKey(first, 259); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for an api key index above 255, got %v", err)
+	}
+	if err := abiAddAPIKey(first, otherPrivateKey, 260); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for an api key index above 255, got %v", err)
+	}
+	if _, err := abiCreateClient("", testPrivateKey, 304, -1, 100); core.ErrorCodeOf(err) != core.ErrorCodeInvalidParams {
+		t.Fatalf("expected an error for a negative api key index, got %v", err)
+	}
+	if err := abiSwitchAPIKey(second, 4); err == nil {
+		t.Fatal("api key 4 was only added to the first client")
+	}
+
+	expected := map[int64]struct{ account, apiKey int64 }{first: {100, 4}, second: {200, 5}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		handle := first
+		if i%2 == 1 {
+			handle = second
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			txInfo, err := abiSignCreateOrder(handle, 1, int64(i), 1000, 300000, 1, 0, 1, 0, 0, -1, int64(i))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			var order struct{ AccountIndex, ApiKeyIndex int64 }
+			if err := json.Unmarshal([]byte(txInfo), &order); err != nil {
+				t.Error(err)
+				return
+			}
+			if e := expected[handle]; order.AccountIndex != e.account || order.ApiKeyIndex != e.apiKey {
+				t.Errorf("handle %d signed for account %d api key %d, expected %d & %d", handle, order.AccountIndex, order.ApiKeyIndex, e.account, e.apiKey)
+			}
+		}()
+	}
+	wg.Wait()
+}
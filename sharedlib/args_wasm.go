@@ -0,0 +1,189 @@
+//go:build js && wasm
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall/js"
+
+	"github.com/elliottech/lighter-go/types"
+)
+
+// maxSafeInteger is Number.MAX_SAFE_INTEGER, the largest integer a JS number holds exactly
+const maxSafeInteger = 1<<53 - 1
+
+var usdcType = reflect.TypeOf(types.USDC(0))
+
+// paramsJSON converts a params object to the JSON taken by core.Session.Call, using the params struct of the
+// method for the types of the fields. Integers can be given as numbers, BigInt or decimal strings, and numbers
+// which aren't safe integers are rejected, as JS rounded them already. USDC amounts can only be decimal strings.
+// Missing fields are left out, so they get their default, and unknown fields are rejected, so a misspelled
+// optional field isn't silently ignored.
+func paramsJSON(params js.Value, paramsType reflect.Type) (string, error) {
+	if params.IsUndefined() || params.IsNull() {
+		return "", nil
+	}
+	b, err := jsToJSON(params, paramsType)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func jsToJSON(v js.Value, t reflect.Type) (json.RawMessage, error) {
+	switch {
+	case t == usdcType:
+		// only a decimal string like "12.5", as a number could be read as USDC or with 6 implied decimals
+		if isBigInt(v) || v.Type() != js.TypeString {
+			return nil, fmt.Errorf("expected a USDC amount as a decimal string like \"12.5\"")
+		}
+		return json.Marshal(v.String())
+	case t.Kind() == reflect.String:
+		if isBigInt(v) || v.Type() != js.TypeString {
+			return nil, fmt.Errorf("expected a string")
+		}
+		return json.Marshal(v.String())
+	case t.Kind() == reflect.Bool:
+		if isBigInt(v) || v.Type() != js.TypeBoolean {
+			return nil, fmt.Errorf("expected a boolean")
+		}
+		return json.Marshal(v.Bool())
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		return jsIntegerJSON(v)
+	case t.Kind() == reflect.Slice:
+		if !js.Global().Get("Array").Call("isArray", v).Bool() {
+			return nil, fmt.Errorf("expected an array")
+		}
+		elems := make([]json.RawMessage, v.Length())
+		for i := range elems {
+			elem, err := jsToJSON(v.Index(i), t.Elem())
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			elems[i] = elem
+		}
+		return json.Marshal(elems)
+	case t.Kind() == reflect.Struct:
+		return jsObjectToJSON(v, t)
+	default:
+		return nil, fmt.Errorf("unsupported type %s", t)
+	}
+}
+
+func jsObjectToJSON(v js.Value, t reflect.Type) (json.RawMessage, error) {
+	if isBigInt(v) || v.Type() != js.TypeObject {
+		return nil, fmt.Errorf("expected an object")
+	}
+
+	fields := map[string]reflect.Type{}
+	jsonFields(t, fields)
+
+	obj := map[string]json.RawMessage{}
+	keys := js.Global().Get("Object").Call("keys", v)
+	for i := 0; i < keys.Length(); i++ {
+		key := keys.Index(i).String()
+		fieldType, ok := fields[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown field %q", key)
+		}
+		value := v.Get(key)
+		if value.IsUndefined() {
+			continue
+		}
+		b, err := jsToJSON(value, fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		obj[key] = b
+	}
+	return json.Marshal(obj)
+}
+
+// jsonFields collects the JSON names & types of the fields of a struct, including the ones of its embedded structs
+func jsonFields(t reflect.Type, fields map[string]reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			jsonFields(f.Type, fields)
+			continue
+		}
+		fields[strings.Split(f.Tag.Get("json"), ",")[0]] = f.Type
+	}
+}
+
+// jsIntegerJSON converts a number, BigInt or decimal string to a JSON integer.
+// The range of the field is checked when the JSON is decoded.
+func jsIntegerJSON(v js.Value) (json.RawMessage, error) {
+	n, err := jsInt64(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(strconv.FormatInt(n, 10)), nil
+}
+
+func jsInt64(v js.Value) (int64, error) {
+	if isBigInt(v) {
+		return strconv.ParseInt(js.Global().Get("String").Invoke(v).String(), 10, 64)
+	}
+
+	switch v.Type() {
+	case js.TypeNumber:
+		f := v.Float()
+		if f != math.Trunc(f) || math.Abs(f) > maxSafeInteger {
+			return 0, fmt.Errorf("%v is not a safe integer, pass a BigInt or a decimal string", f)
+		}
+		return int64(f), nil
+	case js.TypeString:
+		return strconv.ParseInt(strings.TrimSpace(v.String()), 10, 64)
+	default:
+		return 0, fmt.Errorf("expected an integer, got %s", v.Type())
+	}
+}
+
+// isBigInt checks if v is a BigInt. v.Type() panics for BigInt, so v is boxed into an object first.
+func isBigInt(v js.Value) bool {
+	return js.Global().Get("Object").Invoke(v).InstanceOf(js.Global().Get("BigInt"))
+}
+
+// parseJSON parses a JSON like JSON.parse, except that integers are returned as BigInt,
+// as nonces, account & order indexes don't fit in a JS number
+func parseJSON(s string) (js.Value, error) {
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+
+	var v interface{}
+	if err := decoder.Decode(&v); err != nil {
+		return js.Undefined(), fmt.Errorf("invalid JSON. err: %w", err)
+	}
+	return toJSValue(v), nil
+}
+
+func toJSValue(v interface{}) js.Value {
+	switch v := v.(type) {
+	case json.Number:
+		if _, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return js.Global().Get("BigInt").Invoke(v.String())
+		}
+		f, _ := v.Float64()
+		return js.ValueOf(f)
+	case map[string]interface{}:
+		obj := js.Global().Get("Object").New()
+		for key, value := range v {
+			obj.Set(key, toJSValue(value))
+		}
+		return obj
+	case []interface{}:
+		arr := js.Global().Get("Array").New(len(v))
+		for i, value := range v {
+			arr.SetIndex(i, toJSValue(value))
+		}
+		return arr
+	default:
+		return js.ValueOf(v)
+	}
+}
@@ -2,6 +2,9 @@
 
 set -e
 
+# Usage: ./build.sh [signonly], signonly leaves the HTTP client out, see README.md
+PROFILE=${1:-full}
+
 # Build directory in project root
 BUILD_DIR="../build"
 
@@ -10,7 +13,10 @@ mkdir -p "$BUILD_DIR"
 
 # Try to copy wasm_exec.js from GOROOT, fallback to download
 GOROOT=$(go env GOROOT)
-WASM_EXEC="$GOROOT/misc/wasm/wasm_exec.js"
+WASM_EXEC="$GOROOT/lib/wasm/wasm_exec.js"
+if [ ! -f "$WASM_EXEC" ]; then
+  WASM_EXEC="$GOROOT/misc/wasm/wasm_exec.js"
+fi
 
 if [ -f "$WASM_EXEC" ]; then
   echo "✅ Found wasm_exec.js locally."
@@ -22,9 +28,11 @@ else
   echo "✅ Downloaded wasm_exec.js"
 fi
 
-echo "🔨 Building Go -> WASM..."
-cd "$BUILD_DIR"
-GOOS=js GOARCH=wasm go build -o sharedlib.wasm ../sharedlib/sharedlib_wasm.go
-cd - > /dev/null
+echo "🔨 Building Go -> WASM ($PROFILE)..."
+if [ "$PROFILE" = "signonly" ]; then
+  GOOS=js GOARCH=wasm go build -trimpath -tags signonly -ldflags="-s -w" -o "$BUILD_DIR/sharedlib.wasm" .
+else
+  GOOS=js GOARCH=wasm go build -trimpath -o "$BUILD_DIR/sharedlib.wasm" .
+fi
 
 echo "✅ Build complete: build/sharedlib.wasm and wasm_exec.js saved to root build folder"
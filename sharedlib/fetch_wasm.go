@@ -0,0 +1,122 @@
+//go:build js && wasm && !signonly
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"syscall/js"
+
+	"github.com/elliottech/lighter-go/core"
+)
+
+// fetchTransport sends the HTTP requests of the clients with the fetch API of the JS runtime.
+// The default transport of the client package dials TCP connections, which a browser can't do.
+type fetchTransport struct{}
+
+func init() {
+	newSession = func() *core.Session {
+		s := core.NewSession()
+		s.SetTransport(fetchTransport{})
+		return s
+	}
+}
+
+func (fetchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	headers := js.Global().Get("Headers").New()
+	for key, values := range req.Header {
+		for _, value := range values {
+			headers.Call("append", key, value)
+		}
+	}
+
+	init := js.Global().Get("Object").New()
+	init.Set("method", req.Method)
+	init.Set("headers", headers)
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(body) > 0 {
+			jsBody := js.Global().Get("Uint8Array").New(len(body))
+			js.CopyBytesToJS(jsBody, body)
+			init.Set("body", jsBody)
+		}
+	}
+
+	// abort the request when its context is done, e.g. on the timeout of the http.Client
+	controller := js.Global().Get("AbortController").New()
+	init.Set("signal", controller.Get("signal"))
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-req.Context().Done():
+			controller.Call("abort")
+		case <-done:
+		}
+	}()
+
+	resp, err := await(js.Global().Call("fetch", req.URL.String(), init))
+	if err != nil {
+		return nil, fmt.Errorf("fetch %s failed. err: %w", req.URL.Redacted(), err)
+	}
+
+	header := http.Header{}
+	forEach := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		header.Add(args[1].String(), args[0].String())
+		return nil
+	})
+	resp.Get("headers").Call("forEach", forEach)
+	forEach.Release()
+
+	buf, err := await(resp.Call("arrayBuffer"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the response of %s. err: %w", req.URL.Redacted(), err)
+	}
+	body := make([]byte, buf.Get("byteLength").Int())
+	js.CopyBytesToGo(body, js.Global().Get("Uint8Array").New(buf))
+
+	status := resp.Get("status").Int()
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, resp.Get("statusText").String()),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// await blocks the goroutine until the Promise settles. It must not be called from the goroutine of a js.Func,
+// as the event loop is paused while it runs.
+func await(promise js.Value) (js.Value, error) {
+	type settled struct {
+		value js.Value
+		err   error
+	}
+	ch := make(chan settled, 1)
+
+	onFulfilled := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ch <- settled{value: args[0]}
+		return nil
+	})
+	defer onFulfilled.Release()
+	onRejected := js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		ch <- settled{err: errors.New(js.Global().Get("String").Invoke(args[0]).String())}
+		return nil
+	})
+	defer onRejected.Release()
+
+	promise.Call("then", onFulfilled, onRejected)
+	s := <-ch
+	return s.value, s.err
+}
@@ -0,0 +1,267 @@
+// Command gendts generates the TypeScript definitions of the WASM module, from the params of core.Session.Call.
+//
+//	go run ./sharedlib/gendts -o sharedlib/lighter.d.ts
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/elliottech/lighter-go/core"
+	"github.com/elliottech/lighter-go/types"
+)
+
+// elemDefaults are the defaults of the params in slices, which aren't part of the defaults of their method
+var elemDefaults = map[reflect.Type]any{
+	reflect.TypeOf(core.OrderParams{}): core.NewOrderParams(),
+}
+
+const header = `// Code generated by gendts; DO NOT EDIT.
+
+/** An integer, as a number if it's a safe integer, a BigInt or a decimal string. */
+export type Int64 = number | bigint | string;
+
+/** A USDC amount, as a decimal string like "12.5". Numbers are rejected, as they're ambiguous. */
+export type USDC = string;
+
+/** The Error the Promises reject with, method being the function which failed, and code one of lighter.errorCodes. */
+export interface LighterError extends Error {
+  name: "LighterError";
+  method: string;
+  code: number;
+}
+
+export interface APIKeyPair {
+  privateKey: string;
+  publicKey: string;
+}
+
+/** An api key registered on Lighter, as returned by getApiKeys. */
+export interface ApiKey {
+  account_index: bigint;
+  api_key_index: bigint;
+  nonce: bigint;
+  public_key: string;
+}
+`
+
+// jsonResults are the types of the methods returning ResultJSON
+var jsonResults = map[string]string{
+	"GenerateAPIKey": "APIKeyPair",
+	"GetApiKeys":     "ApiKey[]",
+}
+
+type generator struct {
+	buf        bytes.Buffer
+	interfaces map[reflect.Type]bool
+}
+
+func main() {
+	out := flag.String("o", "", "output file, stdout if empty")
+	flag.Parse()
+
+	b, err := generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(b)
+		return
+	}
+	if err := os.WriteFile(*out, b, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate() ([]byte, error) {
+	g := &generator{interfaces: map[reflect.Type]bool{}}
+	g.buf.WriteString(header)
+
+	var txTypes strings.Builder
+	for _, name := range core.Methods() {
+		if _, ok := core.TxType(name); ok {
+			fmt.Fprintf(&txTypes, "    %s: number;\n", core.LowerCamelCase(strings.TrimPrefix(name, "Sign")))
+		}
+	}
+
+	var lighter, client strings.Builder
+	if err := g.writeMethod(&lighter, "GenerateAPIKey", ""); err != nil {
+		return nil, err
+	}
+	if err := g.writeMethod(&lighter, "CreateClient", "LighterClient"); err != nil {
+		return nil, err
+	}
+	for _, name := range core.ClientMethods() {
+		if err := g.writeMethod(&client, name, ""); err != nil {
+			return nil, err
+		}
+	}
+
+	g.buf.WriteString("\n/** A client returned by createClient, with its own api keys, independent of the other clients. */\n")
+	g.buf.WriteString("export interface LighterClient {\n")
+	g.buf.WriteString("  readonly handle: number;\n")
+	g.buf.WriteString(client.String())
+	g.buf.WriteString("  /** Runs a method by name, with its params as a JSON string. */\n")
+	g.buf.WriteString("  call(method: string, paramsJSON?: string): Promise<string>;\n")
+	g.buf.WriteString("  /** Releases the client, its methods reject afterwards. */\n")
+	g.buf.WriteString("  destroy(): Promise<void>;\n")
+	g.buf.WriteString("}\n")
+
+	g.buf.WriteString("\nexport interface Lighter {\n")
+	g.buf.WriteString("  readonly version: string;\n")
+	g.buf.WriteString("  /** The tx types of the tx infos returned by the sign functions, to send them with sendTx. */\n")
+	g.buf.WriteString("  readonly txTypes: {\n" + txTypes.String() + "  };\n")
+	g.buf.WriteString("  /** The codes of the errors, stable across versions, to compare with the code of a LighterError. */\n")
+	g.buf.WriteString("  readonly errorCodes: {\n" + errorCodes() + "  };\n")
+	g.buf.WriteString(lighter.String())
+	g.buf.WriteString("  /** Parses the tx info returned by the sign functions, with its integers as BigInt. */\n")
+	g.buf.WriteString("  parseTxInfo(txInfo: string): Promise<Record<string, unknown>>;\n")
+	g.buf.WriteString("}\n")
+	g.buf.WriteString("\ndeclare global {\n  var lighter: Lighter;\n}\n")
+	return g.buf.Bytes(), nil
+}
+
+// errorCodes returns the fields of lighter.errorCodes, sorted by code
+func errorCodes() string {
+	codes := core.ErrorCodes()
+	names := make([]string, 0, len(codes))
+	for name := range codes {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool { return codes[names[i]] < codes[names[j]] })
+
+	var fields strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&fields, "    readonly %s: %d;\n", name, codes[name])
+	}
+	return fields.String()
+}
+
+// writeMethod writes the declaration of a method, and the interface of its params.
+// The type of its result is given by resultType unless result is set.
+func (g *generator) writeMethod(methods *strings.Builder, name, result string) error {
+	params, err := core.NewParams(name)
+	if err != nil {
+		return err
+	}
+	paramsType := reflect.TypeOf(params).Elem()
+	g.writeInterface(paramsType, reflect.ValueOf(params).Elem())
+
+	optional := ""
+	if allOptional(paramsType, reflect.ValueOf(params).Elem()) {
+		optional = "?"
+	}
+	if result == "" {
+		if result, err = resultType(name); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(methods, "  %s(params%s: %s): Promise<%s>;\n", core.LowerCamelCase(name), optional, paramsType.Name(), result)
+	return nil
+}
+
+// resultType is the type the Promise of a method resolves with, see jsResult of the WASM module
+func resultType(name string) (string, error) {
+	kind, err := core.MethodResult(name)
+	if err != nil {
+		return "", err
+	}
+
+	switch kind {
+	case core.ResultNone:
+		return "void", nil
+	case core.ResultInteger:
+		return "bigint", nil
+	case core.ResultJSON:
+		t, ok := jsonResults[name]
+		if !ok {
+			return "", fmt.Errorf("no TypeScript type for the result of %s", name)
+		}
+		return t, nil
+	default:
+		return "string", nil
+	}
+}
+
+// writeInterface writes the interface of a params struct, after the ones of the structs it uses
+func (g *generator) writeInterface(t reflect.Type, defaults reflect.Value) {
+	if g.interfaces[t] {
+		return
+	}
+	g.interfaces[t] = true
+
+	var fields strings.Builder
+	g.writeFields(&fields, t, defaults)
+	fmt.Fprintf(&g.buf, "\nexport interface %s {\n%s}\n", t.Name(), fields.String())
+}
+
+func (g *generator) writeFields(fields *strings.Builder, t reflect.Type, defaults reflect.Value) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			g.writeFields(fields, f.Type, defaults.Field(i))
+			continue
+		}
+
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		optional := ""
+		if isOptional(f, defaults.Field(i)) {
+			optional = "?"
+		}
+		fmt.Fprintf(fields, "  %s%s: %s;\n", name, optional, g.tsType(f.Type))
+	}
+}
+
+func (g *generator) tsType(t reflect.Type) string {
+	switch {
+	case t == reflect.TypeOf(types.USDC(0)):
+		return "USDC"
+	case t.Kind() == reflect.Int64:
+		return "Int64"
+	case t.Kind() >= reflect.Uint8 && t.Kind() <= reflect.Uint32:
+		return "number"
+	case t.Kind() == reflect.String:
+		return "string"
+	case t.Kind() == reflect.Bool:
+		return "boolean"
+	case t.Kind() == reflect.Slice:
+		return g.tsType(t.Elem()) + "[]"
+	case t.Kind() == reflect.Struct:
+		defaults := reflect.New(t).Elem()
+		if d, ok := elemDefaults[t]; ok {
+			defaults = reflect.ValueOf(d)
+		}
+		g.writeInterface(t, defaults)
+		return t.Name()
+	default:
+		panic(fmt.Sprintf("no TypeScript type for %s", t))
+	}
+}
+
+// isOptional checks if a field can be left out: it has a non-zero default, or is tagged omitempty
+func isOptional(f reflect.StructField, defaultValue reflect.Value) bool {
+	_, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+	return !defaultValue.IsZero() || opts == "omitempty"
+}
+
+func allOptional(t reflect.Type, defaults reflect.Value) bool {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Anonymous {
+			if !allOptional(f.Type, defaults.Field(i)) {
+				return false
+			}
+			continue
+		}
+		if !isOptional(f, defaults.Field(i)) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,22 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestUpToDate checks the checked-in definitions match the params of core, regenerate them with `just dts`
+func TestUpToDate(t *testing.T) {
+	generated, err := generate()
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkedIn, err := os.ReadFile("../lighter.d.ts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(generated, checkedIn) {
+		t.Fatal("sharedlib/lighter.d.ts is out of date, run `just dts`")
+	}
+}
@@ -0,0 +1,336 @@
+// Code generated by gendts; DO NOT EDIT.
+
+/** An integer, as a number if it's a safe integer, a BigInt or a decimal string. */
+export type Int64 = number | bigint | string;
+
+/** A USDC amount, as a decimal string like "12.5". Numbers are rejected, as they're ambiguous. */
+export type USDC = string;
+
+/** The Error the Promises reject with, method being the function which failed, and code one of lighter.errorCodes. */
+export interface LighterError extends Error {
+  name: "LighterError";
+  method: string;
+  code: number;
+}
+
+export interface APIKeyPair {
+  privateKey: string;
+  publicKey: string;
+}
+
+/** An api key registered on Lighter, as returned by getApiKeys. */
+export interface ApiKey {
+  account_index: bigint;
+  api_key_index: bigint;
+  nonce: bigint;
+  public_key: string;
+}
+
+export interface GenerateAPIKeyParams {
+  seed?: string;
+}
+
+export interface CreateClientParams {
+  url?: string;
+  privateKey: string;
+  chainId: number;
+  apiKeyIndex: number;
+  accountIndex: Int64;
+}
+
+export interface AddAPIKeyParams {
+  privateKey: string;
+  apiKeyIndex: number;
+}
+
+export interface CheckClientParams {
+  apiKeyIndex: number;
+  accountIndex: Int64;
+}
+
+export interface ConfigureClientOrderIndexParams {
+  tagBits: number;
+  tag: Int64;
+  lastClientOrderIndex?: Int64;
+}
+
+export interface CreateAuthTokenParams {
+  deadline?: Int64;
+}
+
+export interface GetApiKeysParams {
+  accountIndex: Int64;
+  apiKeyIndex: number;
+}
+
+export interface GetNextNonceParams {
+}
+
+export interface GetTransferFeeInfoParams {
+  toAccountIndex: Int64;
+}
+
+export interface SendTxParams {
+  txType: number;
+  txInfo: string;
+}
+
+export interface SetFatFingerProtectionParams {
+  enabled: boolean;
+}
+
+export interface SetMaxTransferFeeParams {
+  maxFee: USDC;
+}
+
+export interface SharesParams {
+  publicPoolIndex: Int64;
+  shareAmount: Int64;
+  nonce?: Int64;
+}
+
+export interface CancelAllOrdersParams {
+  timeInForce: number;
+  time: Int64;
+  nonce?: Int64;
+}
+
+export interface CancelOrderParams {
+  marketIndex: number;
+  index: Int64;
+  nonce?: Int64;
+}
+
+export interface ChangePubKeyParams {
+  pubKey: string;
+  nonce?: Int64;
+}
+
+export interface OrderParams {
+  marketIndex: number;
+  clientOrderIndex: Int64;
+  baseAmount: Int64;
+  price: number;
+  isAsk: number;
+  type: number;
+  timeInForce: number;
+  reduceOnly?: number;
+  triggerPrice?: number;
+  orderExpiry?: Int64;
+}
+
+export interface CreateGroupedOrdersParams {
+  groupingType: number;
+  orders: OrderParams[];
+  nonce?: Int64;
+}
+
+export interface CreateOrderParams {
+  marketIndex: number;
+  clientOrderIndex: Int64;
+  baseAmount: Int64;
+  price: number;
+  isAsk: number;
+  type: number;
+  timeInForce: number;
+  reduceOnly?: number;
+  triggerPrice?: number;
+  orderExpiry?: Int64;
+  nonce?: Int64;
+}
+
+export interface CreatePublicPoolParams {
+  operatorFee: Int64;
+  initialTotalShares: Int64;
+  minOperatorShareRate: Int64;
+  nonce?: Int64;
+}
+
+export interface CreateSubAccountParams {
+  nonce?: Int64;
+}
+
+export interface ModifyOrderParams {
+  marketIndex: number;
+  index: Int64;
+  baseAmount: Int64;
+  price: number;
+  triggerPrice: number;
+  nonce?: Int64;
+}
+
+export interface TransferParams {
+  toAccountIndex: Int64;
+  usdcAmount: USDC;
+  fee?: USDC;
+  memo: string;
+  nonce?: Int64;
+}
+
+export interface UpdateLeverageParams {
+  marketIndex: number;
+  initialMarginFraction: number;
+  marginMode: number;
+  nonce?: Int64;
+}
+
+export interface UpdateMarginParams {
+  marketIndex: number;
+  usdcAmount: USDC;
+  direction: number;
+  nonce?: Int64;
+}
+
+export interface UpdatePublicPoolParams {
+  publicPoolIndex: Int64;
+  status: number;
+  operatorFee: Int64;
+  minOperatorShareRate: Int64;
+  nonce?: Int64;
+}
+
+export interface WithdrawParams {
+  usdcAmount: USDC;
+  nonce?: Int64;
+}
+
+export interface SwitchAPIKeyParams {
+  apiKeyIndex: number;
+}
+
+/** A client returned by createClient, with its own api keys, independent of the other clients. */
+export interface LighterClient {
+  readonly handle: number;
+  addAPIKey(params: AddAPIKeyParams): Promise<void>;
+  checkClient(params: CheckClientParams): Promise<void>;
+  configureClientOrderIndex(params: ConfigureClientOrderIndexParams): Promise<void>;
+  createAuthToken(params?: CreateAuthTokenParams): Promise<string>;
+  getApiKeys(params: GetApiKeysParams): Promise<ApiKey[]>;
+  getNextNonce(params?: GetNextNonceParams): Promise<bigint>;
+  getTransferFeeInfo(params: GetTransferFeeInfoParams): Promise<string>;
+  sendTx(params: SendTxParams): Promise<string>;
+  setFatFingerProtection(params: SetFatFingerProtectionParams): Promise<void>;
+  setMaxTransferFee(params: SetMaxTransferFeeParams): Promise<void>;
+  signBurnShares(params: SharesParams): Promise<string>;
+  signCancelAllOrders(params: CancelAllOrdersParams): Promise<string>;
+  signCancelOrder(params: CancelOrderParams): Promise<string>;
+  signChangePubKey(params: ChangePubKeyParams): Promise<string>;
+  signCreateGroupedOrders(params: CreateGroupedOrdersParams): Promise<string>;
+  signCreateOrder(params: CreateOrderParams): Promise<string>;
+  signCreatePublicPool(params: CreatePublicPoolParams): Promise<string>;
+  signCreateSubAccount(params?: CreateSubAccountParams): Promise<string>;
+  signMintShares(params: SharesParams): Promise<string>;
+  signModifyOrder(params: ModifyOrderParams): Promise<string>;
+  signTransfer(params: TransferParams): Promise<string>;
+  signUpdateLeverage(params: UpdateLeverageParams): Promise<string>;
+  signUpdateMargin(params: UpdateMarginParams): Promise<string>;
+  signUpdatePublicPool(params: UpdatePublicPoolParams): Promise<string>;
+  signWithdraw(params: WithdrawParams): Promise<string>;
+  switchAPIKey(params: SwitchAPIKeyParams): Promise<void>;
+  /** Runs a method by name, with its params as a JSON string. */
+  call(method: string, paramsJSON?: string): Promise<string>;
+  /** Releases the client, its methods reject afterwards. */
+  destroy(): Promise<void>;
+}
+
+export interface Lighter {
+  readonly version: string;
+  /** The tx types of the tx infos returned by the sign functions, to send them with sendTx. */
+  readonly txTypes: {
+    burnShares: number;
+    cancelAllOrders: number;
+    cancelOrder: number;
+    changePubKey: number;
+    createGroupedOrders: number;
+    createOrder: number;
+    createPublicPool: number;
+    createSubAccount: number;
+    mintShares: number;
+    modifyOrder: number;
+    transfer: number;
+    updateLeverage: number;
+    updateMargin: number;
+    updatePublicPool: number;
+    withdraw: number;
+  };
+  /** The codes of the errors, stable across versions, to compare with the code of a LighterError. */
+  readonly errorCodes: {
+    readonly None: 0;
+    readonly Unknown: 1;
+    readonly InvalidParams: 2;
+    readonly NotInitialized: 3;
+    readonly InvalidKey: 4;
+    readonly Nonce: 5;
+    readonly Network: 6;
+    readonly Rejected: 7;
+    readonly Unavailable: 8;
+    readonly AccountIndexTooLow: 1001;
+    readonly AccountIndexTooHigh: 1002;
+    readonly NonceTooLow: 1003;
+    readonly InvalidCancelAllTimeInForce: 1004;
+    readonly OrderReduceOnlyInvalid: 1005;
+    readonly OrderTriggerPriceInvalid: 1006;
+    readonly OrderExpiryInvalid: 1007;
+    readonly ExpiredAtInvalid: 1008;
+    readonly CancelAllTimeIsNotInRange: 1009;
+    readonly CancelAllTimeIsNotNil: 1010;
+    readonly PubKeyInvalid: 1011;
+    readonly ToAccountIndexTooLow: 1012;
+    readonly ToAccountIndexTooHigh: 1013;
+    readonly FromAccountIndexTooLow: 1014;
+    readonly FromAccountIndexTooHigh: 1015;
+    readonly ApiKeyIndexTooLow: 1016;
+    readonly ApiKeyIndexTooHigh: 1017;
+    readonly PublicPoolIndexTooLow: 1018;
+    readonly PublicPoolIndexTooHigh: 1019;
+    readonly InvalidPoolOperatorFee: 1020;
+    readonly InvalidPoolStatus: 1021;
+    readonly PoolInitialTotalSharesTooLow: 1022;
+    readonly PoolInitialTotalSharesTooHigh: 1023;
+    readonly PoolMinOperatorShareRateTooLow: 1024;
+    readonly PoolMinOperatorShareRateTooHigh: 1025;
+    readonly PoolMintShareAmountTooLow: 1026;
+    readonly PoolMintShareAmountTooHigh: 1027;
+    readonly PoolBurnShareAmountTooLow: 1028;
+    readonly PoolBurnShareAmountTooHigh: 1029;
+    readonly WithdrawalAmountTooLow: 1030;
+    readonly WithdrawalAmountTooHigh: 1031;
+    readonly TransferAmountTooLow: 1032;
+    readonly TransferAmountTooHigh: 1033;
+    readonly MarketIndexTooLow: 1034;
+    readonly MarketIndexTooHigh: 1035;
+    readonly MarketIndexMismatch: 1036;
+    readonly InitialMarginFractionTooLow: 1037;
+    readonly InitialMarginFractionTooHigh: 1038;
+    readonly ClientOrderIndexTooLow: 1039;
+    readonly ClientOrderIndexTooHigh: 1040;
+    readonly ClientOrderIndexNotNil: 1041;
+    readonly OrderIndexTooLow: 1042;
+    readonly OrderIndexTooHigh: 1043;
+    readonly BaseAmountTooLow: 1044;
+    readonly BaseAmountTooHigh: 1045;
+    readonly BaseAmountsNotEqual: 1046;
+    readonly BaseAmountNotNil: 1047;
+    readonly PriceTooLow: 1048;
+    readonly PriceTooHigh: 1049;
+    readonly IsAskInvalid: 1050;
+    readonly OrderTypeInvalid: 1051;
+    readonly OrderTimeInForceInvalid: 1052;
+    readonly GroupingTypeInvalid: 1053;
+    readonly OrderGroupSizeInvalid: 1054;
+    readonly InvalidSignature: 1055;
+    readonly InvalidMarginMode: 1056;
+    readonly CancelModeInvalid: 1057;
+    readonly InvalidUpdateMarginDirection: 1058;
+    readonly TransferFeeNegative: 1059;
+    readonly TransferFeeTooHigh: 1060;
+  };
+  generateAPIKey(params?: GenerateAPIKeyParams): Promise<APIKeyPair>;
+  createClient(params: CreateClientParams): Promise<LighterClient>;
+  /** Parses the tx info returned by the sign functions, with its integers as BigInt. */
+  parseTxInfo(txInfo: string): Promise<Record<string, unknown>>;
+}
+
+declare global {
+  var lighter: Lighter;
+}
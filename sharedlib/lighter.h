@@ -0,0 +1,147 @@
+/* Code generated by cmd/cgo; DO NOT EDIT. */
+
+/* package command-line-arguments */
+
+
+#line 1 "cgo-builtin-export-prolog"
+
+#include <stddef.h>
+
+#ifndef GO_CGO_EXPORT_PROLOGUE_H
+#define GO_CGO_EXPORT_PROLOGUE_H
+
+#ifndef GO_CGO_GOSTRING_TYPEDEF
+typedef struct { const char *p; ptrdiff_t n; } _GoString_;
+extern size_t _GoStringLen(_GoString_ s);
+extern const char *_GoStringPtr(_GoString_ s);
+#endif
+
+#endif
+
+/* Start of preamble from import "C" comments.  */
+
+
+#line 13 "sharedlib.go"
+
+#include <stdlib.h>
+typedef struct {
+	char* err;
+	int errCode;
+} Err;
+
+typedef struct {
+	char* str;
+	char* err;
+	int errCode;
+} StrOrErr;
+
+typedef struct {
+	long long handle;
+	char* err;
+	int errCode;
+} ClientOrErr;
+
+typedef struct {
+	char* privateKey;
+	char* publicKey;
+	char* err;
+	int errCode;
+} ApiKeyResponse;
+
+#line 1 "cgo-generated-wrapper"
+
+
+/* End of preamble from import "C" comments.  */
+
+
+/* Start of boilerplate cgo prologue.  */
+#line 1 "cgo-gcc-export-header-prolog"
+
+#ifndef GO_CGO_PROLOGUE_H
+#define GO_CGO_PROLOGUE_H
+
+typedef signed char GoInt8;
+typedef unsigned char GoUint8;
+typedef short GoInt16;
+typedef unsigned short GoUint16;
+typedef int GoInt32;
+typedef unsigned int GoUint32;
+typedef long long GoInt64;
+typedef unsigned long long GoUint64;
+typedef GoInt64 GoInt;
+typedef GoUint64 GoUint;
+typedef size_t GoUintptr;
+typedef float GoFloat32;
+typedef double GoFloat64;
+#ifdef _MSC_VER
+#if !defined(__cplusplus) || _MSVC_LANG <= 201402L
+#include <complex.h>
+typedef _Fcomplex GoComplex64;
+typedef _Dcomplex GoComplex128;
+#else
+#include <complex>
+typedef std::complex<float> GoComplex64;
+typedef std::complex<double> GoComplex128;
+#endif
+#else
+typedef float _Complex GoComplex64;
+typedef double _Complex GoComplex128;
+#endif
+
+/*
+  static assertion to make sure the file is being used on architecture
+  at least with matching size of GoInt.
+*/
+typedef char _check_for_64_bit_pointer_matching_GoInt[sizeof(void*)==64/8 ? 1:-1];
+
+#ifndef GO_CGO_GOSTRING_TYPEDEF
+typedef _GoString_ GoString;
+#endif
+typedef void *GoMap;
+typedef void *GoChan;
+typedef struct { void *t; void *v; } GoInterface;
+typedef struct { void *data; GoInt len; GoInt cap; } GoSlice;
+
+#endif
+
+/* End of boilerplate cgo prologue.  */
+
+#ifdef __cplusplus
+extern "C" {
+#endif
+
+extern char* LighterVersion(void);
+extern char* LighterErrorCodeName(int code);
+extern void FreeErr(Err r);
+extern void FreeClientOrErr(ClientOrErr r);
+extern void FreeStrOrErr(StrOrErr r);
+extern void FreeApiKeyResponse(ApiKeyResponse r);
+extern ApiKeyResponse GenerateAPIKey(char* cSeed);
+extern ClientOrErr CreateClient(char* cUrl, char* cPrivateKey, int cChainId, int cApiKeyIndex, long long int cAccountIndex);
+extern Err AddAPIKey(long long int cHandle, char* cPrivateKey, int cApiKeyIndex);
+extern Err DestroyClient(long long int cHandle);
+extern Err ConfigureClientOrderIndex(long long int cHandle, int cTagBits, long long int cTag, long long int cLastClientOrderIndex);
+extern Err SetMaxTransferFee(long long int cHandle, long long int cMaxFee);
+extern Err CheckClient(long long int cHandle, int cApiKeyIndex, long long int cAccountIndex);
+extern StrOrErr SignChangePubKey(long long int cHandle, char* cPubKey, long long int cNonce);
+extern StrOrErr SignCreateOrder(long long int cHandle, int cMarketIndex, long long int cClientOrderIndex, long long int cBaseAmount, int cPrice, int cIsAsk, int cOrderType, int cTimeInForce, int cReduceOnly, int cTriggerPrice, long long int cOrderExpiry, long long int cNonce);
+extern StrOrErr SignCreateGroupedOrders(long long int cHandle, int cGroupingType, char* cOrdersJSON, long long int cNonce);
+extern StrOrErr SignCancelOrder(long long int cHandle, int cMarketIndex, long long int cOrderIndex, long long int cNonce);
+extern StrOrErr SignWithdraw(long long int cHandle, long long int cUSDCAmount, long long int cNonce);
+extern StrOrErr SignCreateSubAccount(long long int cHandle, long long int cNonce);
+extern StrOrErr SignCancelAllOrders(long long int cHandle, int cTimeInForce, long long int cTime, long long int cNonce);
+extern StrOrErr SignModifyOrder(long long int cHandle, int cMarketIndex, long long int cIndex, long long int cBaseAmount, long long int cPrice, long long int cTriggerPrice, long long int cNonce);
+extern StrOrErr SignTransfer(long long int cHandle, long long int cToAccountIndex, long long int cUSDCAmount, long long int cFee, char* cMemo, long long int cNonce);
+extern StrOrErr SignCreatePublicPool(long long int cHandle, long long int cOperatorFee, long long int cInitialTotalShares, long long int cMinOperatorShareRate, long long int cNonce);
+extern StrOrErr SignUpdatePublicPool(long long int cHandle, long long int cPublicPoolIndex, int cStatus, long long int cOperatorFee, long long int cMinOperatorShareRate, long long int cNonce);
+extern StrOrErr SignMintShares(long long int cHandle, long long int cPublicPoolIndex, long long int cShareAmount, long long int cNonce);
+extern StrOrErr SignBurnShares(long long int cHandle, long long int cPublicPoolIndex, long long int cShareAmount, long long int cNonce);
+extern StrOrErr SignUpdateLeverage(long long int cHandle, int cMarketIndex, int cInitialMarginFraction, int cMarginMode, long long int cNonce);
+extern StrOrErr CreateAuthToken(long long int cHandle, long long int cDeadline);
+extern Err SwitchAPIKey(long long int cHandle, int cApiKeyIndex);
+extern StrOrErr SignUpdateMargin(long long int cHandle, int cMarketIndex, long long int cUSDCAmount, int cDirection, long long int cNonce);
+extern StrOrErr Call(long long int cHandle, char* cMethod, char* cParamsJSON);
+
+#ifdef __cplusplus
+}
+#endif
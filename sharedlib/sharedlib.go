@@ -1,1018 +1,605 @@
 package main
 
 import (
-	"encoding/json"
 	"fmt"
-	"strings"
-	"time"
+	"sync"
+	"sync/atomic"
+	"unsafe"
 
-	"github.com/elliottech/lighter-go/client"
+	"github.com/elliottech/lighter-go/core"
 	"github.com/elliottech/lighter-go/types"
-	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
-	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 /*
 #include <stdlib.h>
+typedef struct {
+	char* err;
+	int errCode;
+} Err;
+
 typedef struct {
 	char* str;
 	char* err;
+	int errCode;
 } StrOrErr;
 
+typedef struct {
+	long long handle;
+	char* err;
+	int errCode;
+} ClientOrErr;
+
 typedef struct {
 	char* privateKey;
 	char* publicKey;
 	char* err;
+	int errCode;
 } ApiKeyResponse;
 */
 import "C"
 
-var (
-	txClient        *client.TxClient
-	backupTxClients map[uint8]*client.TxClient
-)
+// clients holds a session per handle returned by CreateClient, so one process can sign for many accounts.
+// The exported functions only convert their C arguments & results, defaults and validation are implemented
+// by the core package, shared with the WASM & mobile bindings.
+var clients = core.NewRegistry()
 
-func wrapErr(err error) (ret *C.char) {
-	return C.CString(fmt.Sprintf("%v", err))
-}
+// liveCStrings counts the strings returned to C which weren't freed yet, to catch leaks in the ABI tests
+var liveCStrings atomic.Int64
 
-//export GenerateAPIKey
-func GenerateAPIKey(cSeed *C.char) (ret C.ApiKeyResponse) {
-	var err error
-	var privateKeyStr string
-	var publicKeyStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.ApiKeyResponse{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.ApiKeyResponse{
-				privateKey: C.CString(privateKeyStr),
-				publicKey:  C.CString(publicKeyStr),
-			}
-		}
-	}()
+// cString allocates the strings returned to C, which have to be freed with one of the Free functions
+func cString(s string) *C.char {
+	liveCStrings.Add(1)
+	return C.CString(s)
+}
 
-	seed := C.GoString(cSeed)
-	seedP := &seed
-	if seed == "" {
-		seedP = nil
+func freeCString(p *C.char) {
+	if p != nil {
+		liveCStrings.Add(-1)
+		C.free(unsafe.Pointer(p))
 	}
-
-	key := curve.SampleScalar(seedP)
-
-	publicKeyStr = hexutil.Encode(schnorr.SchnorrPkFromSk(key).ToLittleEndianBytes())
-	privateKeyStr = hexutil.Encode(key.ToLittleEndianBytes())
-
-	return
 }
 
-//export CreateClient
-func CreateClient(cUrl *C.char, cPrivateKey *C.char, cChainId C.int, cApiKeyIndex C.int, cAccountIndex C.longlong) (ret *C.char) {
-	var err error
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = wrapErr(err)
-		}
-	}()
-
-	url := C.GoString(cUrl)
-	privateKey := C.GoString(cPrivateKey)
-	chainId := uint32(cChainId)
-	apiKeyIndex := uint8(cApiKeyIndex)
-	accountIndex := int64(cAccountIndex)
-
-	if accountIndex <= 0 {
-		err = fmt.Errorf("invalid account index")
-		return
-	}
+// wrapErr returns the message & the core.ErrorCode of an error, which every result returned to C holds
+func wrapErr(err error) C.Err {
+	return C.Err{err: cString(fmt.Sprintf("%v", err)), errCode: C.int(core.ErrorCodeOf(err))}
+}
 
-	httpClient := client.NewHTTPClient(url)
-	txClient, err = client.NewTxClient(httpClient, privateKey, accountIndex, apiKeyIndex, chainId)
+func errOrNil(err error) C.Err {
 	if err != nil {
-		err = fmt.Errorf("error occurred when creating TxClient. err: %v", err)
-		return
-	}
-	if backupTxClients == nil {
-		backupTxClients = make(map[uint8]*client.TxClient)
+		return wrapErr(err)
 	}
-	backupTxClients[apiKeyIndex] = txClient
-
-	return nil
+	return C.Err{}
 }
 
-//export CheckClient
-func CheckClient(cApiKeyIndex C.int, cAccountIndex C.longlong) (ret *C.char) {
-	var err error
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = wrapErr(err)
-		}
-	}()
-
-	apiKeyIndex := uint8(cApiKeyIndex)
-	accountIndex := int64(cAccountIndex)
-
-	client, ok := backupTxClients[apiKeyIndex]
-	if !ok {
-		err = fmt.Errorf("api key not registered")
-		return
-	}
-
-	if client.GetApiKeyIndex() != apiKeyIndex {
-		err = fmt.Errorf("apiKeyIndex does not match. expected %v but got %v", client.GetApiKeyIndex(), apiKeyIndex)
-		return
-	}
-	if client.GetAccountIndex() != accountIndex {
-		err = fmt.Errorf("accountIndex does not match. expected %v but got %v", client.GetAccountIndex(), accountIndex)
-		return
-	}
-
-	// check that the API key registered on Lighter matches this one
-	key, err := client.HTTP().GetApiKey(accountIndex, apiKeyIndex)
+func strOrErr(str string, err error) C.StrOrErr {
 	if err != nil {
-		err = fmt.Errorf("failed to get Api Keys. err: %v", err)
-		return
+		e := wrapErr(err)
+		return C.StrOrErr{err: e.err, errCode: e.errCode}
 	}
+	return C.StrOrErr{str: cString(str)}
+}
 
-	pubKeyBytes := client.GetKeyManager().PubKeyBytes()
-	pubKeyStr := hexutil.Encode(pubKeyBytes[:])
-	pubKeyStr = strings.Replace(pubKeyStr, "0x", "", 1)
+func clientOrErr(err error) C.ClientOrErr {
+	e := wrapErr(err)
+	return C.ClientOrErr{err: e.err, errCode: e.errCode}
+}
 
-	ak := key.ApiKeys[0]
-	if ak.PublicKey != pubKeyStr {
-		err = fmt.Errorf("private key does not match the one on Lighter. ownPubKey: %s response: %+v", pubKeyStr, ak)
-		return
+// recoverErr and recoverStrOrErr are deferred by the exported functions, so a panic never crosses the C boundary
+func recoverErr(ret *C.Err) {
+	if r := recover(); r != nil {
+		*ret = wrapErr(fmt.Errorf("%v", r))
 	}
-
-	return
 }
 
-//export SignChangePubKey
-func SignChangePubKey(cPubKey *C.char, cNonce C.longlong) (ret C.StrOrErr) {
-	// Note: The ChangePubKey TX needs to be signed by the API key that's being changed to as well.
-	//       Because of that, there's no reason to add the params for apiKeyIndex & accountIndex, because this
-	//       version of the SDK doesn't have support for multiple signers.
-	//       Even if it'd had, the flow would look something like this:
-	//       - first you select which client you're sending the TX from
-	//       - then we use the ApiKeyIndex & AccountIndex from that client
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+func recoverStrOrErr(ret *C.StrOrErr) {
+	if r := recover(); r != nil {
+		*ret = strOrErr("", fmt.Errorf("%v", r))
 	}
+}
 
-	nonce := int64(cNonce)
+var version = sync.OnceValue(func() *C.char {
+	return C.CString(core.Version)
+})
 
-	// handle PubKey
-	pubKeyStr := C.GoString(cPubKey)
-	pubKeyBytes, err := hexutil.Decode(pubKeyStr)
-	if err != nil {
-		return
-	}
-	if len(pubKeyBytes) != 40 {
-		err = fmt.Errorf("invalid pub key length. expected 40 but got %v", len(pubKeyBytes))
-		return
-	}
-	var pubKey [40]byte
-	copy(pubKey[:], pubKeyBytes)
+// LighterVersion returns the version of the library, whose major version changes when the C ABI breaks.
+// The string is owned by the library and must not be freed.
+//
+//export LighterVersion
+func LighterVersion() *C.char {
+	return version()
+}
 
-	txInfo := &types.ChangePubKeyReq{
-		PubKey: pubKey,
-	}
-	ops := &types.TransactOpts{}
-	if nonce != -1 {
-		ops.Nonce = &nonce
+var errCodeNames = sync.OnceValue(func() map[core.ErrorCode]*C.char {
+	names := make(map[core.ErrorCode]*C.char)
+	for name, code := range core.ErrorCodes() {
+		names[code] = C.CString(name)
 	}
+	return names
+})
 
-	tx, err := txClient.GetChangePubKeyTransaction(txInfo, ops)
-	if err != nil {
-		return
+// LighterErrorCodeName returns the name of the errCode of a result, like "NotInitialized" or "AccountIndexTooLow".
+// The codes are stable, so apps can react to errors without parsing their message. The string is owned by the library and must not be freed.
+//
+//export LighterErrorCodeName
+func LighterErrorCodeName(code C.int) *C.char {
+	if name, ok := errCodeNames()[core.ErrorCode(code)]; ok {
+		return name
 	}
+	return errCodeNames()[core.ErrorCodeUnknown]
+}
 
-	// === manually add MessageToSign to the response:
-	// - marshal the tx
-	// - unmarshal it into a generic map
-	// - add the new field
-	// - marshal it again
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
-	}
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return
-	}
+// FreeErr frees the error returned by the functions which only return an error
+//
+//export FreeErr
+func FreeErr(r C.Err) {
+	freeCString(r.err)
+}
 
-	txInfoStr = string(txInfoBytes)
-	return
+// FreeClientOrErr frees the error of a ClientOrErr returned by CreateClient
+//
+//export FreeClientOrErr
+func FreeClientOrErr(r C.ClientOrErr) {
+	freeCString(r.err)
 }
 
-//export SignCreateOrder
-func SignCreateOrder(cMarketIndex C.int, cClientOrderIndex C.longlong, cBaseAmount C.longlong, cPrice C.int, cIsAsk C.int, cOrderType C.int, cTimeInForce C.int, cReduceOnly C.int, cTriggerPrice C.int, cOrderExpiry C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+// FreeStrOrErr frees the strings of a StrOrErr returned by the library
+//
+//export FreeStrOrErr
+func FreeStrOrErr(r C.StrOrErr) {
+	freeCString(r.str)
+	freeCString(r.err)
+}
+
+// FreeApiKeyResponse frees the strings of an ApiKeyResponse returned by GenerateAPIKey
+//
+//export FreeApiKeyResponse
+func FreeApiKeyResponse(r C.ApiKeyResponse) {
+	freeCString(r.privateKey)
+	freeCString(r.publicKey)
+	freeCString(r.err)
+}
 
+//export GenerateAPIKey
+func GenerateAPIKey(cSeed *C.char) (ret C.ApiKeyResponse) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			e := wrapErr(fmt.Errorf("%v", r))
+			ret = C.ApiKeyResponse{err: e.err, errCode: e.errCode}
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	marketIndex := uint8(cMarketIndex)
-	clientOrderIndex := int64(cClientOrderIndex)
-	baseAmount := int64(cBaseAmount)
-	price := uint32(cPrice)
-	isAsk := uint8(cIsAsk)
-	orderType := uint8(cOrderType)
-	timeInForce := uint8(cTimeInForce)
-	reduceOnly := uint8(cReduceOnly)
-	triggerPrice := uint32(cTriggerPrice)
-	orderExpiry := int64(cOrderExpiry)
-	nonce := int64(cNonce)
-
-	if orderExpiry == -1 {
-		orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
-	}
-
-	txInfo := &types.CreateOrderTxReq{
-		MarketIndex:      marketIndex,
-		ClientOrderIndex: clientOrderIndex,
-		BaseAmount:       baseAmount,
-		Price:            price,
-		IsAsk:            isAsk,
-		Type:             orderType,
-		TimeInForce:      timeInForce,
-		ReduceOnly:       reduceOnly,
-		TriggerPrice:     triggerPrice,
-		OrderExpiry:      orderExpiry,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateOrderTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+	privateKey, publicKey := core.GenerateAPIKey(C.GoString(cSeed))
+	return C.ApiKeyResponse{
+		privateKey: cString(privateKey),
+		publicKey:  cString(publicKey),
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
 }
 
-//export SignCancelOrder
-func SignCancelOrder(cMarketIndex C.int, cOrderIndex C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
+// CreateClient creates a client for the api key & account, and returns the handle the other functions take.
+// The handle stays valid until DestroyClient is called with it.
+//
+//export CreateClient
+func CreateClient(cUrl *C.char, cPrivateKey *C.char, cChainId C.int, cApiKeyIndex C.int, cAccountIndex C.longlong) (ret C.ClientOrErr) {
 	defer func() {
 		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
+			ret = clientOrErr(fmt.Errorf("%v", r))
 		}
 	}()
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+	var params core.ParamChecker
+	chainId := params.Uint32("chain id", int64(cChainId))
+	apiKeyIndex := params.Uint8("api key index", int64(cApiKeyIndex))
+	if err := params.Err(); err != nil {
+		return clientOrErr(err)
 	}
-
-	marketIndex := uint8(cMarketIndex)
-	orderIndex := int64(cOrderIndex)
-	nonce := int64(cNonce)
-
-	txInfo := &types.CancelOrderTxReq{
-		MarketIndex: marketIndex,
-		Index:       orderIndex,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+	s := core.NewSession()
+	if err := s.CreateClient(C.GoString(cUrl), C.GoString(cPrivateKey), chainId, apiKeyIndex, int64(cAccountIndex)); err != nil {
+		return clientOrErr(err)
 	}
+	return C.ClientOrErr{handle: C.longlong(clients.Add(s))}
+}
 
-	tx, err := txClient.GetCancelOrderTransaction(txInfo, ops)
+// AddAPIKey adds another api key of the account to the client, which can then be selected with SwitchAPIKey
+//
+//export AddAPIKey
+func AddAPIKey(cHandle C.longlong, cPrivateKey *C.char, cApiKeyIndex C.int) (ret C.Err) {
+	defer recoverErr(&ret)
+
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return wrapErr(err)
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
+	apiKeyIndex, err := core.APIKeyIndex(int64(cApiKeyIndex))
 	if err != nil {
-		return
+		return wrapErr(err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return errOrNil(s.AddAPIKey(C.GoString(cPrivateKey), apiKeyIndex))
 }
 
-//export SignWithdraw
-func SignWithdraw(cUSDCAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
+// DestroyClient releases the client, its handle can't be used afterwards
+//
+//export DestroyClient
+func DestroyClient(cHandle C.longlong) (ret C.Err) {
+	defer recoverErr(&ret)
 
-	usdcAmount := uint64(cUSDCAmount)
-	nonce := int64(cNonce)
+	return errOrNil(clients.Remove(int64(cHandle)))
+}
 
-	txInfo := types.WithdrawTxReq{
-		USDCAmount: usdcAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+//export ConfigureClientOrderIndex
+func ConfigureClientOrderIndex(cHandle C.longlong, cTagBits C.int, cTag C.longlong, cLastClientOrderIndex C.longlong) (ret C.Err) {
+	defer recoverErr(&ret)
 
-	tx, err := txClient.GetWithdrawTransaction(&txInfo, ops)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return wrapErr(err)
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+	var params core.ParamChecker
+	tagBits := params.Uint8("tag bits", int64(cTagBits))
+	if err := params.Err(); err != nil {
+		return wrapErr(err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return errOrNil(s.ConfigureClientOrderIndex(tagBits, int64(cTag), int64(cLastClientOrderIndex)))
 }
 
-//export SignCreateSubAccount
-func SignCreateSubAccount(cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
+// SetMaxTransferFee sets the highest fee, with 6 decimals, accepted by SignTransfer when the fee is -1. It's 10 USDC by default.
+//
+//export SetMaxTransferFee
+func SetMaxTransferFee(cHandle C.longlong, cMaxFee C.longlong) (ret C.Err) {
+	defer recoverErr(&ret)
 
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return wrapErr(err)
 	}
+	return errOrNil(s.SetMaxTransferFee(types.USDC(cMaxFee)))
+}
 
-	nonce := int64(cNonce)
-
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+//export CheckClient
+func CheckClient(cHandle C.longlong, cApiKeyIndex C.int, cAccountIndex C.longlong) (ret C.Err) {
+	defer recoverErr(&ret)
 
-	tx, err := txClient.GetCreateSubAccountTransaction(ops)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return wrapErr(err)
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
+	apiKeyIndex, err := core.APIKeyIndex(int64(cApiKeyIndex))
 	if err != nil {
-		return
+		return wrapErr(err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return errOrNil(s.CheckClient(apiKeyIndex, int64(cAccountIndex)))
 }
 
-//export SignCancelAllOrders
-func SignCancelAllOrders(cTimeInForce C.int, cTime C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
+//export SignChangePubKey
+func SignChangePubKey(cHandle C.longlong, cPubKey *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	// Note: The ChangePubKey TX needs to be signed by the API key that's being changed to as well.
+	//       The ApiKeyIndex & AccountIndex are the ones of the current api key of the client.
+	defer recoverStrOrErr(&ret)
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
 	}
+	return strOrErr(s.SignChangePubKey(core.ChangePubKeyParams{
+		PubKey: C.GoString(cPubKey),
+		Nonce:  int64(cNonce),
+	}))
+}
 
-	timeInForce := uint8(cTimeInForce)
-	t := int64(cTime)
-	nonce := int64(cNonce)
+// SignCreateOrder takes price and triggerPrice as C ints, so prices above 2147483647 have to be signed with Call
+//
+//export SignCreateOrder
+func SignCreateOrder(cHandle C.longlong, cMarketIndex C.int, cClientOrderIndex C.longlong, cBaseAmount C.longlong, cPrice C.int, cIsAsk C.int, cOrderType C.int, cTimeInForce C.int, cReduceOnly C.int, cTriggerPrice C.int, cOrderExpiry C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
+
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
+	}
+	var params core.ParamChecker
+	p := core.CreateOrderParams{
+		OrderParams: core.OrderParams{
+			MarketIndex:      params.Uint8("market index", int64(cMarketIndex)),
+			ClientOrderIndex: int64(cClientOrderIndex),
+			BaseAmount:       int64(cBaseAmount),
+			Price:            params.Uint32("price", int64(cPrice)),
+			IsAsk:            params.Uint8("is ask", int64(cIsAsk)),
+			Type:             params.Uint8("order type", int64(cOrderType)),
+			TimeInForce:      params.Uint8("time in force", int64(cTimeInForce)),
+			ReduceOnly:       params.Uint8("reduce only", int64(cReduceOnly)),
+			TriggerPrice:     params.Uint32("trigger price", int64(cTriggerPrice)),
+			OrderExpiry:      int64(cOrderExpiry),
+		},
+		Nonce: int64(cNonce),
+	}
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
+	}
+	return strOrErr(s.SignCreateOrder(p))
+}
 
-	txInfo := &types.CancelAllOrdersTxReq{
-		TimeInForce: timeInForce,
-		Time:        t,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+// SignCreateGroupedOrders takes the orders as a JSON array of objects with the fields of SignCreateOrder,
+// e.g. [{"marketIndex":0,"clientOrderIndex":0,"baseAmount":1000,"price":300000,"isAsk":0,"type":0,"timeInForce":1}].
+// A missing orderExpiry is -1, the default expiry of 28 days.
+//
+//export SignCreateGroupedOrders
+func SignCreateGroupedOrders(cHandle C.longlong, cGroupingType C.int, cOrdersJSON *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	tx, err := txClient.GetCancelAllOrdersTransaction(txInfo, ops)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
+	var params core.ParamChecker
+	groupingType := params.Uint8("grouping type", int64(cGroupingType))
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
+	}
+	orders, err := core.ParseOrders(C.GoString(cOrdersJSON))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignCreateGroupedOrders(core.CreateGroupedOrdersParams{
+		GroupingType: groupingType,
+		Orders:       orders,
+		Nonce:        int64(cNonce),
+	}))
 }
 
-//export SignModifyOrder
-func SignModifyOrder(cMarketIndex C.int, cIndex C.longlong, cBaseAmount C.longlong, cPrice C.longlong, cTriggerPrice C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
+//export SignCancelOrder
+func SignCancelOrder(cHandle C.longlong, cMarketIndex C.int, cOrderIndex C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
 	}
-
-	marketIndex := uint8(cMarketIndex)
-	index := int64(cIndex)
-	baseAmount := int64(cBaseAmount)
-	price := uint32(cPrice)
-	triggerPrice := uint32(cTriggerPrice)
-	nonce := int64(cNonce)
-
-	txInfo := &types.ModifyOrderTxReq{
-		MarketIndex:  marketIndex,
-		Index:        index,
-		BaseAmount:   baseAmount,
-		Price:        price,
-		TriggerPrice: triggerPrice,
+	var params core.ParamChecker
+	p := core.CancelOrderParams{
+		MarketIndex: params.Uint8("market index", int64(cMarketIndex)),
+		Index:       int64(cOrderIndex),
+		Nonce:       int64(cNonce),
 	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
 	}
+	return strOrErr(s.SignCancelOrder(p))
+}
 
-	tx, err := txClient.GetModifyOrderTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignWithdraw
+func SignWithdraw(cHandle C.longlong, cUSDCAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	txInfoBytes, err := json.Marshal(tx)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignWithdraw(core.WithdrawParams{
+		USDCAmount: types.USDC(cUSDCAmount),
+		Nonce:      int64(cNonce),
+	}))
 }
 
-//export SignTransfer
-func SignTransfer(cToAccountIndex C.longlong, cUSDCAmount C.longlong, cFee C.longlong, cMemo *C.char, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
+//export SignCreateSubAccount
+func SignCreateSubAccount(cHandle C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	toAccountIndex := int64(cToAccountIndex)
-	usdcAmount := int64(cUSDCAmount)
-	nonce := int64(cNonce)
-	fee := int64(cFee)
-	memo := [32]byte{}
-	memoStr := C.GoString(cMemo)
-	if len(memoStr) != 32 {
-		err = fmt.Errorf("memo expected to be 32 bytes long")
-		return
-	}
-	for i := 0; i < 32; i++ {
-		memo[i] = byte(memoStr[i])
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
 	}
+	return strOrErr(s.SignCreateSubAccount(core.CreateSubAccountParams{
+		Nonce: int64(cNonce),
+	}))
+}
 
-	txInfo := &types.TransferTxReq{
-		ToAccountIndex: toAccountIndex,
-		USDCAmount:     usdcAmount,
-		Fee:            fee,
-		Memo:           memo,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+//export SignCancelAllOrders
+func SignCancelAllOrders(cHandle C.longlong, cTimeInForce C.int, cTime C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	tx, err := txClient.GetTransferTransaction(txInfo, ops)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+	var params core.ParamChecker
+	p := core.CancelAllOrdersParams{
+		TimeInForce: params.Uint8("time in force", int64(cTimeInForce)),
+		Time:        int64(cTime),
+		Nonce:       int64(cNonce),
 	}
-
-	txInfoStr = string(txInfoBytes)
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignCancelAllOrders(p))
 }
 
-//export SignCreatePublicPool
-func SignCreatePublicPool(cOperatorFee C.longlong, cInitialTotalShares C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
+//export SignModifyOrder
+func SignModifyOrder(cHandle C.longlong, cMarketIndex C.int, cIndex C.longlong, cBaseAmount C.longlong, cPrice C.longlong, cTriggerPrice C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
 	}
-
-	operatorFee := int64(cOperatorFee)
-	initialTotalShares := int64(cInitialTotalShares)
-	minOperatorShareRate := int64(cMinOperatorShareRate)
-	nonce := int64(cNonce)
-
-	txInfo := &types.CreatePublicPoolTxReq{
-		OperatorFee:          operatorFee,
-		InitialTotalShares:   initialTotalShares,
-		MinOperatorShareRate: minOperatorShareRate,
+	var params core.ParamChecker
+	p := core.ModifyOrderParams{
+		MarketIndex:  params.Uint8("market index", int64(cMarketIndex)),
+		Index:        int64(cIndex),
+		BaseAmount:   int64(cBaseAmount),
+		Price:        params.Uint32("price", int64(cPrice)),
+		TriggerPrice: params.Uint32("trigger price", int64(cTriggerPrice)),
+		Nonce:        int64(cNonce),
 	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
 	}
+	return strOrErr(s.SignModifyOrder(p))
+}
 
-	tx, err := txClient.GetCreatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignTransfer
+func SignTransfer(cHandle C.longlong, cToAccountIndex C.longlong, cUSDCAmount C.longlong, cFee C.longlong, cMemo *C.char, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	txInfoBytes, err := json.Marshal(tx)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignTransfer(core.TransferParams{
+		ToAccountIndex: int64(cToAccountIndex),
+		USDCAmount:     types.USDC(cUSDCAmount),
+		Fee:            types.USDC(cFee),
+		Memo:           C.GoString(cMemo),
+		Nonce:          int64(cNonce),
+	}))
 }
 
-//export SignUpdatePublicPool
-func SignUpdatePublicPool(cPublicPoolIndex C.longlong, cStatus C.int, cOperatorFee C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	publicPoolIndex := int64(cPublicPoolIndex)
-	status := uint8(cStatus)
-	operatorFee := int64(cOperatorFee)
-	minOperatorShareRate := int64(cMinOperatorShareRate)
-	nonce := int64(cNonce)
-
-	txInfo := &types.UpdatePublicPoolTxReq{
-		PublicPoolIndex:      publicPoolIndex,
-		Status:               status,
-		OperatorFee:          operatorFee,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignCreatePublicPool
+func SignCreatePublicPool(cHandle C.longlong, cOperatorFee C.longlong, cInitialTotalShares C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	txInfoBytes, err := json.Marshal(tx)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignCreatePublicPool(core.CreatePublicPoolParams{
+		OperatorFee:          int64(cOperatorFee),
+		InitialTotalShares:   int64(cInitialTotalShares),
+		MinOperatorShareRate: int64(cMinOperatorShareRate),
+		Nonce:                int64(cNonce),
+	}))
 }
 
-//export SignMintShares
-func SignMintShares(cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
+//export SignUpdatePublicPool
+func SignUpdatePublicPool(cHandle C.longlong, cPublicPoolIndex C.longlong, cStatus C.int, cOperatorFee C.longlong, cMinOperatorShareRate C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
 	}
-
-	publicPoolIndex := int64(cPublicPoolIndex)
-	shareAmount := int64(cShareAmount)
-	nonce := int64(cNonce)
-
-	txInfo := &types.MintSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
+	var params core.ParamChecker
+	p := core.UpdatePublicPoolParams{
+		PublicPoolIndex:      int64(cPublicPoolIndex),
+		Status:               params.Uint8("status", int64(cStatus)),
+		OperatorFee:          int64(cOperatorFee),
+		MinOperatorShareRate: int64(cMinOperatorShareRate),
+		Nonce:                int64(cNonce),
 	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
 	}
+	return strOrErr(s.SignUpdatePublicPool(p))
+}
 
-	tx, err := txClient.GetMintSharesTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+//export SignMintShares
+func SignMintShares(cHandle C.longlong, cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	txInfoBytes, err := json.Marshal(tx)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignMintShares(core.SharesParams{
+		PublicPoolIndex: int64(cPublicPoolIndex),
+		ShareAmount:     int64(cShareAmount),
+		Nonce:           int64(cNonce),
+	}))
 }
 
 //export SignBurnShares
-func SignBurnShares(cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	publicPoolIndex := int64(cPublicPoolIndex)
-	shareAmount := int64(cShareAmount)
-	nonce := int64(cNonce)
-
-	txInfo := &types.BurnSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetBurnSharesTransaction(txInfo, ops)
-	if err != nil {
-		return
-	}
+func SignBurnShares(cHandle C.longlong, cPublicPoolIndex C.longlong, cShareAmount C.longlong, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	txInfoBytes, err := json.Marshal(tx)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	return strOrErr(s.SignBurnShares(core.SharesParams{
+		PublicPoolIndex: int64(cPublicPoolIndex),
+		ShareAmount:     int64(cShareAmount),
+		Nonce:           int64(cNonce),
+	}))
 }
 
 //export SignUpdateLeverage
-func SignUpdateLeverage(cMarketIndex C.int, cInitialMarginFraction C.int, cMarginMode C.int, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	marketIndex := uint8(cMarketIndex)
-	initialMarginFraction := uint16(cInitialMarginFraction)
-	nonce := int64(cNonce)
-	marginMode := uint8(cMarginMode)
-
-	txInfo := &types.UpdateLeverageTxReq{
-		MarketIndex:           marketIndex,
-		InitialMarginFraction: initialMarginFraction,
-		MarginMode:            uint8(marginMode),
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
+func SignUpdateLeverage(cHandle C.longlong, cMarketIndex C.int, cInitialMarginFraction C.int, cMarginMode C.int, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	tx, err := txClient.GetUpdateLeverageTransaction(txInfo, ops)
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return
+	var params core.ParamChecker
+	p := core.UpdateLeverageParams{
+		MarketIndex:           params.Uint8("market index", int64(cMarketIndex)),
+		InitialMarginFraction: params.Uint16("initial margin fraction", int64(cInitialMarginFraction)),
+		MarginMode:            params.Uint8("margin mode", int64(cMarginMode)),
+		Nonce:                 int64(cNonce),
 	}
-
-	txInfoStr = string(txInfoBytes)
-	return
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
+	}
+	return strOrErr(s.SignUpdateLeverage(p))
 }
 
 //export CreateAuthToken
-func CreateAuthToken(cDeadline C.longlong) (ret C.StrOrErr) {
-	var err error
-	var authToken string
-
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(authToken),
-			}
-		}
-	}()
-
-	if txClient == nil {
-		err = fmt.Errorf("client is not created, call CreateClient() first")
-		return
-	}
-
-	deadline := int64(cDeadline)
-	if deadline == 0 {
-		deadline = time.Now().Add(time.Hour * 7).Unix()
-	}
+func CreateAuthToken(cHandle C.longlong, cDeadline C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	authToken, err = txClient.GetAuthToken(time.Unix(deadline, 0))
+	s, err := clients.Get(int64(cHandle))
 	if err != nil {
-		return
+		return strOrErr("", err)
 	}
-
-	return
+	// a deadline of 0 creates a token valid for 7 hours, and -1 returns a cached token, valid for at least 1 hour
+	return strOrErr(s.CreateAuthToken(int64(cDeadline)))
 }
 
 //export SwitchAPIKey
-func SwitchAPIKey(c C.int) (ret *C.char) {
-	var err error
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("%v", r)
-		}
-		if err != nil {
-			ret = wrapErr(err)
-		}
-	}()
+func SwitchAPIKey(cHandle C.longlong, cApiKeyIndex C.int) (ret C.Err) {
+	defer recoverErr(&ret)
 
-	txClient = backupTxClients[uint8(c)]
-	if txClient == nil {
-		err = fmt.Errorf("no client initialized for api key")
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return wrapErr(err)
 	}
-
-	return
+	apiKeyIndex, err := core.APIKeyIndex(int64(cApiKeyIndex))
+	if err != nil {
+		return wrapErr(err)
+	}
+	return errOrNil(s.SwitchAPIKey(apiKeyIndex))
 }
 
 //export SignUpdateMargin
-func SignUpdateMargin(cMarketIndex C.int, cUSDCAmount C.longlong, cDirection C.int, cNonce C.longlong) (ret C.StrOrErr) {
-	var err error
-	var txInfoStr string
-	defer func() {
-		if r := recover(); r != nil {
-			wrapErr(fmt.Errorf("panic: %v", r))
-		}
-		if err != nil {
-			ret = C.StrOrErr{
-				err: wrapErr(err),
-			}
-		} else {
-			ret = C.StrOrErr{
-				str: C.CString(txInfoStr),
-			}
-		}
-	}()
+func SignUpdateMargin(cHandle C.longlong, cMarketIndex C.int, cUSDCAmount C.longlong, cDirection C.int, cNonce C.longlong) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	if txClient == nil {
-		err = fmt.Errorf("Client is not created, call CreateClient() first")
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
 	}
-
-	marketIndex := uint8(cMarketIndex)
-	usdcAmount := int64(cUSDCAmount)
-	direction := uint8(cDirection)
-	nonce := int64(cNonce)
-
-	txInfo := &types.UpdateMarginTxReq{
-		MarketIndex: marketIndex,
-		USDCAmount:  usdcAmount,
-		Direction:   direction,
+	var params core.ParamChecker
+	p := core.UpdateMarginParams{
+		MarketIndex: params.Uint8("market index", int64(cMarketIndex)),
+		USDCAmount:  types.USDC(cUSDCAmount),
+		Direction:   params.Uint8("direction", int64(cDirection)),
+		Nonce:       int64(cNonce),
 	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
+	if err := params.Err(); err != nil {
+		return strOrErr("", err)
 	}
+	return strOrErr(s.SignUpdateMargin(p))
+}
 
-	tx, err := txClient.GetUpdateMarginTransaction(txInfo, ops)
-
-	txInfoBytes, err := json.Marshal(tx)
-	txInfoStr = string(txInfoBytes)
+// Call runs any method of the client by name, with its params as a JSON object, see core.Session.Call
+//
+//export Call
+func Call(cHandle C.longlong, cMethod *C.char, cParamsJSON *C.char) (ret C.StrOrErr) {
+	defer recoverStrOrErr(&ret)
 
-	return ret
+	s, err := clients.Get(int64(cHandle))
+	if err != nil {
+		return strOrErr("", err)
+	}
+	return strOrErr(s.Call(C.GoString(cMethod), C.GoString(cParamsJSON)))
 }
 
 func main() {}
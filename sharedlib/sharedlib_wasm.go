@@ -3,1202 +3,258 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"syscall/js"
-	"time"
 
-	"github.com/elliottech/lighter-go/client"
-	"github.com/elliottech/lighter-go/types"
-	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
-	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
-	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/elliottech/lighter-go/core"
 )
 
-var (
-	txClient        *client.TxClient
-	backupTxClients map[uint8]*client.TxClient
-)
-
-func generateAPIKey(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in generateAPIKey: %v\n", r)
-		}
-	}()
-
-	seed := ""
-	if len(args) > 0 && args[0].Type() == js.TypeString {
-		seed = args[0].String()
-	}
-
-	var seedP *string
-	if seed != "" {
-		seedP = &seed
-	}
-
-	key := curve.SampleScalar(seedP)
-	publicKeyStr := hexutil.Encode(schnorr.SchnorrPkFromSk(key).ToLittleEndianBytes())
-	privateKeyStr := hexutil.Encode(key.ToLittleEndianBytes())
-
-	result := map[string]interface{}{
-		"privateKey": privateKeyStr,
-		"publicKey":  publicKeyStr,
-		"err":        nil,
-	}
+// clients holds the sessions of the client objects returned by lighter.createClient, by handle.
+// The functions only convert their JS arguments & results, defaults and validation are implemented
+// by the core package, shared with the C & mobile bindings.
+var clients = core.NewRegistry()
+
+// newSession creates the session of a client object. Unless built with signonly, its requests are sent with fetch.
+var newSession = core.NewSession
+
+// clientPrototype holds the methods of the client objects, which find their session by the handle of this
+var clientPrototype js.Value
+
+// promise runs fn in a goroutine and returns a Promise resolving with its result, or rejecting with a jsError.
+// Blocking the JS event loop on an HTTP request, e.g. to fetch a nonce, would deadlock, as the request needs
+// the event loop to complete, so every function of the module returns a Promise.
+func promise(name string, fn func() (interface{}, error)) js.Value {
+	executor := js.FuncOf(func(this js.Value, promiseArgs []js.Value) interface{} {
+		resolve, reject := promiseArgs[0], promiseArgs[1]
+
+		go func() {
+			defer func() {
+				if r := recover(); r != nil {
+					reject.Invoke(jsError(name, fmt.Errorf("panic in %s: %v", name, r)))
+				}
+			}()
+
+			result, err := fn()
+			if err != nil {
+				reject.Invoke(jsError(name, err))
+				return
+			}
+			resolve.Invoke(result)
+		}()
+		return nil
+	})
+	// the executor is called by the Promise constructor, so it can be released right after
+	defer executor.Release()
 
-	return js.ValueOf(result)
+	return js.Global().Get("Promise").New(executor)
 }
 
-func createClient(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in createClient: %v\n", r)
-		}
-	}()
-
-	if len(args) < 5 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	url := args[0].String()
-	privateKey := args[1].String()
-	chainId := uint32(args[2].Int())
-	apiKeyIndex := uint8(args[3].Int())
-	accountIndex := int64(args[4].Int())
-
-	if accountIndex <= 0 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "invalid account index",
-		})
-	}
-
-	httpClient := client.NewHTTPClient(url)
-	var err error
-	txClient, err = client.NewTxClient(httpClient, privateKey, accountIndex, apiKeyIndex, chainId)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	if backupTxClients == nil {
-		backupTxClients = make(map[uint8]*client.TxClient)
-	}
-	backupTxClients[apiKeyIndex] = txClient
-
-	return js.ValueOf(map[string]interface{}{
-		"err": nil,
-	})
+// jsError converts err to a JS Error, with the name of the function which failed in its method property,
+// and its core.ErrorCode in its code property
+func jsError(method string, err error) js.Value {
+	jsErr := js.Global().Get("Error").New(err.Error())
+	jsErr.Set("name", "LighterError")
+	jsErr.Set("method", method)
+	jsErr.Set("code", int(core.ErrorCodeOf(err)))
+	return jsErr
 }
 
-func checkClient(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in checkClient: %v\n", r)
-		}
-	}()
-
-	if len(args) < 2 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	apiKeyIndex := uint8(args[0].Int())
-	accountIndex := int64(args[1].Int())
-
-	client, ok := backupTxClients[apiKeyIndex]
-	if !ok {
-		return js.ValueOf(map[string]interface{}{
-			"err": "api key not registered",
-		})
-	}
-
-	if client.GetApiKeyIndex() != apiKeyIndex {
-		return js.ValueOf(map[string]interface{}{
-			"err": fmt.Sprintf("apiKeyIndex does not match. expected %v but got %v", client.GetApiKeyIndex(), apiKeyIndex),
-		})
-	}
-	if client.GetAccountIndex() != accountIndex {
-		return js.ValueOf(map[string]interface{}{
-			"err": fmt.Sprintf("accountIndex does not match. expected %v but got %v", client.GetAccountIndex(), accountIndex),
-		})
-	}
-
-	// check that the API key registered on Lighter matches this one
-	key, err := client.HTTP().GetApiKey(accountIndex, apiKeyIndex)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": fmt.Sprintf("failed to get Api Keys. err: %v", err),
-		})
-	}
-
-	pubKeyBytes := client.GetKeyManager().PubKeyBytes()
-	pubKeyStr := hexutil.Encode(pubKeyBytes[:])
-	pubKeyStr = strings.Replace(pubKeyStr, "0x", "", 1)
-
-	ak := key.ApiKeys[0]
-	if ak.PublicKey != pubKeyStr {
-		return js.ValueOf(map[string]interface{}{
-			"err": fmt.Sprintf("private key does not match the one on Lighter. ownPubKey: %s response: %+v", pubKeyStr, ak),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"err": nil,
-	})
+func invalidParams(err error) error {
+	return core.WithErrorCode(core.ErrorCodeInvalidParams, fmt.Errorf("invalid params. err: %w", err))
 }
 
-func signChangePubKey(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signChangePubKey: %v\n", r)
-		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
+var errInsufficientArguments = core.WithErrorCode(core.ErrorCodeInvalidParams, errors.New("insufficient arguments"))
 
-	if len(args) < 2 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	pubKeyStr := args[0].String()
-	nonce := int64(args[1].Int())
-
-	// handle PubKey
-	pubKeyBytes, err := hexutil.Decode(pubKeyStr)
+// methodFunc exposes a method of core.Session.Call, taking a params object with the field names of its params.
+// session returns the session to run it with, from the this of the call.
+func methodFunc(name string, session func(this js.Value) (*core.Session, error)) js.Func {
+	jsName := core.LowerCamelCase(name)
+	params, err := core.NewParams(name)
 	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+		panic(err)
 	}
-	if len(pubKeyBytes) != 40 {
-		return js.ValueOf(map[string]interface{}{
-			"err": fmt.Sprintf("invalid pub key length. expected 40 but got %v", len(pubKeyBytes)),
-		})
-	}
-	var pubKey [40]byte
-	copy(pubKey[:], pubKeyBytes)
+	paramsType := reflect.TypeOf(params).Elem()
 
-	txInfo := &types.ChangePubKeyReq{
-		PubKey: pubKey,
-	}
-	ops := &types.TransactOpts{}
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetChangePubKeyTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	// === manually add MessageToSign to the response:
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func signCreateOrder(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signCreateOrder: %v\n", r)
+	return js.FuncOf(func(this js.Value, args []js.Value) interface{} {
+		params := js.Undefined()
+		if len(args) > 0 {
+			params = args[0]
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 11 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	marketIndex := uint8(args[0].Int())
-	clientOrderIndex := int64(args[1].Int())
-	baseAmount := int64(args[2].Int())
-	price := uint32(args[3].Int())
-	isAsk := uint8(args[4].Int())
-	orderType := uint8(args[5].Int())
-	timeInForce := uint8(args[6].Int())
-	reduceOnly := uint8(args[7].Int())
-	triggerPrice := uint32(args[8].Int())
-	orderExpiry := int64(args[9].Int())
-	nonce := int64(args[10].Int())
-
-	if orderExpiry == -1 {
-		orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
-	}
-
-	txInfo := &types.CreateOrderTxReq{
-		MarketIndex:      marketIndex,
-		ClientOrderIndex: clientOrderIndex,
-		BaseAmount:       baseAmount,
-		Price:            price,
-		IsAsk:            isAsk,
-		Type:             orderType,
-		TimeInForce:      timeInForce,
-		ReduceOnly:       reduceOnly,
-		TriggerPrice:     triggerPrice,
-		OrderExpiry:      orderExpiry,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
 
-	tx, err := txClient.GetCreateOrderTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
+		return promise(jsName, func() (interface{}, error) {
+			s, err := session(this)
+			if err != nil {
+				return nil, err
+			}
+			paramsJSON, err := paramsJSON(params, paramsType)
+			if err != nil {
+				return nil, invalidParams(err)
+			}
+			result, err := s.Call(name, paramsJSON)
+			if err != nil {
+				return nil, err
+			}
+			return jsResult(name, result)
 		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
 	})
 }
 
-func signCancelOrder(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signCancelOrder: %v\n", r)
-		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 3 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	marketIndex := uint8(args[0].Int())
-	orderIndex := int64(args[1].Int())
-	nonce := int64(args[2].Int())
-
-	txInfo := &types.CancelOrderTxReq{
-		MarketIndex: marketIndex,
-		Index:       orderIndex,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCancelOrderTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
+var errDestroyed = core.WithErrorCode(core.ErrorCodeNotInitialized, errors.New("the client was destroyed"))
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+// clientHandle returns the handle of a client object
+func clientHandle(this js.Value) (int64, error) {
+	if this.Type() != js.TypeObject || this.Get("handle").Type() != js.TypeNumber {
+		return 0, core.WithErrorCode(core.ErrorCodeNotInitialized, fmt.Errorf("not called on a client, call it as a method of the client returned by createClient"))
 	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
+	return int64(this.Get("handle").Int()), nil
 }
 
-func signCreateGroupedOrders(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signCreateGroupedOrders: %v\n", r)
-		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 3 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments: expected groupingType, ordersJSON, nonce",
-		})
-	}
-
-	groupingType := uint8(args[0].Int())
-	ordersJSON := args[1].String()
-	nonce := int64(args[2].Int())
-
-	// Parse orders from JSON
-	var ordersData []map[string]interface{}
-	if err := json.Unmarshal([]byte(ordersJSON), &ordersData); err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": fmt.Sprintf("failed to parse orders JSON: %v", err),
-		})
-	}
-
-	// Convert to CreateOrderTxReq slice
-	orders := make([]*types.CreateOrderTxReq, 0, len(ordersData))
-	for i, orderData := range ordersData {
-		marketIndex, ok := orderData["marketIndex"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: marketIndex is required", i),
-			})
-		}
-
-		clientOrderIndex, ok := orderData["clientOrderIndex"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: clientOrderIndex is required", i),
-			})
-		}
-
-		baseAmount, ok := orderData["baseAmount"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: baseAmount is required", i),
-			})
-		}
-
-		price, ok := orderData["price"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: price is required", i),
-			})
-		}
-
-		isAsk, ok := orderData["isAsk"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: isAsk is required", i),
-			})
-		}
-
-		orderType, ok := orderData["type"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: type is required", i),
-			})
-		}
-
-		timeInForce, ok := orderData["timeInForce"].(float64)
-		if !ok {
-			return js.ValueOf(map[string]interface{}{
-				"err": fmt.Sprintf("order %d: timeInForce is required", i),
-			})
-		}
-
-		reduceOnly := uint8(0)
-		if ro, ok := orderData["reduceOnly"].(float64); ok {
-			reduceOnly = uint8(ro)
-		}
-
-		triggerPrice := uint32(0)
-		if tp, ok := orderData["triggerPrice"].(float64); ok {
-			triggerPrice = uint32(tp)
-		}
-
-		orderExpiry := int64(-1)
-		if oe, ok := orderData["orderExpiry"].(float64); ok {
-			orderExpiry = int64(oe)
-		}
-		if orderExpiry == -1 {
-			orderExpiry = time.Now().Add(time.Hour * 24 * 28).UnixMilli() // 28 days
-		}
-
-		orders = append(orders, &types.CreateOrderTxReq{
-			MarketIndex:      uint8(marketIndex),
-			ClientOrderIndex: int64(clientOrderIndex),
-			BaseAmount:       int64(baseAmount),
-			Price:            uint32(price),
-			IsAsk:            uint8(isAsk),
-			Type:             uint8(orderType),
-			TimeInForce:      uint8(timeInForce),
-			ReduceOnly:       reduceOnly,
-			TriggerPrice:     triggerPrice,
-			OrderExpiry:      orderExpiry,
-		})
-	}
-
-	txInfo := &types.CreateGroupedOrdersTxReq{
-		GroupingType: groupingType,
-		Orders:       orders,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateGroupedOrdersTransaction(txInfo, ops)
+// clientSession returns the session of a client object
+func clientSession(this js.Value) (*core.Session, error) {
+	handle, err := clientHandle(this)
 	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+		return nil, err
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
+	s, err := clients.Get(handle)
 	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+		return nil, errDestroyed
 	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
+	return s, nil
 }
 
-func signWithdraw(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signWithdraw: %v\n", r)
-		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 2 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	usdcAmount := uint64(args[0].Int())
-	nonce := int64(args[1].Int())
-
-	txInfo := types.WithdrawTxReq{
-		USDCAmount: usdcAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetWithdrawTransaction(&txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
+// noSession is the session of the functions which don't use it, like generateAPIKey
+func noSession(js.Value) (*core.Session, error) {
+	return core.NewSession(), nil
 }
 
-func signCreateSubAccount(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signCreateSubAccount: %v\n", r)
-		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 1 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	nonce := int64(args[0].Int())
-
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreateSubAccountTransaction(ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+// createClient creates a session with its first client, and resolves with a client object bound to it
+func createClient(this js.Value, args []js.Value) interface{} {
+	params := js.Undefined()
+	if len(args) > 0 {
+		params = args[0]
 	}
 
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func signCancelAllOrders(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signCancelAllOrders: %v\n", r)
+	return promise("createClient", func() (interface{}, error) {
+		paramsJSON, err := paramsJSON(params, reflect.TypeOf(core.CreateClientParams{}))
+		if err != nil {
+			return nil, invalidParams(err)
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 3 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	timeInForce := uint8(args[0].Int())
-	t := int64(args[1].Int())
-	nonce := int64(args[2].Int())
-
-	txInfo := &types.CancelAllOrdersTxReq{
-		TimeInForce: timeInForce,
-		Time:        t,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCancelAllOrdersTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func signModifyOrder(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signModifyOrder: %v\n", r)
+		s := newSession()
+		if _, err := s.Call("CreateClient", paramsJSON); err != nil {
+			return nil, err
 		}
-	}()
 
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 6 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	marketIndex := uint8(args[0].Int())
-	index := int64(args[1].Int())
-	baseAmount := int64(args[2].Int())
-	price := uint32(args[3].Int())
-	triggerPrice := uint32(args[4].Int())
-	nonce := int64(args[5].Int())
-
-	txInfo := &types.ModifyOrderTxReq{
-		MarketIndex:  marketIndex,
-		Index:        index,
-		BaseAmount:   baseAmount,
-		Price:        price,
-		TriggerPrice: triggerPrice,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetModifyOrderTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
+		client := js.Global().Get("Object").Call("create", clientPrototype)
+		descriptor := js.Global().Get("Object").New()
+		descriptor.Set("value", clients.Add(s))
+		descriptor.Set("enumerable", true)
+		js.Global().Get("Object").Call("defineProperty", client, "handle", descriptor)
+		return client, nil
 	})
 }
 
-func signTransfer(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signTransfer: %v\n", r)
+// destroy releases the session of a client object, its methods reject afterwards
+func destroy(this js.Value, args []js.Value) interface{} {
+	return promise("destroy", func() (interface{}, error) {
+		handle, err := clientHandle(this)
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 5 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	toAccountIndex := int64(args[0].Int())
-	usdcAmount := int64(args[1].Int())
-	fee := int64(args[2].Int())
-	memoStr := args[3].String()
-	nonce := int64(args[4].Int())
-
-	memo := [32]byte{}
-	if len(memoStr) != 32 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "memo expected to be 32 bytes long",
-		})
-	}
-	for i := 0; i < 32; i++ {
-		memo[i] = byte(memoStr[i])
-	}
-
-	txInfo := &types.TransferTxReq{
-		ToAccountIndex: toAccountIndex,
-		USDCAmount:     usdcAmount,
-		Fee:            fee,
-		Memo:           memo,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetTransferTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-	
-	// Add MessageToSign like in original
-	obj := make(map[string]interface{})
-	err = json.Unmarshal(txInfoBytes, &obj)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-	obj["MessageToSign"] = tx.GetL1SignatureBody()
-	txInfoBytes, err = json.Marshal(obj)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func signCreatePublicPool(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signCreatePublicPool: %v\n", r)
+		if err := clients.Remove(handle); err != nil {
+			return nil, errDestroyed
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 4 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	operatorFee := int64(args[0].Int())
-	initialTotalShares := int64(args[1].Int())
-	minOperatorShareRate := int64(args[2].Int())
-	nonce := int64(args[3].Int())
-
-	txInfo := &types.CreatePublicPoolTxReq{
-		OperatorFee:          operatorFee,
-		InitialTotalShares:   initialTotalShares,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetCreatePublicPoolTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
+		return js.Undefined(), nil
 	})
 }
 
-func signUpdatePublicPool(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signUpdatePublicPool: %v\n", r)
-		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 5 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	publicPoolIndex := int64(args[0].Int())
-	status := uint8(args[1].Int())
-	operatorFee := int64(args[2].Int())
-	minOperatorShareRate := int64(args[3].Int())
-	nonce := int64(args[4].Int())
-
-	txInfo := &types.UpdatePublicPoolTxReq{
-		PublicPoolIndex:      publicPoolIndex,
-		Status:               status,
-		OperatorFee:          operatorFee,
-		MinOperatorShareRate: minOperatorShareRate,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdatePublicPoolTransaction(txInfo, ops)
+// jsResult converts the result of a method according to its kind: integers & the integers of the JSON results
+// are BigInt, so nonces & indexes aren't rounded
+func jsResult(name, result string) (interface{}, error) {
+	kind, err := core.MethodResult(name)
 	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+		return nil, err
 	}
 
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+	switch kind {
+	case core.ResultNone:
+		return js.Undefined(), nil
+	case core.ResultInteger:
+		return js.Global().Get("BigInt").Invoke(result), nil
+	case core.ResultJSON:
+		return parseJSON(result)
+	default:
+		return result, nil
 	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
 }
 
-func signMintShares(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signMintShares: %v\n", r)
+// call runs any method of a client by name, with its params as a JSON string, see core.Session.Call
+func call(this js.Value, args []js.Value) interface{} {
+	return promise("call", func() (interface{}, error) {
+		s, err := clientSession(this)
+		if err != nil {
+			return nil, err
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 3 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	publicPoolIndex := int64(args[0].Int())
-	shareAmount := int64(args[1].Int())
-	nonce := int64(args[2].Int())
-
-	txInfo := &types.MintSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetMintSharesTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func signBurnShares(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signBurnShares: %v\n", r)
+		if len(args) < 1 {
+			return nil, errInsufficientArguments
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 3 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	publicPoolIndex := int64(args[0].Int())
-	shareAmount := int64(args[1].Int())
-	nonce := int64(args[2].Int())
-
-	txInfo := &types.BurnSharesTxReq{
-		PublicPoolIndex: publicPoolIndex,
-		ShareAmount:     shareAmount,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetBurnSharesTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func signUpdateLeverage(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signUpdateLeverage: %v\n", r)
+		params := ""
+		if len(args) > 1 {
+			params = args[1].String()
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 4 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	marketIndex := uint8(args[0].Int())
-	initialMarginFraction := uint16(args[1].Int())
-	marginMode := uint8(args[2].Int())
-	nonce := int64(args[3].Int())
-
-	txInfo := &types.UpdateLeverageTxReq{
-		MarketIndex:           marketIndex,
-		InitialMarginFraction: initialMarginFraction,
-		MarginMode:            marginMode,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
-	}
-
-	tx, err := txClient.GetUpdateLeverageTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
+		return s.Call(args[0].String(), params)
 	})
 }
 
-func createAuthToken(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in createAuthToken: %v\n", r)
+// parseTxInfoFunc parses the tx info returned by the sign functions, with its integers as BigInt
+func parseTxInfoFunc(this js.Value, args []js.Value) interface{} {
+	return promise("parseTxInfo", func() (interface{}, error) {
+		if len(args) < 1 {
+			return nil, errInsufficientArguments
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "client is not created, call createClient() first",
-		})
-	}
-
-	if len(args) < 1 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	deadline := int64(args[0].Int())
-	if deadline == 0 {
-		deadline = time.Now().Add(time.Hour * 7).Unix()
-	}
-
-	authToken, err := txClient.GetAuthToken(time.Unix(deadline, 0))
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": authToken,
-		"err": nil,
-	})
-}
-
-func switchAPIKey(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in switchAPIKey: %v\n", r)
+		txInfo, err := parseJSON(args[0].String())
+		if err != nil {
+			return nil, core.WithErrorCode(core.ErrorCodeInvalidParams, err)
 		}
-	}()
-
-	if len(args) < 1 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	apiKeyIndex := uint8(args[0].Int())
-
-	client, ok := backupTxClients[apiKeyIndex]
-	if !ok {
-		return js.ValueOf(map[string]interface{}{
-			"err": "api key not registered",
-		})
-	}
-
-	txClient = client
-
-	return js.ValueOf(map[string]interface{}{
-		"err": nil,
+		return txInfo, nil
 	})
 }
 
-func signUpdateMargin(this js.Value, args []js.Value) interface{} {
-	defer func() {
-		if r := recover(); r != nil {
-			fmt.Printf("Panic in signUpdateMargin: %v\n", r)
+func main() {
+	// The methods of the clients are named like the methods of core.Session.Call in lower camel case,
+	// e.g. client.signCreateOrder({marketIndex: 0, ...}), and shared by the client objects
+	clientPrototype = js.Global().Get("Object").New()
+	for _, name := range core.ClientMethods() {
+		clientPrototype.Set(core.LowerCamelCase(name), methodFunc(name, clientSession))
+	}
+	clientPrototype.Set("call", js.FuncOf(call))
+	clientPrototype.Set("destroy", js.FuncOf(destroy))
+
+	// Register the functions of the module under globalThis.lighter
+	lighter := js.Global().Get("Object").New()
+	lighter.Set("generateAPIKey", methodFunc("GenerateAPIKey", noSession))
+	lighter.Set("createClient", js.FuncOf(createClient))
+	lighter.Set("parseTxInfo", js.FuncOf(parseTxInfoFunc))
+	lighter.Set("version", core.Version)
+
+	// the tx types to send the tx infos with, e.g. lighter.txTypes.createOrder
+	txTypes := js.Global().Get("Object").New()
+	for _, name := range core.Methods() {
+		if txType, ok := core.TxType(name); ok {
+			txTypes.Set(core.LowerCamelCase(strings.TrimPrefix(name, "Sign")), txType)
 		}
-	}()
-
-	if txClient == nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": "Client is not created, call CreateClient() first",
-		})
-	}
-
-	if len(args) < 4 {
-		return js.ValueOf(map[string]interface{}{
-			"err": "insufficient arguments",
-		})
-	}
-
-	marketIndex := uint8(args[0].Int())
-	usdcAmount := int64(args[1].Int())
-	direction := uint8(args[2].Int())
-	nonce := int64(args[3].Int())
-
-	txInfo := &types.UpdateMarginTxReq{
-		MarketIndex: marketIndex,
-		USDCAmount:  usdcAmount,
-		Direction:   direction,
-	}
-	ops := new(types.TransactOpts)
-	if nonce != -1 {
-		ops.Nonce = &nonce
 	}
+	lighter.Set("txTypes", txTypes)
 
-	tx, err := txClient.GetUpdateMarginTransaction(txInfo, ops)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
+	// the codes of the errors, e.g. lighter.errorCodes.NotInitialized, to compare with the code of a LighterError
+	errorCodes := js.Global().Get("Object").New()
+	for name, code := range core.ErrorCodes() {
+		errorCodes.Set(name, int(code))
 	}
-
-	txInfoBytes, err := json.Marshal(tx)
-	if err != nil {
-		return js.ValueOf(map[string]interface{}{
-			"err": err.Error(),
-		})
-	}
-
-	return js.ValueOf(map[string]interface{}{
-		"str": string(txInfoBytes),
-		"err": nil,
-	})
-}
-
-func main() {
-	// Register functions to be called from JavaScript
-	js.Global().Set("GenerateAPIKey", js.FuncOf(generateAPIKey))
-	js.Global().Set("CreateClient", js.FuncOf(createClient))
-	js.Global().Set("CheckClient", js.FuncOf(checkClient))
-	js.Global().Set("SignChangePubKey", js.FuncOf(signChangePubKey))
-	js.Global().Set("SignCreateOrder", js.FuncOf(signCreateOrder))
-	js.Global().Set("SignCancelOrder", js.FuncOf(signCancelOrder))
-	js.Global().Set("SignCreateGroupedOrders", js.FuncOf(signCreateGroupedOrders))
-	js.Global().Set("SignWithdraw", js.FuncOf(signWithdraw))
-	js.Global().Set("SignCreateSubAccount", js.FuncOf(signCreateSubAccount))
-	js.Global().Set("SignCancelAllOrders", js.FuncOf(signCancelAllOrders))
-	js.Global().Set("SignModifyOrder", js.FuncOf(signModifyOrder))
-	js.Global().Set("SignTransfer", js.FuncOf(signTransfer))
-	js.Global().Set("SignCreatePublicPool", js.FuncOf(signCreatePublicPool))
-	js.Global().Set("SignUpdatePublicPool", js.FuncOf(signUpdatePublicPool))
-	js.Global().Set("SignMintShares", js.FuncOf(signMintShares))
-	js.Global().Set("SignBurnShares", js.FuncOf(signBurnShares))
-	js.Global().Set("SignUpdateLeverage", js.FuncOf(signUpdateLeverage))
-	js.Global().Set("CreateAuthToken", js.FuncOf(createAuthToken))
-	js.Global().Set("SwitchAPIKey", js.FuncOf(switchAPIKey))
-	js.Global().Set("SignUpdateMargin", js.FuncOf(signUpdateMargin))
+	lighter.Set("errorCodes", errorCodes)
+	js.Global().Set("lighter", lighter)
 
 	fmt.Println("Lighter Go WASM module loaded successfully")
 
@@ -0,0 +1,52 @@
+#!/bin/bash
+
+# Builds the WASM profiles and fails if one is over its size budget, or if the signonly profile links a package
+# it shouldn't. The budgets are in bytes, of the binary and of its gzip, which is what a browser downloads.
+# Run from the project root, e.g. `./sharedlib/size.sh` or `just wasm-size`.
+
+set -e
+
+FULL_BUDGET=${FULL_BUDGET:-14000000}
+FULL_GZIP_BUDGET=${FULL_GZIP_BUDGET:-3600000}
+SIGNONLY_BUDGET=${SIGNONLY_BUDGET:-6300000}
+SIGNONLY_GZIP_BUDGET=${SIGNONLY_GZIP_BUDGET:-1700000}
+# TinyGo shouldn't do worse than the standard toolchain
+TINYGO_BUDGET=${TINYGO_BUDGET:-$SIGNONLY_BUDGET}
+TINYGO_GZIP_BUDGET=${TINYGO_GZIP_BUDGET:-$SIGNONLY_GZIP_BUDGET}
+
+BUILD_DIR="./build"
+mkdir -p "$BUILD_DIR"
+failed=0
+
+check() {
+  local name=$1 file=$2 budget=$3 gzip_budget=$4
+  local size gzip_size
+  size=$(wc -c < "$file")
+  gzip_size=$(gzip -9c "$file" | wc -c)
+  printf "%-10s %10d bytes (budget %10d)  gzip %10d bytes (budget %10d)\n" "$name" "$size" "$budget" "$gzip_size" "$gzip_budget"
+  if [ "$size" -gt "$budget" ] || [ "$gzip_size" -gt "$gzip_budget" ]; then
+    echo "❌ $name is over budget"
+    failed=1
+  fi
+}
+
+GOOS=js GOARCH=wasm go build -trimpath -o "$BUILD_DIR/sharedlib.wasm" ./sharedlib
+check full "$BUILD_DIR/sharedlib.wasm" "$FULL_BUDGET" "$FULL_GZIP_BUDGET"
+
+GOOS=js GOARCH=wasm go build -trimpath -tags signonly -ldflags="-s -w" -o "$BUILD_DIR/sharedlib-signonly.wasm" ./sharedlib
+check signonly "$BUILD_DIR/sharedlib-signonly.wasm" "$SIGNONLY_BUDGET" "$SIGNONLY_GZIP_BUDGET"
+
+forbidden=$(GOOS=js GOARCH=wasm go list -deps -tags signonly ./sharedlib | grep -E '^net(/|$)|^crypto/tls$|go-ethereum' || true)
+if [ -n "$forbidden" ]; then
+  echo "❌ signonly links" $forbidden
+  failed=1
+fi
+
+if command -v tinygo &> /dev/null; then
+  tinygo build -target wasm -tags signonly -no-debug -o "$BUILD_DIR/sharedlib-tinygo.wasm" ./sharedlib
+  check tinygo "$BUILD_DIR/sharedlib-tinygo.wasm" "$TINYGO_BUDGET" "$TINYGO_GZIP_BUDGET"
+else
+  echo "tinygo not found, skipping the TinyGo profile"
+fi
+
+exit $failed
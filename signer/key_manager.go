@@ -1,10 +1,12 @@
 package signer
 
 import (
+	"encoding/binary"
 	"fmt"
 	"hash"
 
 	curve "github.com/elliottech/poseidon_crypto/curve/ecgfp5"
+	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/elliottech/poseidon_crypto/field/goldilocks_quintic_extension"
 	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
 )
@@ -20,19 +22,26 @@ type KeyManager interface {
 	PrvKeyBytes() []byte
 }
 
+// keyManager is safe for concurrent use. The public key is derived once, as it costs as much as a signature.
 type keyManager struct {
-	key curve.ECgFp5Scalar
+	key         curve.ECgFp5Scalar
+	pubKey      gFp5.Element
+	pubKeyBytes [40]byte
 }
 
 func NewKeyManager(b []byte) (KeyManager, error) {
 	if len(b) != 40 {
 		return nil, fmt.Errorf("invalid private key length. expected: 40 got: %v", len(b))
 	}
-	return &keyManager{key: curve.ScalarElementFromLittleEndianBytes(b)}, nil
+
+	key := &keyManager{key: curve.ScalarElementFromLittleEndianBytes(b)}
+	key.pubKey = schnorr.SchnorrPkFromSk(key.key)
+	copy(key.pubKeyBytes[:], key.pubKey.ToLittleEndianBytes())
+	return key, nil
 }
 
 func (key *keyManager) Sign(hashedMessage []byte, hFunc hash.Hash) ([]byte, error) {
-	hashedMessageAsQuinticExtension, err := gFp5.FromCanonicalLittleEndianBytes(hashedMessage)
+	hashedMessageAsQuinticExtension, err := parseHashedMessage(hashedMessage)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse message while signing. message: %v err: %w", hashedMessage, err)
 	}
@@ -40,15 +49,28 @@ func (key *keyManager) Sign(hashedMessage []byte, hFunc hash.Hash) ([]byte, erro
 }
 
 func (key *keyManager) PubKey() gFp5.Element {
-	return schnorr.SchnorrPkFromSk(key.key)
+	return key.pubKey
 }
 
-func (key *keyManager) PubKeyBytes() (res [40]byte) {
-	bytes := key.PubKey().ToLittleEndianBytes()
-	copy(res[:], bytes[:])
-	return
+func (key *keyManager) PubKeyBytes() [40]byte {
+	return key.pubKeyBytes
 }
 
 func (key *keyManager) PrvKeyBytes() []byte {
 	return key.key.ToLittleEndianBytes()
 }
+
+// parseHashedMessage is gFp5.FromCanonicalLittleEndianBytes, without the intermediate allocations
+func parseHashedMessage(b []byte) (res gFp5.Element, err error) {
+	if len(b) != gFp5.Bytes {
+		return res, fmt.Errorf("input bytes len should be %d but is %d", gFp5.Bytes, len(b))
+	}
+	for i := range res {
+		limb := binary.LittleEndian.Uint64(b[i*g.Bytes:])
+		if limb >= g.ORDER {
+			return res, fmt.Errorf("limb %d is not a canonical field element", i)
+		}
+		res[i] = g.FromUint64(limb)
+	}
+	return res, nil
+}
@@ -0,0 +1,72 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+
+	gFp5 "github.com/elliottech/poseidon_crypto/field/goldilocks_quintic_extension"
+	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
+)
+
+// ErrInvalidSignature is returned when a KeyProvider signature doesn't verify against its public key,
+// e.g. when the Keychain entry was replaced by another key
+var ErrInvalidSignature = errors.New("the signature of the key provider does not match its public key")
+
+// KeyProvider signs with a private key kept outside of the process memory, e.g. in the Keychain of a phone,
+// which is only decrypted when signing. It must be safe for concurrent use.
+type KeyProvider interface {
+	// Sign returns the 80 bytes Schnorr signature of the 40 bytes hashed message
+	Sign(hashedMessage []byte) ([]byte, error)
+	// PublicKey returns the 40 bytes public key
+	PublicKey() []byte
+}
+
+// providedKeyManager is a KeyManager signing with a KeyProvider. The private key is unknown, so PrvKeyBytes is nil.
+type providedKeyManager struct {
+	provider    KeyProvider
+	pubKey      gFp5.Element
+	pubKeyBytes [40]byte
+}
+
+func NewKeyManagerFromProvider(provider KeyProvider) (KeyManager, error) {
+	b := provider.PublicKey()
+	if len(b) != 40 {
+		return nil, fmt.Errorf("invalid public key length. expected: 40 got: %v", len(b))
+	}
+	pubKey, err := gFp5.FromCanonicalLittleEndianBytes(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key. err: %w", err)
+	}
+
+	key := &providedKeyManager{provider: provider, pubKey: pubKey}
+	copy(key.pubKeyBytes[:], b)
+	return key, nil
+}
+
+func (key *providedKeyManager) Sign(hashedMessage []byte, hFunc hash.Hash) ([]byte, error) {
+	sig, err := key.provider.Sign(hashedMessage)
+	if err != nil {
+		return nil, fmt.Errorf("key provider failed to sign. err: %w", err)
+	}
+	if len(sig) != 80 {
+		return nil, fmt.Errorf("invalid signature length from key provider. expected: 80 got: %v", len(sig))
+	}
+	// otherwise the tx would only be rejected once sent to Lighter
+	if err := schnorr.Validate(key.pubKeyBytes[:], hashedMessage, sig); err != nil {
+		return nil, fmt.Errorf("%w. err: %v", ErrInvalidSignature, err)
+	}
+	return sig, nil
+}
+
+func (key *providedKeyManager) PubKey() gFp5.Element {
+	return key.pubKey
+}
+
+func (key *providedKeyManager) PubKeyBytes() [40]byte {
+	return key.pubKeyBytes
+}
+
+func (key *providedKeyManager) PrvKeyBytes() []byte {
+	return nil
+}
@@ -0,0 +1,48 @@
+package types
+
+import (
+	"testing"
+)
+
+// BenchmarkConstructTx measures hashing & signing of every tx type, as done by the TxClient.
+// Run with `go test ./types -run '^$' -bench . -benchmem`.
+func BenchmarkConstructTx(b *testing.B) {
+	key := mustKeyManager(b, vectorPrivateKey)
+
+	for _, vc := range vectorCases(b) {
+		if vc.signedBy != "" {
+			continue
+		}
+		b.Run(vc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := vc.construct(key, vectorChainId, vectorTransactOpts()); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkHashTx measures the hash of every tx type, without signing
+func BenchmarkHashTx(b *testing.B) {
+	key := mustKeyManager(b, vectorPrivateKey)
+
+	for _, vc := range vectorCases(b) {
+		if vc.signedBy != "" {
+			continue
+		}
+		tx, err := vc.construct(key, vectorChainId, vectorTransactOpts())
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(vc.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := tx.Hash(vectorChainId); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// Grouped orders have ordering and sizing rules which are enforced by the exchange,
+// and only partially by L2CreateGroupedOrdersTxInfo.Validate:
+//   - the parent (entry) order comes first and is a limit or market order
+//   - child orders (stop loss / take profit) close the position opened by the parent,
+//     so they are reduce only, on the opposite side, and their size is taken from the parent fill (BaseAmount = 0)
+//   - OCO orders are two reduce only orders on the same side, with the same size & expiry
+//   - no order in a group can have a ClientOrderIndex
+//
+// The helpers below build the request in the expected order and check these rules before signing.
+// The given orders are copied, the caller's requests are never modified.
+
+// NewOTO creates a OneTriggersTheOther group. The child order is placed once the entry order is filled.
+// The child's BaseAmount must be either 0 or equal to the entry's BaseAmount. The child is made reduce only.
+func NewOTO(entry, child *CreateOrderTxReq) (*CreateGroupedOrdersTxReq, error) {
+	if entry == nil || child == nil {
+		return nil, fmt.Errorf("entry and child orders are required")
+	}
+
+	parent := *entry
+	children, err := newChildOrders(&parent, child)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGroupedOrders(txtypes.GroupingType_OneTriggersTheOther, append([]*CreateOrderTxReq{&parent}, children...))
+}
+
+// NewBracketOrder creates a OneTriggersAOneCancelsTheOther group. The stop loss & take profit orders are
+// placed once the entry order is filled, and the first one to trigger cancels the other.
+// The BaseAmount of the stop loss & take profit must be either 0 or equal to the entry's BaseAmount.
+func NewBracketOrder(entry, stopLoss, takeProfit *CreateOrderTxReq) (*CreateGroupedOrdersTxReq, error) {
+	if entry == nil || stopLoss == nil || takeProfit == nil {
+		return nil, fmt.Errorf("entry, stop loss and take profit orders are required")
+	}
+	if !isStopLoss(stopLoss.Type) {
+		return nil, fmt.Errorf("stop loss order has invalid type %d. %w", stopLoss.Type, txtypes.ErrOrderTypeInvalid)
+	}
+	if !isTakeProfit(takeProfit.Type) {
+		return nil, fmt.Errorf("take profit order has invalid type %d. %w", takeProfit.Type, txtypes.ErrOrderTypeInvalid)
+	}
+
+	parent := *entry
+	children, err := newChildOrders(&parent, stopLoss, takeProfit)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGroupedOrders(txtypes.GroupingType_OneTriggersAOneCancelsTheOther, append([]*CreateOrderTxReq{&parent}, children...))
+}
+
+// NewOCO creates a OneCancelsTheOther group on an existing position. Both orders are forced to be reduce only,
+// and must be on the same side, with the same BaseAmount & OrderExpiry.
+func NewOCO(stopLoss, takeProfit *CreateOrderTxReq) (*CreateGroupedOrdersTxReq, error) {
+	if stopLoss == nil || takeProfit == nil {
+		return nil, fmt.Errorf("stop loss and take profit orders are required")
+	}
+	if !isStopLoss(stopLoss.Type) {
+		return nil, fmt.Errorf("stop loss order has invalid type %d. %w", stopLoss.Type, txtypes.ErrOrderTypeInvalid)
+	}
+	if !isTakeProfit(takeProfit.Type) {
+		return nil, fmt.Errorf("take profit order has invalid type %d. %w", takeProfit.Type, txtypes.ErrOrderTypeInvalid)
+	}
+
+	sl, tp := *stopLoss, *takeProfit
+	if sl.MarketIndex != tp.MarketIndex {
+		return nil, txtypes.ErrMarketIndexMismatch
+	}
+	if sl.IsAsk != tp.IsAsk {
+		return nil, fmt.Errorf("stop loss and take profit should be on the same side. %w", txtypes.ErrIsAskInvalid)
+	}
+	if sl.BaseAmount != tp.BaseAmount {
+		return nil, txtypes.ErrBaseAmountsNotEqual
+	}
+	if sl.OrderExpiry != tp.OrderExpiry {
+		return nil, fmt.Errorf("stop loss and take profit should have the same expiry. %w", txtypes.ErrOrderExpiryInvalid)
+	}
+	sl.ReduceOnly = 1
+	tp.ReduceOnly = 1
+
+	return newGroupedOrders(txtypes.GroupingType_OneCancelsTheOther, []*CreateOrderTxReq{&sl, &tp})
+}
+
+// NewGroupedOrders creates a group from the orders in the order of the tx, checked by NewOTO, NewBracketOrder
+// or NewOCO depending on groupingType: the entry order comes first, and the stop loss & take profit orders
+// can be in any order. It's meant for the bindings, which take the orders as a list.
+func NewGroupedOrders(groupingType uint8, orders []*CreateOrderTxReq) (*CreateGroupedOrdersTxReq, error) {
+	switch groupingType {
+	case txtypes.GroupingType_OneTriggersTheOther:
+		if len(orders) != 2 {
+			return nil, txtypes.ErrOrderGroupSizeInvalid
+		}
+		return NewOTO(orders[0], orders[1])
+	case txtypes.GroupingType_OneCancelsTheOther:
+		if len(orders) != 2 {
+			return nil, txtypes.ErrOrderGroupSizeInvalid
+		}
+		stopLoss, takeProfit := stopLossFirst(orders[0], orders[1])
+		return NewOCO(stopLoss, takeProfit)
+	case txtypes.GroupingType_OneTriggersAOneCancelsTheOther:
+		if len(orders) != 3 {
+			return nil, txtypes.ErrOrderGroupSizeInvalid
+		}
+		stopLoss, takeProfit := stopLossFirst(orders[1], orders[2])
+		return NewBracketOrder(orders[0], stopLoss, takeProfit)
+	default:
+		return nil, txtypes.ErrGroupingTypeInvalid
+	}
+}
+
+// stopLossFirst orders a stop loss & take profit pair given in any order
+func stopLossFirst(a, b *CreateOrderTxReq) (stopLoss, takeProfit *CreateOrderTxReq) {
+	if a != nil && isTakeProfit(a.Type) {
+		return b, a
+	}
+	return a, b
+}
+
+// newChildOrders copies the child orders, checks them against the parent and applies the size and reduce only rules.
+func newChildOrders(parent *CreateOrderTxReq, orders ...*CreateOrderTxReq) ([]*CreateOrderTxReq, error) {
+	children := make([]*CreateOrderTxReq, 0, len(orders))
+	for _, order := range orders {
+		child := *order
+		if child.MarketIndex != parent.MarketIndex {
+			return nil, txtypes.ErrMarketIndexMismatch
+		}
+		if child.IsAsk == parent.IsAsk {
+			return nil, fmt.Errorf("child orders should be on the opposite side of the entry order. %w", txtypes.ErrIsAskInvalid)
+		}
+		if child.BaseAmount != txtypes.NilOrderBaseAmount && child.BaseAmount != parent.BaseAmount {
+			return nil, fmt.Errorf("child order BaseAmount should match the entry order. %w", txtypes.ErrBaseAmountsNotEqual)
+		}
+		// orders without a BaseAmount have to be reduce only
+		child.BaseAmount = txtypes.NilOrderBaseAmount
+		child.ReduceOnly = 1
+		children = append(children, &child)
+	}
+	return children, nil
+}
+
+func newGroupedOrders(groupingType uint8, orders []*CreateOrderTxReq) (*CreateGroupedOrdersTxReq, error) {
+	for _, order := range orders {
+		if order.ClientOrderIndex != txtypes.NilClientOrderIndex {
+			return nil, txtypes.ErrClientOrderIndexNotNil
+		}
+	}
+
+	req := &CreateGroupedOrdersTxReq{
+		GroupingType: groupingType,
+		Orders:       orders,
+	}
+
+	// run the same checks as the signer, using placeholder account fields
+	var (
+		accountIndex = txtypes.MinAccountIndex
+		apiKeyIndex  = txtypes.MinApiKeyIndex
+		nonce        = txtypes.MinNonce
+	)
+	if err := ConvertCreateGroupedOrdersTx(req, &TransactOpts{
+		FromAccountIndex: &accountIndex,
+		ApiKeyIndex:      &apiKeyIndex,
+		Nonce:            &nonce,
+	}).Validate(); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func isStopLoss(orderType uint8) bool {
+	return orderType == txtypes.StopLossOrder || orderType == txtypes.StopLossLimitOrder
+}
+
+func isTakeProfit(orderType uint8) bool {
+	return orderType == txtypes.TakeProfitOrder || orderType == txtypes.TakeProfitLimitOrder
+}
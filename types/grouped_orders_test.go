@@ -0,0 +1,115 @@
+package types
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+const groupOrderExpiry int64 = 1769817600000 // 2026-01-31 00:00:00 UTC
+
+func entryOrder() *CreateOrderTxReq {
+	return &CreateOrderTxReq{MarketIndex: 0, BaseAmount: 1000, Price: 300000, IsAsk: 0,
+		Type: txtypes.LimitOrder, TimeInForce: txtypes.GoodTillTime, OrderExpiry: groupOrderExpiry}
+}
+
+func stopLossOrder(isAsk uint8) *CreateOrderTxReq {
+	return &CreateOrderTxReq{MarketIndex: 0, Price: 250000, IsAsk: isAsk, Type: txtypes.StopLossOrder,
+		TimeInForce: txtypes.ImmediateOrCancel, TriggerPrice: 250000, OrderExpiry: groupOrderExpiry}
+}
+
+func takeProfitOrder(isAsk uint8) *CreateOrderTxReq {
+	return &CreateOrderTxReq{MarketIndex: 0, Price: 350000, IsAsk: isAsk, Type: txtypes.TakeProfitOrder,
+		TimeInForce: txtypes.ImmediateOrCancel, TriggerPrice: 350000, OrderExpiry: groupOrderExpiry}
+}
+
+// validateGroup runs the checks of the signer on the group, with the account fields set
+func validateGroup(t *testing.T, req *CreateGroupedOrdersTxReq) {
+	t.Helper()
+
+	accountIndex, apiKeyIndex, nonce := int64(100), uint8(3), int64(1)
+	expiredAt := int64(1767225600000)
+	tx := ConvertCreateGroupedOrdersTx(req, &TransactOpts{
+		FromAccountIndex: &accountIndex,
+		ApiKeyIndex:      &apiKeyIndex,
+		Nonce:            &nonce,
+		ExpiredAt:        expiredAt,
+	})
+	if err := tx.Validate(); err != nil {
+		t.Fatalf("the group doesn't pass Validate. err: %v", err)
+	}
+}
+
+func TestGroupedOrders(t *testing.T) {
+	entry := entryOrder()
+	sl, tp := stopLossOrder(1), takeProfitOrder(1)
+
+	oto, err := NewOTO(entry, sl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateGroup(t, oto)
+
+	bracket, err := NewBracketOrder(entry, sl, tp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateGroup(t, bracket)
+	for _, child := range bracket.Orders[1:] {
+		if child.ReduceOnly != 1 || child.BaseAmount != txtypes.NilOrderBaseAmount {
+			t.Errorf("child orders should be reduce only without BaseAmount, got %+v", child)
+		}
+	}
+
+	sl.BaseAmount, tp.BaseAmount = 1000, 1000
+	oco, err := NewOCO(sl, tp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateGroup(t, oco)
+
+	if sl.ReduceOnly != 0 || entry.ReduceOnly != 0 {
+		t.Error("the caller's orders were modified")
+	}
+
+	// the orders of the tx, with the take profit first
+	group, err := NewGroupedOrders(txtypes.GroupingType_OneTriggersAOneCancelsTheOther, []*CreateOrderTxReq{entry, takeProfitOrder(1), stopLossOrder(1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	validateGroup(t, group)
+	if group.Orders[1].Type != txtypes.StopLossOrder {
+		t.Errorf("expected the stop loss as first child, got type %d", group.Orders[1].Type)
+	}
+}
+
+func TestGroupedOrdersErrors(t *testing.T) {
+	entry := entryOrder()
+
+	if _, err := NewOTO(entry, stopLossOrder(0)); !errors.Is(err, txtypes.ErrIsAskInvalid) {
+		t.Errorf("expected ErrIsAskInvalid for a child on the side of the entry, got %v", err)
+	}
+	child := stopLossOrder(1)
+	child.BaseAmount = 500
+	if _, err := NewOTO(entry, child); !errors.Is(err, txtypes.ErrBaseAmountsNotEqual) {
+		t.Errorf("expected ErrBaseAmountsNotEqual, got %v", err)
+	}
+	if _, err := NewBracketOrder(entry, takeProfitOrder(1), stopLossOrder(1)); !errors.Is(err, txtypes.ErrOrderTypeInvalid) {
+		t.Errorf("expected ErrOrderTypeInvalid for swapped orders, got %v", err)
+	}
+
+	sl, tp := stopLossOrder(1), takeProfitOrder(1)
+	sl.BaseAmount, tp.BaseAmount = 1000, 1000
+	tp.OrderExpiry = groupOrderExpiry + 1
+	if _, err := NewOCO(sl, tp); !errors.Is(err, txtypes.ErrOrderExpiryInvalid) {
+		t.Errorf("expected ErrOrderExpiryInvalid for different expiries, got %v", err)
+	}
+
+	if _, err := NewGroupedOrders(txtypes.GroupingType_OneCancelsTheOther, []*CreateOrderTxReq{sl}); !errors.Is(err, txtypes.ErrOrderGroupSizeInvalid) {
+		t.Errorf("expected ErrOrderGroupSizeInvalid, got %v", err)
+	}
+	if _, err := NewGroupedOrders(9, []*CreateOrderTxReq{entry, sl}); !errors.Is(err, txtypes.ErrGroupingTypeInvalid) {
+		t.Errorf("expected ErrGroupingTypeInvalid, got %v", err)
+	}
+}
@@ -1,6 +1,7 @@
 package types
 
 import (
+	"encoding/hex"
 	"fmt"
 	"time"
 
@@ -9,7 +10,6 @@ import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
 	gFp5 "github.com/elliottech/poseidon_crypto/field/goldilocks_quintic_extension"
 	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
-	ethCommon "github.com/ethereum/go-ethereum/common"
 )
 
 type TransactOpts struct {
@@ -28,13 +28,13 @@ type ChangePubKeyReq struct {
 
 type TransferTxReq struct {
 	ToAccountIndex int64
-	USDCAmount     int64
-	Fee            int64
+	USDCAmount     USDC
+	Fee            USDC
 	Memo           [32]byte
 }
 
 type WithdrawTxReq struct {
-	USDCAmount uint64
+	USDCAmount USDC
 }
 
 type CreateOrderTxReq struct {
@@ -50,6 +50,8 @@ type CreateOrderTxReq struct {
 	OrderExpiry      int64
 }
 
+// CreateGroupedOrdersTxReq should be built using NewOTO, NewOCO or NewBracketOrder,
+// which check the ordering, side, size and reduce only rules of grouped orders.
 type CreateGroupedOrdersTxReq struct {
 	GroupingType uint8
 	Orders       []*CreateOrderTxReq
@@ -104,7 +106,7 @@ type UpdateLeverageTxReq struct {
 
 type UpdateMarginTxReq struct {
 	MarketIndex uint8
-	USDCAmount  int64
+	USDCAmount  USDC
 	Direction   uint8
 }
 
@@ -128,7 +130,7 @@ func ConstructAuthToken(key signer.Signer, deadline time.Time, ops *TransactOpts
 	if err != nil {
 		return "", err
 	}
-	signature := ethCommon.Bytes2Hex(signatureBytes)
+	signature := hex.EncodeToString(signatureBytes)
 
 	return fmt.Sprintf("%v:%v", message, signature), err
 }
@@ -150,7 +152,7 @@ func ConstructChangePubKeyTx(key signer.Signer, lighterChainId uint32, tx *Chang
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -172,7 +174,7 @@ func ConstructCreateSubAccountTx(key signer.Signer, lighterChainId uint32, ops *
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -194,7 +196,7 @@ func ConstructCreatePublicPoolTx(key signer.Signer, lighterChainId uint32, tx *C
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -216,7 +218,7 @@ func ConstructUpdatePublicPoolTx(key signer.Signer, lighterChainId uint32, tx *U
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -238,7 +240,7 @@ func ConstructWithdrawTx(key signer.Signer, lighterChainId uint32, tx *WithdrawT
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -260,7 +262,7 @@ func ConstructCreateOrderTx(key signer.Signer, lighterChainId uint32, tx *Create
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -282,7 +284,7 @@ func ConstructL2CreateGroupedOrdersTx(key signer.Signer, lighterChainId uint32,
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -304,7 +306,7 @@ func ConstructL2CancelOrderTx(key signer.Signer, lighterChainId uint32, tx *Canc
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -326,7 +328,7 @@ func ConstructL2ModifyOrderTx(key signer.Signer, lighterChainId uint32, tx *Modi
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -348,7 +350,7 @@ func ConstructL2CancelAllOrdersTx(key signer.Signer, lighterChainId uint32, tx *
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -370,7 +372,7 @@ func ConstructTransferTx(key signer.Signer, lighterChainId uint32, tx *TransferT
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -392,7 +394,7 @@ func ConstructMintSharesTx(key signer.Signer, lighterChainId uint32, tx *MintSha
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -414,7 +416,7 @@ func ConstructBurnSharesTx(key signer.Signer, lighterChainId uint32, tx *BurnSha
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -436,7 +438,7 @@ func ConstructUpdateLeverageTx(key signer.Signer, lighterChainId uint32, tx *Upd
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -458,7 +460,7 @@ func ConstructUpdateMarginTx(key signer.Signer, lighterChainId uint32, tx *Updat
 		return nil, err
 	}
 
-	convertedTx.SignedHash = ethCommon.Bytes2Hex(msgHash)
+	convertedTx.SignedHash = hex.EncodeToString(msgHash)
 	convertedTx.Sig = signature
 	return convertedTx, nil
 }
@@ -468,8 +470,8 @@ func ConvertTransferTx(tx *TransferTxReq, ops *TransactOpts) *txtypes.L2Transfer
 		FromAccountIndex: *ops.FromAccountIndex,
 		ApiKeyIndex:      *ops.ApiKeyIndex,
 		ToAccountIndex:   tx.ToAccountIndex,
-		USDCAmount:       tx.USDCAmount,
-		Fee:              tx.Fee,
+		USDCAmount:       int64(tx.USDCAmount),
+		Fee:              int64(tx.Fee),
 		Memo:             tx.Memo,
 		ExpiredAt:        ops.ExpiredAt,
 		Nonce:            *ops.Nonce,
@@ -563,7 +565,7 @@ func ConvertWithdrawTx(tx *WithdrawTxReq, ops *TransactOpts) *txtypes.L2Withdraw
 	return &txtypes.L2WithdrawTxInfo{
 		FromAccountIndex: *ops.FromAccountIndex,
 		ApiKeyIndex:      *ops.ApiKeyIndex,
-		USDCAmount:       tx.USDCAmount,
+		USDCAmount:       uint64(tx.USDCAmount), //nolint:gosec // negative amounts wrap above MaxWithdrawalAmount and fail Validate
 		ExpiredAt:        ops.ExpiredAt,
 		Nonce:            *ops.Nonce,
 	}
@@ -652,7 +654,7 @@ func ConvertUpdateMarginTx(tx *UpdateMarginTxReq, ops *TransactOpts) *txtypes.L2
 		AccountIndex: *ops.FromAccountIndex,
 		ApiKeyIndex:  *ops.ApiKeyIndex,
 		MarketIndex:  tx.MarketIndex,
-		USDCAmount:   tx.USDCAmount,
+		USDCAmount:   int64(tx.USDCAmount),
 		Direction:    tx.Direction,
 		ExpiredAt:    ops.ExpiredAt,
 		Nonce:        *ops.Nonce,
@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2BurnSharesTxInfo)(nil)
@@ -87,5 +86,5 @@ func (txInfo *L2BurnSharesTxInfo) Hash(lighterChainId uint32, extra ...g.Element
 	elems = append(elems, g.FromInt64(txInfo.PublicPoolIndex))
 	elems = append(elems, g.FromInt64(txInfo.ShareAmount))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
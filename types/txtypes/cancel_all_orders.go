@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2CancelAllOrdersTxInfo)(nil)
@@ -91,5 +90,5 @@ func (txInfo *L2CancelAllOrdersTxInfo) Hash(lighterChainId uint32, extra ...g.El
 	elems = append(elems, g.FromUint32(uint32(txInfo.TimeInForce)))
 	elems = append(elems, g.FromInt64(txInfo.Time))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2CancelOrderTxInfo)(nil)
@@ -78,7 +77,7 @@ func (txInfo *L2CancelOrderTxInfo) Validate() error {
 }
 
 func (txInfo *L2CancelOrderTxInfo) Hash(lighterChainId uint32, extra ...g.Element) (msgHash []byte, err error) {
-	elems := make([]g.Element, 0, 7)
+	elems := make([]g.Element, 0, 8)
 
 	elems = append(elems, g.FromUint32(lighterChainId))
 	elems = append(elems, g.FromUint32(TxTypeL2CancelOrder))
@@ -90,5 +89,5 @@ func (txInfo *L2CancelOrderTxInfo) Hash(lighterChainId uint32, extra ...g.Elemen
 	elems = append(elems, g.FromUint32(uint32(txInfo.MarketIndex)))
 	elems = append(elems, g.FromInt64(txInfo.Index))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
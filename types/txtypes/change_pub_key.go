@@ -1,34 +1,19 @@
 package txtypes
 
 import (
+	"encoding/hex"
 	"fmt"
-	"strings"
 
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/common/hexutil"
 )
 
 const (
 	templateChangePubKey = "Register Lighter Account\n\npubkey: 0x%s\nnonce: %s\naccount index: %s\napi key index: %s\nOnly sign this message for a trusted client!"
 )
 
+// getHex10FromUint64 encodes value as 0x followed by 16 hex digits
 func getHex10FromUint64(value uint64) string {
-	v := hexutil.EncodeUint64(value)
-	v = strings.Replace(v, "0x", "", 1)
-
-	// Make sure result has fixed bytes
-	vBytes := []byte(v)
-	if len(vBytes) < 16 {
-		toAppend := make([]byte, 16-len(vBytes))
-		for i := range toAppend {
-			toAppend[i] = 48
-		}
-		vBytes = append(toAppend, vBytes...)
-	}
-
-	return fmt.Sprintf("0x%s", string(vBytes))
+	return fmt.Sprintf("0x%016x", value)
 }
 
 var _ TxInfo = (*L2ChangePubKeyTxInfo)(nil)
@@ -93,7 +78,7 @@ func (txInfo *L2ChangePubKeyTxInfo) Validate() error {
 
 func (txInfo *L2ChangePubKeyTxInfo) GetL1SignatureBody() string {
 	signatureBody := fmt.Sprintf(templateChangePubKey,
-		common.Bytes2Hex(txInfo.PubKey),
+		hex.EncodeToString(txInfo.PubKey),
 		getHex10FromUint64(uint64(txInfo.Nonce)),
 		getHex10FromUint64(uint64(txInfo.AccountIndex)),
 		getHex10FromUint64(uint64(txInfo.ApiKeyIndex)),
@@ -117,5 +102,5 @@ func (txInfo *L2ChangePubKeyTxInfo) Hash(lighterChainId uint32, extra ...g.Eleme
 	}
 	elems = append(elems, pubKeyFieldElems...)
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
@@ -0,0 +1,23 @@
+package txtypes
+
+import (
+	"testing"
+)
+
+func TestL1SignatureBody(t *testing.T) {
+	pubKey := make([]byte, 40)
+	for i := range pubKey {
+		pubKey[i] = byte(i + 1)
+	}
+	tx := &L2ChangePubKeyTxInfo{AccountIndex: 100, ApiKeyIndex: 3, PubKey: pubKey, Nonce: 5}
+
+	expected := "Register Lighter Account\n\n" +
+		"pubkey: 0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728\n" +
+		"nonce: 0x0000000000000005\n" +
+		"account index: 0x0000000000000064\n" +
+		"api key index: 0x0000000000000003\n" +
+		"Only sign this message for a trusted client!"
+	if got := tx.GetL1SignatureBody(); got != expected {
+		t.Fatalf("unexpected L1 signature body:\n%s", got)
+	}
+}
@@ -9,6 +9,7 @@ var _ TxInfo = (*L2CreateGroupedOrdersTxInfo)(nil)
 
 // !!! Ensure that if primary order is reduce only, all child orders are also reduce only
 // !!! Otherwise CancelPositionTiedAccountOrders flow breaks
+// Use types.NewOTO, types.NewOCO and types.NewBracketOrder to build requests which follow these rules.
 type L2CreateGroupedOrdersTxInfo struct {
 	AccountIndex int64
 	ApiKeyIndex  uint8
@@ -312,7 +313,7 @@ func (txInfo *L2CreateGroupedOrdersTxInfo) Hash(lighterChainId uint32, extra ...
 
 	aggregatedOrderHash := p2.EmptyHashOut()
 	for index, order := range txInfo.Orders {
-		orderHash := p2.HashNoPad([]g.Element{
+		orderHash := hashNoPad([]g.Element{
 			g.FromUint32(uint32(order.MarketIndex)),
 			g.FromInt64(order.ClientOrderIndex),
 			g.FromInt64(order.BaseAmount),
@@ -327,10 +328,10 @@ func (txInfo *L2CreateGroupedOrdersTxInfo) Hash(lighterChainId uint32, extra ...
 		if index == 0 {
 			aggregatedOrderHash = orderHash
 		} else {
-			aggregatedOrderHash = p2.HashNToOne([]p2.HashOut{aggregatedOrderHash, orderHash})
+			aggregatedOrderHash = hashTwoToOne(aggregatedOrderHash, orderHash)
 		}
 	}
 	elems = append(elems, aggregatedOrderHash[:]...)
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
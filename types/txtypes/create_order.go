@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2CreateOrderTxInfo)(nil)
@@ -182,5 +181,5 @@ func (txInfo *L2CreateOrderTxInfo) Hash(lighterChainId uint32, extra ...g.Elemen
 	elems = append(elems, g.FromUint32(txInfo.TriggerPrice))
 	elems = append(elems, g.FromInt64(txInfo.OrderExpiry))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
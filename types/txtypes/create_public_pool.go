@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2CreatePublicPoolTxInfo)(nil)
@@ -97,5 +96,5 @@ func (txInfo *L2CreatePublicPoolTxInfo) Hash(lighterChainId uint32, extra ...g.E
 	elems = append(elems, g.FromInt64(txInfo.InitialTotalShares))
 	elems = append(elems, g.FromInt64(txInfo.MinOperatorShareRate))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2CreateSubAccountTxInfo)(nil)
@@ -69,5 +68,5 @@ func (txInfo *L2CreateSubAccountTxInfo) Hash(lighterChainId uint32, extra ...g.E
 	elems = append(elems, g.FromInt64(txInfo.AccountIndex))
 	elems = append(elems, g.FromUint32(uint32(txInfo.ApiKeyIndex)))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
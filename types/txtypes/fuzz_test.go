@@ -0,0 +1,616 @@
+package txtypes
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// The fuzz targets check the properties every tx type should have, for any input:
+//   - Validate and Hash don't panic, and Hash succeeds for valid txs
+//   - Hash is deterministic, and depends on the chain id
+//   - valid txs have their common fields (account, api key, nonce, expiry) in range
+//   - the GetTxInfo JSON decodes to a tx with the same hash
+//   - changing a hashed field of a valid tx, while keeping it valid, changes the hash
+//
+// Run a target with e.g. `go test ./types/txtypes -run '^$' -fuzz FuzzL2TransferTxInfo`.
+
+const (
+	fuzzChainId     uint32 = 304
+	fuzzExpiredAt   int64  = 1767225600000
+	fuzzOrderExpiry int64  = 1769817600000
+)
+
+func checkTx[T TxInfo](t *testing.T, tx T, mutations ...func(T)) {
+	t.Helper()
+
+	validateErr := tx.Validate()
+	hash, err := tx.Hash(fuzzChainId)
+	if err != nil {
+		if validateErr == nil {
+			t.Fatalf("Hash failed for a valid tx %+v. err: %v", tx, err)
+		}
+		return
+	}
+	hashAgain, err := tx.Hash(fuzzChainId)
+	if err != nil || !bytes.Equal(hash, hashAgain) {
+		t.Fatalf("Hash is not deterministic for %+v", tx)
+	}
+	if validateErr != nil {
+		return
+	}
+
+	checkCommonFields(t, tx)
+
+	otherChainHash, err := tx.Hash(fuzzChainId + 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(hash, otherChainHash) {
+		t.Fatalf("Hash doesn't depend on the chain id")
+	}
+
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decodedHash, err := decodeTx(t, tx).Hash(fuzzChainId)
+	if err != nil {
+		t.Fatalf("Hash failed for the decoded tx. err: %v", err)
+	}
+	if !bytes.Equal(hash, decodedHash) {
+		t.Fatalf("decoded tx has a different hash. tx info: %s", txInfo)
+	}
+
+	for i, mutate := range mutations {
+		mutated := decodeTx(t, tx)
+		mutate(mutated)
+		if mutated.Validate() != nil {
+			continue
+		}
+		if mutatedTxInfo, err := mutated.GetTxInfo(); err != nil || mutatedTxInfo == txInfo {
+			// the mutation was a no-op for this tx
+			continue
+		}
+		mutatedHash, err := mutated.Hash(fuzzChainId)
+		if err != nil {
+			t.Fatalf("Hash failed for the valid mutated tx %+v. err: %v", mutated, err)
+		}
+		if bytes.Equal(hash, mutatedHash) {
+			t.Fatalf("mutation %d doesn't change the hash.\ntx:      %+v\nmutated: %+v", i, tx, mutated)
+		}
+	}
+}
+
+// decodeTx returns a copy of tx, made by decoding its tx info JSON
+func decodeTx[T TxInfo](t *testing.T, tx T) T {
+	t.Helper()
+
+	txInfo, err := tx.GetTxInfo()
+	if err != nil {
+		t.Fatalf("GetTxInfo failed. err: %v", err)
+	}
+	decoded := reflect.New(reflect.TypeOf(tx).Elem()).Interface().(T)
+	if err := json.Unmarshal([]byte(txInfo), decoded); err != nil {
+		t.Fatalf("failed to decode tx info %s. err: %v", txInfo, err)
+	}
+	return decoded
+}
+
+func checkCommonFields(t *testing.T, tx TxInfo) {
+	t.Helper()
+
+	v := reflect.ValueOf(tx).Elem()
+	accountIndex := v.FieldByName("AccountIndex")
+	if !accountIndex.IsValid() {
+		accountIndex = v.FieldByName("FromAccountIndex")
+	}
+	if accountIndex.Int() < MinAccountIndex || accountIndex.Int() > MaxAccountIndex {
+		t.Fatalf("valid tx has account index %d out of range", accountIndex.Int())
+	}
+	apiKeyIndex := uint8(v.FieldByName("ApiKeyIndex").Uint())
+	if _, ok := tx.(*L2CancelAllOrdersTxInfo); ok && apiKeyIndex == NilApiKeyIndex {
+		// cancel all can be sent without an api key
+		apiKeyIndex = MinApiKeyIndex
+	}
+	if apiKeyIndex > MaxApiKeyIndex {
+		t.Fatalf("valid tx has api key index %d out of range", apiKeyIndex)
+	}
+	if nonce := v.FieldByName("Nonce").Int(); nonce < MinNonce {
+		t.Fatalf("valid tx has nonce %d out of range", nonce)
+	}
+	if expiredAt := v.FieldByName("ExpiredAt").Int(); expiredAt < 0 || expiredAt > MaxTimestamp {
+		t.Fatalf("valid tx has ExpiredAt %d out of range", expiredAt)
+	}
+}
+
+func FuzzL2TransferTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), int64(2000), int64(25_500_000), int64(0), int64(42), fuzzExpiredAt, int64(1<<32))
+	f.Add(int64(1), uint8(0), int64(1), int64(1), int64(1), int64(0), int64(0), int64(1<<32+1))
+	f.Add(int64(MaxAccountIndex), MaxApiKeyIndex, int64(MaxAccountIndex), MaxTransferAmount, MaxTransferAmount, int64(1<<47), int64(MaxTimestamp), int64(-1))
+
+	f.Fuzz(func(t *testing.T, from int64, apiKey uint8, to int64, amount, fee, nonce, expiredAt, otherAmount int64) {
+		tx := &L2TransferTxInfo{
+			FromAccountIndex: from,
+			ApiKeyIndex:      apiKey,
+			ToAccountIndex:   to,
+			USDCAmount:       amount,
+			Fee:              fee,
+			ExpiredAt:        expiredAt,
+			Nonce:            nonce,
+		}
+		if tx.Validate() == nil {
+			if amount < MinTransferAmount || amount > MaxTransferAmount || fee < 0 || fee > MaxTransferAmount {
+				t.Fatalf("valid transfer has amount %d or fee %d out of range", amount, fee)
+			}
+			if to < MinAccountIndex+1 || to > MaxAccountIndex {
+				t.Fatalf("valid transfer has ToAccountIndex %d out of range", to)
+			}
+		}
+
+		// the Memo is not part of the hash
+		checkTx(t, tx,
+			func(tx *L2TransferTxInfo) { tx.FromAccountIndex++ },
+			func(tx *L2TransferTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2TransferTxInfo) { tx.ToAccountIndex++ },
+			func(tx *L2TransferTxInfo) { tx.USDCAmount++ },
+			func(tx *L2TransferTxInfo) { tx.USDCAmount ^= 1 << 32 },
+			func(tx *L2TransferTxInfo) { tx.USDCAmount = otherAmount },
+			func(tx *L2TransferTxInfo) { tx.Fee++ },
+			func(tx *L2TransferTxInfo) { tx.Fee ^= 1 << 32 },
+			func(tx *L2TransferTxInfo) { tx.Fee, tx.USDCAmount = tx.USDCAmount, tx.Fee },
+			func(tx *L2TransferTxInfo) { tx.Nonce++ },
+			func(tx *L2TransferTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2WithdrawTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint64(100_000_000), int64(42), fuzzExpiredAt, uint64(1<<32))
+	f.Add(int64(MaxAccountIndex), MaxApiKeyIndex, MaxWithdrawalAmount, int64(0), int64(MaxTimestamp), uint64(1<<63))
+
+	f.Fuzz(func(t *testing.T, from int64, apiKey uint8, amount uint64, nonce, expiredAt int64, otherAmount uint64) {
+		tx := &L2WithdrawTxInfo{
+			FromAccountIndex: from,
+			ApiKeyIndex:      apiKey,
+			USDCAmount:       amount,
+			ExpiredAt:        expiredAt,
+			Nonce:            nonce,
+		}
+		if tx.Validate() == nil && (amount < MinWithdrawalAmount || amount > MaxWithdrawalAmount) {
+			t.Fatalf("valid withdraw has amount %d out of range", amount)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2WithdrawTxInfo) { tx.FromAccountIndex++ },
+			func(tx *L2WithdrawTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2WithdrawTxInfo) { tx.USDCAmount++ },
+			func(tx *L2WithdrawTxInfo) { tx.USDCAmount ^= 1 << 32 },
+			func(tx *L2WithdrawTxInfo) { tx.USDCAmount = otherAmount },
+			func(tx *L2WithdrawTxInfo) { tx.Nonce++ },
+			func(tx *L2WithdrawTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2UpdateMarginTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(1), int64(10_000_000), uint8(1), int64(42), fuzzExpiredAt, int64(1<<32))
+	f.Add(int64(0), uint8(0), MaxMarketIndex, MaxTransferAmount, uint8(0), int64(0), int64(0), int64(-1))
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, market uint8, amount int64, direction uint8, nonce, expiredAt, otherAmount int64) {
+		tx := &L2UpdateMarginTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			MarketIndex:  market,
+			USDCAmount:   amount,
+			Direction:    direction,
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+		if tx.Validate() == nil && (amount <= 0 || amount > MaxTransferAmount) {
+			t.Fatalf("valid update margin has amount %d out of range", amount)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2UpdateMarginTxInfo) { tx.AccountIndex++ },
+			func(tx *L2UpdateMarginTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2UpdateMarginTxInfo) { tx.MarketIndex++ },
+			func(tx *L2UpdateMarginTxInfo) { tx.USDCAmount++ },
+			func(tx *L2UpdateMarginTxInfo) { tx.USDCAmount ^= 1 << 32 },
+			func(tx *L2UpdateMarginTxInfo) { tx.USDCAmount = otherAmount },
+			func(tx *L2UpdateMarginTxInfo) { tx.Direction ^= 1 },
+			func(tx *L2UpdateMarginTxInfo) { tx.Nonce++ },
+			func(tx *L2UpdateMarginTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2ChangePubKeyTxInfo(f *testing.F) {
+	pubKey := bytes.Repeat([]byte{1}, 40)
+	f.Add(int64(100), uint8(3), pubKey, int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), bytes.Repeat([]byte{0xff}, 40), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), []byte{}, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey uint8, pubKey []byte, nonce, expiredAt int64) {
+		tx := &L2ChangePubKeyTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			PubKey:       pubKey,
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+
+		checkTx(t, tx,
+			func(tx *L2ChangePubKeyTxInfo) { tx.AccountIndex++ },
+			func(tx *L2ChangePubKeyTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2ChangePubKeyTxInfo) { tx.PubKey[0] ^= 1 },
+			func(tx *L2ChangePubKeyTxInfo) { tx.PubKey[39] ^= 1 },
+			func(tx *L2ChangePubKeyTxInfo) { tx.Nonce++ },
+			func(tx *L2ChangePubKeyTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2CreateSubAccountTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), int64(42), fuzzExpiredAt)
+	f.Add(int64(-1), uint8(255), int64(-1), int64(-1))
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey uint8, nonce, expiredAt int64) {
+		tx := &L2CreateSubAccountTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+
+		checkTx(t, tx,
+			func(tx *L2CreateSubAccountTxInfo) { tx.AccountIndex++ },
+			func(tx *L2CreateSubAccountTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2CreateSubAccountTxInfo) { tx.Nonce++ },
+			func(tx *L2CreateSubAccountTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2CreatePublicPoolTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), int64(100_000), 2*MinInitialTotalShares, int64(500), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), FeeTick, MaxInitialTotalShares, ShareTick, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey uint8, operatorFee, initialTotalShares, minOperatorShareRate, nonce, expiredAt int64) {
+		tx := &L2CreatePublicPoolTxInfo{
+			AccountIndex:         account,
+			ApiKeyIndex:          apiKey,
+			OperatorFee:          operatorFee,
+			InitialTotalShares:   initialTotalShares,
+			MinOperatorShareRate: minOperatorShareRate,
+			ExpiredAt:            expiredAt,
+			Nonce:                nonce,
+		}
+		if tx.Validate() == nil {
+			if operatorFee < 0 || operatorFee > FeeTick || minOperatorShareRate < 0 || minOperatorShareRate > ShareTick {
+				t.Fatalf("valid create public pool has operator fee %d or share rate %d out of range", operatorFee, minOperatorShareRate)
+			}
+			if initialTotalShares < MinInitialTotalShares || initialTotalShares > MaxInitialTotalShares {
+				t.Fatalf("valid create public pool has initial total shares %d out of range", initialTotalShares)
+			}
+		}
+
+		checkTx(t, tx,
+			func(tx *L2CreatePublicPoolTxInfo) { tx.AccountIndex++ },
+			func(tx *L2CreatePublicPoolTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2CreatePublicPoolTxInfo) { tx.OperatorFee++ },
+			func(tx *L2CreatePublicPoolTxInfo) { tx.InitialTotalShares++ },
+			func(tx *L2CreatePublicPoolTxInfo) { tx.MinOperatorShareRate++ },
+			func(tx *L2CreatePublicPoolTxInfo) { tx.Nonce++ },
+			func(tx *L2CreatePublicPoolTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2UpdatePublicPoolTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), int64(1000), uint8(1), int64(50_000), int64(1000), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), int64(1000), uint8(2), int64(-1), int64(-1), int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey uint8, pool int64, status uint8, operatorFee, minOperatorShareRate, nonce, expiredAt int64) {
+		tx := &L2UpdatePublicPoolTxInfo{
+			AccountIndex:         account,
+			ApiKeyIndex:          apiKey,
+			PublicPoolIndex:      pool,
+			Status:               status,
+			OperatorFee:          operatorFee,
+			MinOperatorShareRate: minOperatorShareRate,
+			ExpiredAt:            expiredAt,
+			Nonce:                nonce,
+		}
+		if tx.Validate() == nil && status != 0 && status != 1 {
+			t.Fatalf("valid update public pool has status %d", status)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.AccountIndex++ },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.PublicPoolIndex++ },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.Status ^= 1 },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.OperatorFee++ },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.MinOperatorShareRate++ },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.Nonce++ },
+			func(tx *L2UpdatePublicPoolTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2MintSharesTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), int64(1000), int64(5000), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), int64(1000), MaxPoolSharesToMintOrBurn, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey uint8, pool, shares, nonce, expiredAt int64) {
+		tx := &L2MintSharesTxInfo{
+			AccountIndex:    account,
+			ApiKeyIndex:     apiKey,
+			PublicPoolIndex: pool,
+			ShareAmount:     shares,
+			ExpiredAt:       expiredAt,
+			Nonce:           nonce,
+		}
+		if tx.Validate() == nil && (shares < MinPoolSharesToMintOrBurn || shares > MaxPoolSharesToMintOrBurn) {
+			t.Fatalf("valid mint shares has share amount %d out of range", shares)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2MintSharesTxInfo) { tx.AccountIndex++ },
+			func(tx *L2MintSharesTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2MintSharesTxInfo) { tx.PublicPoolIndex++ },
+			func(tx *L2MintSharesTxInfo) { tx.ShareAmount++ },
+			func(tx *L2MintSharesTxInfo) { tx.Nonce++ },
+			func(tx *L2MintSharesTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2BurnSharesTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), int64(1000), int64(5000), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), int64(1000), MaxPoolSharesToMintOrBurn, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey uint8, pool, shares, nonce, expiredAt int64) {
+		tx := &L2BurnSharesTxInfo{
+			AccountIndex:    account,
+			ApiKeyIndex:     apiKey,
+			PublicPoolIndex: pool,
+			ShareAmount:     shares,
+			ExpiredAt:       expiredAt,
+			Nonce:           nonce,
+		}
+		if tx.Validate() == nil && (shares < MinPoolSharesToMintOrBurn || shares > MaxPoolSharesToMintOrBurn) {
+			t.Fatalf("valid burn shares has share amount %d out of range", shares)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2BurnSharesTxInfo) { tx.AccountIndex++ },
+			func(tx *L2BurnSharesTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2BurnSharesTxInfo) { tx.PublicPoolIndex++ },
+			func(tx *L2BurnSharesTxInfo) { tx.ShareAmount++ },
+			func(tx *L2BurnSharesTxInfo) { tx.Nonce++ },
+			func(tx *L2BurnSharesTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2UpdateLeverageTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(1), uint16(500), uint8(IsolatedMargin), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(1), uint16(0), uint8(2), int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, market uint8, imf uint16, marginMode uint8, nonce, expiredAt int64) {
+		tx := &L2UpdateLeverageTxInfo{
+			AccountIndex:          account,
+			ApiKeyIndex:           apiKey,
+			MarketIndex:           market,
+			InitialMarginFraction: imf,
+			MarginMode:            marginMode,
+			ExpiredAt:             expiredAt,
+			Nonce:                 nonce,
+		}
+		if tx.Validate() == nil && market > MaxMarketIndex {
+			t.Fatalf("valid update leverage has market index %d out of range", market)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2UpdateLeverageTxInfo) { tx.AccountIndex++ },
+			func(tx *L2UpdateLeverageTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2UpdateLeverageTxInfo) { tx.MarketIndex++ },
+			func(tx *L2UpdateLeverageTxInfo) { tx.InitialMarginFraction++ },
+			func(tx *L2UpdateLeverageTxInfo) { tx.MarginMode ^= 1 },
+			func(tx *L2UpdateLeverageTxInfo) { tx.Nonce++ },
+			func(tx *L2UpdateLeverageTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2CancelOrderTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(1), int64(12345), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(1), MaxOrderIndex, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, market uint8, index, nonce, expiredAt int64) {
+		tx := &L2CancelOrderTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			MarketIndex:  market,
+			Index:        index,
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+		if tx.Validate() == nil && (index < MinClientOrderIndex || index > MaxOrderIndex) {
+			t.Fatalf("valid cancel order has index %d out of range", index)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2CancelOrderTxInfo) { tx.AccountIndex++ },
+			func(tx *L2CancelOrderTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2CancelOrderTxInfo) { tx.MarketIndex++ },
+			func(tx *L2CancelOrderTxInfo) { tx.Index++ },
+			func(tx *L2CancelOrderTxInfo) { tx.Nonce++ },
+			func(tx *L2CancelOrderTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2CancelAllOrdersTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(ScheduledCancelAll), fuzzExpiredAt+MinOrderCancelAllPeriod, int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(ImmediateCancelAll), int64(0), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(AbortScheduledCancelAll), int64(1), int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, timeInForce uint8, cancelTime, nonce, expiredAt int64) {
+		tx := &L2CancelAllOrdersTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			TimeInForce:  timeInForce,
+			Time:         cancelTime,
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+		if tx.Validate() == nil && timeInForce > AbortScheduledCancelAll {
+			t.Fatalf("valid cancel all has time in force %d", timeInForce)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2CancelAllOrdersTxInfo) { tx.AccountIndex++ },
+			func(tx *L2CancelAllOrdersTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2CancelAllOrdersTxInfo) { tx.TimeInForce++ },
+			func(tx *L2CancelAllOrdersTxInfo) { tx.Time++ },
+			func(tx *L2CancelAllOrdersTxInfo) { tx.Nonce++ },
+			func(tx *L2CancelAllOrdersTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2ModifyOrderTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(1), int64(12345), int64(2000), uint32(351000), uint32(0), int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(1), MaxOrderIndex, MaxOrderBaseAmount, MaxOrderPrice, MaxOrderTriggerPrice, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, market uint8, index, baseAmount int64, price, triggerPrice uint32, nonce, expiredAt int64) {
+		tx := &L2ModifyOrderTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			MarketIndex:  market,
+			Index:        index,
+			BaseAmount:   baseAmount,
+			Price:        price,
+			TriggerPrice: triggerPrice,
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+		if tx.Validate() == nil && (baseAmount < 0 || baseAmount > MaxOrderBaseAmount || price < MinOrderPrice) {
+			t.Fatalf("valid modify order has base amount %d or price %d out of range", baseAmount, price)
+		}
+
+		checkTx(t, tx,
+			func(tx *L2ModifyOrderTxInfo) { tx.AccountIndex++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.MarketIndex++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.Index++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.BaseAmount++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.Price++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.TriggerPrice++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.Nonce++ },
+			func(tx *L2ModifyOrderTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2CreateOrderTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(1), int64(12345), int64(1000), uint32(350000), uint8(0), uint8(LimitOrder), uint8(GoodTillTime), uint8(0), uint32(0), fuzzOrderExpiry, int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(1), int64(0), int64(1000), uint32(330000), uint8(1), uint8(StopLossOrder), uint8(ImmediateOrCancel), uint8(1), uint32(335000), fuzzOrderExpiry, int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(1), int64(0), int64(1000), uint32(350000), uint8(0), uint8(MarketOrder), uint8(ImmediateOrCancel), uint8(0), uint32(0), int64(0), int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, market uint8, clientOrderIndex, baseAmount int64, price uint32, isAsk, orderType, timeInForce, reduceOnly uint8, triggerPrice uint32, orderExpiry, nonce, expiredAt int64) {
+		tx := &L2CreateOrderTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			OrderInfo: &OrderInfo{
+				MarketIndex:      market,
+				ClientOrderIndex: clientOrderIndex,
+				BaseAmount:       baseAmount,
+				Price:            price,
+				IsAsk:            isAsk,
+				Type:             orderType,
+				TimeInForce:      timeInForce,
+				ReduceOnly:       reduceOnly,
+				TriggerPrice:     triggerPrice,
+				OrderExpiry:      orderExpiry,
+			},
+			ExpiredAt: expiredAt,
+			Nonce:     nonce,
+		}
+		if tx.Validate() == nil {
+			if isAsk > 1 || reduceOnly > 1 || orderType > ApiMaxOrderType || timeInForce > PostOnly {
+				t.Fatalf("valid create order has an invalid enum value %+v", tx.OrderInfo)
+			}
+			if clientOrderIndex != NilClientOrderIndex && (clientOrderIndex < MinClientOrderIndex || clientOrderIndex > MaxClientOrderIndex) {
+				t.Fatalf("valid create order has client order index %d out of range", clientOrderIndex)
+			}
+		}
+
+		checkTx(t, tx,
+			func(tx *L2CreateOrderTxInfo) { tx.AccountIndex++ },
+			func(tx *L2CreateOrderTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2CreateOrderTxInfo) { tx.MarketIndex++ },
+			func(tx *L2CreateOrderTxInfo) { tx.ClientOrderIndex++ },
+			func(tx *L2CreateOrderTxInfo) { tx.BaseAmount++ },
+			func(tx *L2CreateOrderTxInfo) { tx.Price++ },
+			func(tx *L2CreateOrderTxInfo) { tx.IsAsk ^= 1 },
+			func(tx *L2CreateOrderTxInfo) { tx.Type++ },
+			func(tx *L2CreateOrderTxInfo) { tx.TimeInForce++ },
+			func(tx *L2CreateOrderTxInfo) { tx.ReduceOnly ^= 1 },
+			func(tx *L2CreateOrderTxInfo) { tx.TriggerPrice++ },
+			func(tx *L2CreateOrderTxInfo) { tx.OrderExpiry++ },
+			func(tx *L2CreateOrderTxInfo) { tx.Nonce++ },
+			func(tx *L2CreateOrderTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
+
+func FuzzL2CreateGroupedOrdersTxInfo(f *testing.F) {
+	f.Add(int64(100), uint8(3), uint8(GroupingType_OneTriggersTheOther), uint8(2), uint8(1), int64(1000), uint32(350000), uint8(0), uint8(LimitOrder), uint32(335000), fuzzOrderExpiry, int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(GroupingType_OneTriggersAOneCancelsTheOther), uint8(3), uint8(1), int64(1000), uint32(350000), uint8(1), uint8(LimitOrder), uint32(365000), fuzzOrderExpiry, int64(42), fuzzExpiredAt)
+	f.Add(int64(100), uint8(3), uint8(GroupingType_OneCancelsTheOther), uint8(2), uint8(1), int64(1000), uint32(350000), uint8(0), uint8(StopLossOrder), uint32(335000), fuzzOrderExpiry, int64(42), fuzzExpiredAt)
+
+	f.Fuzz(func(t *testing.T, account int64, apiKey, groupingType, count, market uint8, baseAmount int64, price uint32, isAsk, parentType uint8, triggerPrice uint32, orderExpiry, nonce, expiredAt int64) {
+		// the first order is the parent, followed by a stop loss & a take profit closing it
+		orders := []*OrderInfo{
+			{MarketIndex: market, BaseAmount: baseAmount, Price: price, IsAsk: isAsk, Type: parentType, TimeInForce: GoodTillTime, TriggerPrice: triggerPrice, OrderExpiry: orderExpiry},
+			{MarketIndex: market, Price: price, IsAsk: isAsk ^ 1, Type: StopLossOrder, TimeInForce: ImmediateOrCancel, ReduceOnly: 1, TriggerPrice: triggerPrice, OrderExpiry: orderExpiry},
+			{MarketIndex: market, Price: price, IsAsk: isAsk ^ 1, Type: TakeProfitOrder, TimeInForce: ImmediateOrCancel, ReduceOnly: 1, TriggerPrice: triggerPrice, OrderExpiry: orderExpiry},
+		}
+		if groupingType == GroupingType_OneCancelsTheOther {
+			// OCO orders are both reduce only, on the same side and with the same size
+			orders = []*OrderInfo{
+				{MarketIndex: market, BaseAmount: baseAmount, Price: price, IsAsk: isAsk, Type: parentType, TimeInForce: ImmediateOrCancel, ReduceOnly: 1, TriggerPrice: triggerPrice, OrderExpiry: orderExpiry},
+				{MarketIndex: market, BaseAmount: baseAmount, Price: price, IsAsk: isAsk, Type: TakeProfitOrder, TimeInForce: ImmediateOrCancel, ReduceOnly: 1, TriggerPrice: triggerPrice, OrderExpiry: orderExpiry},
+			}
+		}
+		tx := &L2CreateGroupedOrdersTxInfo{
+			AccountIndex: account,
+			ApiKeyIndex:  apiKey,
+			GroupingType: groupingType,
+			Orders:       orders[:min(int(count), len(orders))],
+			ExpiredAt:    expiredAt,
+			Nonce:        nonce,
+		}
+		if tx.Validate() == nil && (len(tx.Orders) == 0 || len(tx.Orders) > int(MaxGroupedOrderCount)) {
+			t.Fatalf("valid grouped orders has %d orders", len(tx.Orders))
+		}
+
+		checkTx(t, tx,
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.AccountIndex++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.ApiKeyIndex++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.GroupingType++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.Orders[0].BaseAmount++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.Orders[0].Price++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.Orders[len(tx.Orders)-1].TriggerPrice++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.Orders[0], tx.Orders[1] = tx.Orders[1], tx.Orders[0] },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.Orders = tx.Orders[:len(tx.Orders)-1] },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.Nonce++ },
+			func(tx *L2CreateGroupedOrdersTxInfo) { tx.ExpiredAt++ },
+		)
+	})
+}
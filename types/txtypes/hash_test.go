@@ -0,0 +1,63 @@
+package txtypes
+
+import (
+	"bytes"
+	"testing"
+
+	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
+	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
+)
+
+// TestHashAllocations checks the elements hashed by the txs stay on the stack, so signing many txs doesn't
+// create garbage. The returned hash is the only allocation, except for ChangePubKey which parses the key.
+func TestHashAllocations(t *testing.T) {
+	order := &OrderInfo{MarketIndex: 1, ClientOrderIndex: 7, BaseAmount: 1000, Price: 300000, OrderExpiry: 1}
+	txs := map[string]interface {
+		Hash(lighterChainId uint32, extra ...g.Element) ([]byte, error)
+	}{
+		"burn_shares":           &L2BurnSharesTxInfo{},
+		"cancel_all_orders":     &L2CancelAllOrdersTxInfo{},
+		"cancel_order":          &L2CancelOrderTxInfo{},
+		"create_grouped_orders": &L2CreateGroupedOrdersTxInfo{Orders: []*OrderInfo{order, order, order}},
+		"create_order":          &L2CreateOrderTxInfo{OrderInfo: order},
+		"create_public_pool":    &L2CreatePublicPoolTxInfo{},
+		"create_sub_account":    &L2CreateSubAccountTxInfo{},
+		"mint_shares":           &L2MintSharesTxInfo{},
+		"modify_order":          &L2ModifyOrderTxInfo{},
+		"transfer":              &L2TransferTxInfo{},
+		"update_leverage":       &L2UpdateLeverageTxInfo{},
+		"update_margin":         &L2UpdateMarginTxInfo{},
+		"update_public_pool":    &L2UpdatePublicPoolTxInfo{},
+		"withdraw":              &L2WithdrawTxInfo{},
+	}
+
+	for name, tx := range txs {
+		allocs := testing.AllocsPerRun(10, func() {
+			if _, err := tx.Hash(304); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs != 1 {
+			t.Errorf("%s: expected 1 allocation, got %v", name, allocs)
+		}
+	}
+}
+
+func TestHashNoPad(t *testing.T) {
+	elems := make([]g.Element, 0, 20)
+	for i := 0; i < cap(elems); i++ {
+		// every length up to 20 covers empty, partial and multiple rates
+		if a, b := hashNoPad(elems), p2.HashNoPad(elems); a != b {
+			t.Errorf("hashNoPad of %d elements: expected %v, got %v", len(elems), b, a)
+		}
+		if a, b := hashToQuinticExtensionBytes(elems), p2.HashToQuinticExtension(elems).ToLittleEndianBytes(); !bytes.Equal(a, b) {
+			t.Errorf("hashToQuinticExtensionBytes of %d elements: expected %x, got %x", len(elems), b, a)
+		}
+		elems = append(elems, g.FromUint64(uint64(i)*0x9e3779b97f4a7c15))
+	}
+
+	a, b := p2.HashNoPad(elems[:4]), p2.HashNoPad(elems[4:])
+	if got, expected := hashTwoToOne(a, b), p2.HashNToOne([]p2.HashOut{a, b}); got != expected {
+		t.Errorf("hashTwoToOne: expected %v, got %v", expected, got)
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"strings"
 
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 const templateTransfer = "Transfer\n\nnonce: %s\nfrom: %s\napi key: %s\nto: %s\namount: %s\nfee: %s\nmemo: %s\nOnly sign this message for a trusted client!"
@@ -106,7 +105,7 @@ func (txInfo *L2TransferTxInfo) Hash(lighterChainId uint32, extra ...g.Element)
 	elems = append(elems, g.FromUint64(uint64(txInfo.Fee)&0xFFFFFFFF))        //nolint:gosec
 	elems = append(elems, g.FromUint64(uint64(txInfo.Fee)>>32))               //nolint:gosec
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
 
 func (txInfo *L2TransferTxInfo) GetL1SignatureBody() string {
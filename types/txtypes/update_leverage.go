@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2UpdateLeverageTxInfo)(nil)
@@ -94,5 +93,5 @@ func (txInfo *L2UpdateLeverageTxInfo) Hash(lighterChainId uint32, extra ...g.Ele
 	elems = append(elems, g.FromInt64(int64(txInfo.InitialMarginFraction)))
 	elems = append(elems, g.FromUint32(uint32(txInfo.MarginMode)))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
@@ -2,7 +2,6 @@ package txtypes
 
 import (
 	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
-	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
 )
 
 var _ TxInfo = (*L2UpdateMarginTxInfo)(nil)
@@ -94,5 +93,5 @@ func (txInfo *L2UpdateMarginTxInfo) Hash(lighterChainId uint32, extra ...g.Eleme
 	elems = append(elems, g.FromUint64(uint64(txInfo.USDCAmount)>>32))        //nolint:gosec
 	elems = append(elems, g.FromUint32(uint32(txInfo.Direction)))
 
-	return p2.HashToQuinticExtension(elems).ToLittleEndianBytes(), nil
+	return hashToQuinticExtensionBytes(elems), nil
 }
@@ -1,15 +1,62 @@
 package txtypes
 
-import "encoding/json"
+import (
+	"encoding/binary"
+	"encoding/json"
 
+	g "github.com/elliottech/poseidon_crypto/field/goldilocks"
+	gFp5 "github.com/elliottech/poseidon_crypto/field/goldilocks_quintic_extension"
+	p2 "github.com/elliottech/poseidon_crypto/hash/poseidon2_goldilocks"
+)
+
+// IsValidPubKey checks the key is 40 bytes, not zero, and made of canonical field elements, so it can be hashed
 func IsValidPubKey(bytes []byte) bool {
 	if len(bytes) != 40 {
 		return false
 	}
+	if _, err := g.ArrayFromCanonicalLittleEndianBytes(bytes); err != nil {
+		return false
+	}
 
 	return !isZeroByteSlice(bytes)
 }
 
+// hashToQuinticExtensionBytes returns the same bytes as p2.HashToQuinticExtension(elems).ToLittleEndianBytes().
+// The sponge state lives on the stack, and the 40 bytes of the hash are the only allocation.
+func hashToQuinticExtensionBytes(elems []g.Element) []byte {
+	perm := absorb(elems)
+
+	// a quintic extension element fits in the rate, so it's squeezed without another permutation
+	res := make([]byte, gFp5.Bytes)
+	for i := 0; i < gFp5.Bytes/g.Bytes; i++ {
+		binary.LittleEndian.PutUint64(res[i*g.Bytes:], perm[i].Uint64())
+	}
+	return res
+}
+
+// hashNoPad returns the same hash as p2.HashNoPad(elems), without allocating
+func hashNoPad(elems []g.Element) p2.HashOut {
+	perm := absorb(elems)
+	return p2.HashOut{perm[0], perm[1], perm[2], perm[3]}
+}
+
+// hashTwoToOne returns the same hash as p2.HashTwoToOne(a, b), without allocating
+func hashTwoToOne(a, b p2.HashOut) p2.HashOut {
+	return hashNoPad([]g.Element{a[0], a[1], a[2], a[3], b[0], b[1], b[2], b[3]})
+}
+
+// absorb runs the sponge over elems, and returns its state, from which the hash is squeezed
+func absorb(elems []g.Element) [p2.WIDTH]g.Element {
+	var perm [p2.WIDTH]g.Element
+	for i := 0; i < len(elems); i += p2.RATE {
+		for j := 0; j < p2.RATE && i+j < len(elems); j++ {
+			perm[j].Set(&elems[i+j])
+		}
+		p2.Permute(&perm)
+	}
+	return perm
+}
+
 func isZeroByteSlice(bytes []byte) bool {
 	for _, s := range bytes {
 		if s != 0 {
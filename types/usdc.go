@@ -0,0 +1,150 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/elliottech/lighter-go/types/txtypes"
+)
+
+// USDC is an amount of USDC with 6 implied decimals, i.e. 1_000_000 is 1 USDC.
+// Use ParseUSDC("1.5") or 15 * OneUSDC / 10 rather than raw integers, so amounts can't be off by a factor of OneUSDC.
+//
+// In JSON, a USDC is a decimal string like "1.5". JSON numbers are rejected, as "500000" and 500000 would
+// otherwise mean different amounts depending on whether the number is read as USDC or with 6 implied decimals.
+type USDC int64
+
+const (
+	OneUSDC USDC = txtypes.OneUSDC
+	MaxUSDC USDC = txtypes.MaxExchangeUSDC
+
+	usdcDecimals = 6
+)
+
+// ParseUSDC parses a decimal amount like "123.45" or "-0.000001".
+// More than 6 decimals are rejected rather than rounded.
+func ParseUSDC(s string) (USDC, error) {
+	str := strings.TrimSpace(s)
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(str, "-")
+
+	whole, frac, hasFrac := strings.Cut(str, ".")
+	if whole == "" || (hasFrac && frac == "") || strings.HasPrefix(whole, "+") {
+		return 0, fmt.Errorf("invalid USDC amount %q", s)
+	}
+	if len(frac) > usdcDecimals {
+		return 0, fmt.Errorf("USDC amount %q has more than %d decimals", s, usdcDecimals)
+	}
+
+	wholeAmount, err := strconv.ParseUint(whole, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid USDC amount %q. err: %w", s, err)
+	}
+	fracAmount := uint64(0)
+	if frac != "" {
+		fracAmount, err = strconv.ParseUint(frac+strings.Repeat("0", usdcDecimals-len(frac)), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid USDC amount %q. err: %w", s, err)
+		}
+	}
+	if wholeAmount > (uint64(MaxUSDC)-fracAmount)/uint64(OneUSDC) {
+		return 0, fmt.Errorf("USDC amount %q is larger than %s", s, MaxUSDC)
+	}
+
+	amount := USDC(wholeAmount*uint64(OneUSDC) + fracAmount)
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
+}
+
+// String formats the amount as a decimal, without trailing zeros, e.g. "1.5" or "-3"
+func (u USDC) String() string {
+	abs := uint64(u)
+	sign := ""
+	if u < 0 {
+		abs = uint64(-u)
+		sign = "-"
+	}
+
+	whole := strconv.FormatUint(abs/uint64(OneUSDC), 10)
+	frac := strings.TrimRight(fmt.Sprintf("%06d", abs%uint64(OneUSDC)), "0")
+	if frac == "" {
+		return sign + whole
+	}
+	return sign + whole + "." + frac
+}
+
+// Validate checks the amount is within [-MaxUSDC, MaxUSDC]
+func (u USDC) Validate() error {
+	if u > MaxUSDC || u < -MaxUSDC {
+		return fmt.Errorf("USDC amount %d is out of range, should be within %s", int64(u), MaxUSDC)
+	}
+	return nil
+}
+
+// Add returns u + o, or an error if the result is out of range
+func (u USDC) Add(o USDC) (USDC, error) {
+	if err := u.Validate(); err != nil {
+		return 0, err
+	}
+	if err := o.Validate(); err != nil {
+		return 0, err
+	}
+	// both are bounded by 2^60, so the sum can't overflow
+	ret := u + o
+	return ret, ret.Validate()
+}
+
+// Sub returns u - o, or an error if the result is out of range
+func (u USDC) Sub(o USDC) (USDC, error) {
+	return u.Add(-o)
+}
+
+// Mul returns u * n, or an error if the result is out of range
+func (u USDC) Mul(n int64) (USDC, error) {
+	if err := u.Validate(); err != nil {
+		return 0, err
+	}
+	if u == 0 || n == 0 {
+		return 0, nil
+	}
+	if n > int64(MaxUSDC) || n < -int64(MaxUSDC) {
+		return 0, fmt.Errorf("USDC multiplier %d is out of range", n)
+	}
+
+	absU, absN := int64(u), n
+	if absU < 0 {
+		absU = -absU
+	}
+	if absN < 0 {
+		absN = -absN
+	}
+	if absU > int64(MaxUSDC)/absN {
+		return 0, fmt.Errorf("USDC amount %s * %d is out of range", u, n)
+	}
+	return u * USDC(n), nil
+}
+
+func (u USDC) MarshalJSON() ([]byte, error) {
+	return json.Marshal(u.String())
+}
+
+func (u *USDC) UnmarshalJSON(data []byte) error {
+	if !bytes.HasPrefix(data, []byte(`"`)) {
+		return fmt.Errorf("USDC should be a decimal string like \"12.5\", got %s", data)
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	amount, err := ParseUSDC(s)
+	if err != nil {
+		return err
+	}
+	*u = amount
+	return nil
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseUSDC(t *testing.T) {
+	for _, tc := range []struct {
+		s        string
+		expected USDC
+	}{
+		{"0", 0},
+		{"1", OneUSDC},
+		{"1.5", 1_500_000},
+		{"0.000001", 1},
+		{"12.50", 12_500_000},
+		{" 3 ", 3 * OneUSDC},
+		{"-0.000001", -1},
+		{"-2.25", -2_250_000},
+		{"1152921504606.846975", MaxUSDC},
+		{"-1152921504606.846975", -MaxUSDC},
+	} {
+		amount, err := ParseUSDC(tc.s)
+		if err != nil {
+			t.Errorf("ParseUSDC(%q): %v", tc.s, err)
+			continue
+		}
+		if amount != tc.expected {
+			t.Errorf("ParseUSDC(%q): expected %d, got %d", tc.s, tc.expected, amount)
+		}
+	}
+
+	for _, s := range []string{
+		"",
+		"-",
+		".5",
+		"1.",
+		"+1",
+		"1e6",
+		"1,5",
+		"0x10",
+		"1.0000001", // not rounded
+		"0.0000005",
+		"1152921504606.846976", // above MaxUSDC
+		"18446744073709.551616",
+		"99999999999999999999",
+	} {
+		if amount, err := ParseUSDC(s); err == nil {
+			t.Errorf("ParseUSDC(%q): expected an error, got %d", s, amount)
+		}
+	}
+}
+
+func TestUSDCString(t *testing.T) {
+	for _, tc := range []struct {
+		amount   USDC
+		expected string
+	}{
+		{0, "0"},
+		{1, "0.000001"},
+		{OneUSDC, "1"},
+		{1_500_000, "1.5"},
+		{12_340_000, "12.34"},
+		{-1, "-0.000001"},
+		{-3 * OneUSDC, "-3"},
+		{MaxUSDC, "1152921504606.846975"},
+	} {
+		if s := tc.amount.String(); s != tc.expected {
+			t.Errorf("USDC(%d).String(): expected %q, got %q", int64(tc.amount), tc.expected, s)
+		}
+		if amount, err := ParseUSDC(tc.expected); err != nil || amount != tc.amount {
+			t.Errorf("ParseUSDC(%q): expected %d, got %d, %v", tc.expected, int64(tc.amount), amount, err)
+		}
+	}
+}
+
+func TestUSDCJSON(t *testing.T) {
+	type params struct {
+		Amount USDC `json:"amount"`
+	}
+
+	for _, amount := range []USDC{0, 1, 1_500_000, -2_250_000, MaxUSDC} {
+		b, err := json.Marshal(params{Amount: amount})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var p params
+		if err := json.Unmarshal(b, &p); err != nil {
+			t.Fatalf("unmarshal %s: %v", b, err)
+		}
+		if p.Amount != amount {
+			t.Errorf("round trip of %s: expected %d, got %d", b, int64(amount), int64(p.Amount))
+		}
+	}
+
+	var p params
+	if err := json.Unmarshal([]byte(`{"amount":"500000"}`), &p); err != nil || p.Amount != 500_000*OneUSDC {
+		t.Errorf(`expected "500000" to be 500000 USDC, got %d, %v`, int64(p.Amount), err)
+	}
+	// numbers are ambiguous, they could be USDC or have 6 implied decimals
+	for _, data := range []string{`{"amount":500000}`, `{"amount":1.5}`, `{"amount":"1.0000001"}`, `{"amount":"abc"}`} {
+		if err := json.Unmarshal([]byte(data), &p); err == nil {
+			t.Errorf("unmarshal %s: expected an error", data)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/elliottech/lighter-go/signer"
+	"github.com/elliottech/lighter-go/types/txtypes"
+	schnorr "github.com/elliottech/poseidon_crypto/signature/schnorr"
+)
+
+// The golden vectors in testdata/vectors are shared with the TypeScript & Swift wrappers, see testdata/vectors/README.md.
+// Run `go test ./types -run TestGoldenVectors -update` to regenerate them after an intended change.
+var updateVectors = flag.Bool("update", false, "regenerate the golden vectors in testdata/vectors")
+
+const vectorsDir = "../testdata/vectors"
+
+const (
+	vectorPrivateKey      = "0x0102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728"
+	vectorOtherPrivateKey = "0x28272625242322211f1e1d1c1b1a191817161514131211100f0e0d0c0b0a09080706050403020100"
+	vectorChainId         = 304
+
+	vectorAccountIndex int64 = 100
+	vectorApiKeyIndex  uint8 = 3
+	vectorNonce        int64 = 42
+	vectorExpiredAt    int64 = 1767225600000 // 2026-01-01 00:00:00 UTC
+	vectorOrderExpiry  int64 = 1769817600000 // 2026-01-31 00:00:00 UTC
+)
+
+type vectorOps struct {
+	AccountIndex int64 `json:"account_index"`
+	ApiKeyIndex  uint8 `json:"api_key_index"`
+	Nonce        int64 `json:"nonce"`
+	ExpiredAt    int64 `json:"expired_at"`
+}
+
+type vector struct {
+	Name           string          `json:"name"`
+	TxType         uint8           `json:"tx_type"`
+	PrivateKey     string          `json:"private_key"`
+	PublicKey      string          `json:"public_key"`
+	ChainId        uint32          `json:"chain_id"`
+	Ops            vectorOps       `json:"ops"`
+	Request        json.RawMessage `json:"request"`
+	Hash           string          `json:"hash"`
+	TxInfo         string          `json:"tx_info"`
+	Signature      string          `json:"signature"`
+	SignatureValid bool            `json:"signature_valid"`
+}
+
+type vectorCase struct {
+	name    string
+	request any // nil for create_sub_account, which has no request
+	// signedBy is the key used for the signature stored in the vector. If it's not the tx key, the signature is invalid.
+	signedBy string
+}
+
+// construct signs the tx of the request
+func (vc vectorCase) construct(key signer.Signer, chainId uint32, ops *TransactOpts) (txtypes.TxInfo, error) {
+	switch req := vc.request.(type) {
+	case nil:
+		return ConstructCreateSubAccountTx(key, chainId, ops)
+	case *ChangePubKeyReq:
+		return ConstructChangePubKeyTx(key, chainId, req, ops)
+	case *CreatePublicPoolTxReq:
+		return ConstructCreatePublicPoolTx(key, chainId, req, ops)
+	case *UpdatePublicPoolTxReq:
+		return ConstructUpdatePublicPoolTx(key, chainId, req, ops)
+	case *TransferTxReq:
+		return ConstructTransferTx(key, chainId, req, ops)
+	case *WithdrawTxReq:
+		return ConstructWithdrawTx(key, chainId, req, ops)
+	case *CreateOrderTxReq:
+		return ConstructCreateOrderTx(key, chainId, req, ops)
+	case *CreateGroupedOrdersTxReq:
+		return ConstructL2CreateGroupedOrdersTx(key, chainId, req, ops)
+	case *CancelOrderTxReq:
+		return ConstructL2CancelOrderTx(key, chainId, req, ops)
+	case *CancelAllOrdersTxReq:
+		return ConstructL2CancelAllOrdersTx(key, chainId, req, ops)
+	case *ModifyOrderTxReq:
+		return ConstructL2ModifyOrderTx(key, chainId, req, ops)
+	case *MintSharesTxReq:
+		return ConstructMintSharesTx(key, chainId, req, ops)
+	case *BurnSharesTxReq:
+		return ConstructBurnSharesTx(key, chainId, req, ops)
+	case *UpdateLeverageTxReq:
+		return ConstructUpdateLeverageTx(key, chainId, req, ops)
+	case *UpdateMarginTxReq:
+		return ConstructUpdateMarginTx(key, chainId, req, ops)
+	default:
+		return nil, fmt.Errorf("no constructor for a %T request", req)
+	}
+}
+
+func mustKeyManager(t testing.TB, privateKey string) signer.KeyManager {
+	t.Helper()
+
+	b, err := hex.DecodeString(privateKey[2:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := signer.NewKeyManager(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func vectorCases(t testing.TB) []vectorCase {
+	otherKey := mustKeyManager(t, vectorOtherPrivateKey)
+
+	var memo [32]byte
+	copy(memo[:], "lighter-go golden vector memo 01")
+
+	entry := &CreateOrderTxReq{
+		MarketIndex:      1,
+		ClientOrderIndex: 0,
+		BaseAmount:       1000,
+		Price:            350000,
+		IsAsk:            0,
+		Type:             txtypes.LimitOrder,
+		TimeInForce:      txtypes.GoodTillTime,
+		OrderExpiry:      vectorOrderExpiry,
+	}
+	stopLoss := &CreateOrderTxReq{
+		MarketIndex:  1,
+		BaseAmount:   1000,
+		Price:        330000,
+		IsAsk:        1,
+		Type:         txtypes.StopLossOrder,
+		TimeInForce:  txtypes.ImmediateOrCancel,
+		TriggerPrice: 335000,
+		OrderExpiry:  vectorOrderExpiry,
+	}
+	grouped, err := NewOTO(entry, stopLoss)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	transfer := &TransferTxReq{ToAccountIndex: 2000, USDCAmount: 25*OneUSDC + OneUSDC/2, Fee: 0, Memo: memo}
+
+	return []vectorCase{
+		{
+			name:    "change_pub_key",
+			request: &ChangePubKeyReq{PubKey: otherKey.PubKeyBytes()},
+		},
+		{
+			name:    "create_sub_account",
+			request: nil,
+		},
+		{
+			name:    "create_public_pool",
+			request: &CreatePublicPoolTxReq{OperatorFee: 100_000, InitialTotalShares: 2 * txtypes.MinInitialTotalShares, MinOperatorShareRate: 500},
+		},
+		{
+			name:    "update_public_pool",
+			request: &UpdatePublicPoolTxReq{PublicPoolIndex: 1000, Status: 1, OperatorFee: 50_000, MinOperatorShareRate: 1000},
+		},
+		{
+			name:    "transfer",
+			request: transfer,
+		},
+		{
+			name:     "transfer_invalid_signature",
+			request:  transfer,
+			signedBy: vectorOtherPrivateKey,
+		},
+		{
+			name:    "withdraw",
+			request: &WithdrawTxReq{USDCAmount: 100 * OneUSDC},
+		},
+		{
+			name:    "create_order",
+			request: entry,
+		},
+		{
+			name:    "create_grouped_orders",
+			request: grouped,
+		},
+		{
+			name:    "cancel_order",
+			request: &CancelOrderTxReq{MarketIndex: 1, Index: 12345},
+		},
+		{
+			name:    "cancel_all_orders",
+			request: &CancelAllOrdersTxReq{TimeInForce: txtypes.ScheduledCancelAll, Time: vectorExpiredAt + txtypes.MinOrderCancelAllPeriod},
+		},
+		{
+			name:    "modify_order",
+			request: &ModifyOrderTxReq{MarketIndex: 1, Index: 12345, BaseAmount: 2000, Price: 351000},
+		},
+		{
+			name:    "mint_shares",
+			request: &MintSharesTxReq{PublicPoolIndex: 1000, ShareAmount: 5000},
+		},
+		{
+			name:    "burn_shares",
+			request: &BurnSharesTxReq{PublicPoolIndex: 1000, ShareAmount: 5000},
+		},
+		{
+			name:    "update_leverage",
+			request: &UpdateLeverageTxReq{MarketIndex: 1, InitialMarginFraction: 500, MarginMode: txtypes.IsolatedMargin},
+		},
+		{
+			name:    "update_margin",
+			request: &UpdateMarginTxReq{MarketIndex: 1, USDCAmount: 10 * OneUSDC, Direction: 1},
+		},
+	}
+}
+
+func vectorTransactOpts() *TransactOpts {
+	accountIndex, apiKeyIndex, nonce := vectorAccountIndex, vectorApiKeyIndex, vectorNonce
+	return &TransactOpts{
+		FromAccountIndex: &accountIndex,
+		ApiKeyIndex:      &apiKeyIndex,
+		ExpiredAt:        vectorExpiredAt,
+		Nonce:            &nonce,
+	}
+}
+
+// txInfoWithoutSig drops the signature from a tx info JSON, as signatures are randomized
+func txInfoWithoutSig(t *testing.T, txInfo string) map[string]any {
+	t.Helper()
+
+	ret := make(map[string]any)
+	decoder := json.NewDecoder(strings.NewReader(txInfo))
+	decoder.UseNumber()
+	if err := decoder.Decode(&ret); err != nil {
+		t.Fatalf("invalid tx info %s. err: %v", txInfo, err)
+	}
+	delete(ret, "Sig")
+	return ret
+}
+
+func TestGoldenVectors(t *testing.T) {
+	key := mustKeyManager(t, vectorPrivateKey)
+	pubKey := key.PubKeyBytes()
+
+	for _, vc := range vectorCases(t) {
+		t.Run(vc.name, func(t *testing.T) {
+			tx, err := vc.construct(key, vectorChainId, vectorTransactOpts())
+			if err != nil {
+				t.Fatalf("failed to construct tx. err: %v", err)
+			}
+			request, err := json.Marshal(vc.request)
+			if err != nil {
+				t.Fatal(err)
+			}
+			txInfo, err := tx.GetTxInfo()
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, err := hex.DecodeString(tx.GetTxHash())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// a fresh signature always verifies, but is different on every run
+			var freshSig struct{ Sig []byte }
+			if err := json.Unmarshal([]byte(txInfo), &freshSig); err != nil {
+				t.Fatal(err)
+			}
+			if err := schnorr.Validate(pubKey[:], hash, freshSig.Sig); err != nil {
+				t.Fatalf("fresh signature is invalid. err: %v", err)
+			}
+
+			path := filepath.Join(vectorsDir, vc.name+".json")
+			if *updateVectors {
+				writeVector(t, path, vc, key, tx, request, hash)
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read vector, run with -update to generate it. err: %v", err)
+			}
+			var v vector
+			if err := json.Unmarshal(data, &v); err != nil {
+				t.Fatal(err)
+			}
+
+			if v.Name != vc.name || v.ChainId != vectorChainId || v.PrivateKey != vectorPrivateKey {
+				t.Fatalf("vector %s doesn't match the test case", path)
+			}
+			if v.Ops != (vectorOps{AccountIndex: vectorAccountIndex, ApiKeyIndex: vectorApiKeyIndex, Nonce: vectorNonce, ExpiredAt: vectorExpiredAt}) {
+				t.Fatalf("ops %+v don't match the test case", v.Ops)
+			}
+			if v.PublicKey != "0x"+hex.EncodeToString(pubKey[:]) {
+				t.Fatalf("public key mismatch. expected: %s got: 0x%x", v.PublicKey, pubKey)
+			}
+			if v.TxType != tx.GetTxType() {
+				t.Fatalf("tx type mismatch. expected: %d got: %d", v.TxType, tx.GetTxType())
+			}
+
+			var expectedRequest, gotRequest any
+			if err := json.Unmarshal(v.Request, &expectedRequest); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal(request, &gotRequest); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(expectedRequest, gotRequest) {
+				t.Fatalf("request mismatch.\nexpected: %s\ngot:      %s", v.Request, request)
+			}
+
+			if v.Hash != tx.GetTxHash() {
+				t.Fatalf("hash mismatch. expected: %s got: %s", v.Hash, tx.GetTxHash())
+			}
+			if !reflect.DeepEqual(txInfoWithoutSig(t, v.TxInfo), txInfoWithoutSig(t, txInfo)) {
+				t.Fatalf("tx info mismatch.\nexpected: %s\ngot:      %s", v.TxInfo, txInfo)
+			}
+
+			sig, err := hex.DecodeString(v.Signature)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var storedSig struct{ Sig []byte }
+			if err := json.Unmarshal([]byte(v.TxInfo), &storedSig); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(sig, storedSig.Sig) {
+				t.Fatalf("signature doesn't match the Sig of the tx info")
+			}
+			if valid := schnorr.Validate(pubKey[:], hash, sig) == nil; valid != v.SignatureValid {
+				t.Fatalf("signature verification mismatch. expected: %v got: %v", v.SignatureValid, valid)
+			}
+		})
+	}
+}
+
+func writeVector(t *testing.T, path string, vc vectorCase, key signer.KeyManager, tx txtypes.TxInfo, request []byte, hash []byte) {
+	t.Helper()
+
+	// the stored tx info carries the stored signature, which is made by signedBy for negative vectors
+	signedBy := key
+	if vc.signedBy != "" {
+		signedBy = mustKeyManager(t, vc.signedBy)
+	}
+	sig, err := signedBy.Sign(hash, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	reflect.ValueOf(tx).Elem().FieldByName("Sig").SetBytes(sig)
+	storedTxInfo, err := tx.GetTxInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pubKey := key.PubKeyBytes()
+	v := vector{
+		Name:       vc.name,
+		TxType:     tx.GetTxType(),
+		PrivateKey: vectorPrivateKey,
+		PublicKey:  "0x" + hex.EncodeToString(pubKey[:]),
+		ChainId:    vectorChainId,
+		Ops: vectorOps{
+			AccountIndex: vectorAccountIndex,
+			ApiKeyIndex:  vectorApiKeyIndex,
+			Nonce:        vectorNonce,
+			ExpiredAt:    vectorExpiredAt,
+		},
+		Request:        request,
+		Hash:           tx.GetTxHash(),
+		TxInfo:         storedTxInfo,
+		Signature:      hex.EncodeToString(sig),
+		SignatureValid: vc.signedBy == "",
+	}
+
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}